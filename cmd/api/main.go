@@ -3,17 +3,25 @@ package main
 import (
 	"context"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"github.com/alifdwt/techtest-indico-be/internal/apperror"
 	"github.com/alifdwt/techtest-indico-be/internal/config"
+	"github.com/alifdwt/techtest-indico-be/internal/eventsink"
+	"github.com/alifdwt/techtest-indico-be/internal/graphqlapi"
+	"github.com/alifdwt/techtest-indico-be/internal/grpcapi"
 	"github.com/alifdwt/techtest-indico-be/internal/handler"
+	"github.com/alifdwt/techtest-indico-be/internal/middleware"
 	"github.com/alifdwt/techtest-indico-be/internal/repository"
 	"github.com/alifdwt/techtest-indico-be/internal/routes"
 	"github.com/alifdwt/techtest-indico-be/internal/service"
+	"github.com/alifdwt/techtest-indico-be/internal/storage"
+	"github.com/alifdwt/techtest-indico-be/internal/util"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -36,6 +44,8 @@ import (
 // @license.name Apache 2.0
 // @license.url http://www.apache.org/licenses/LICENSE-2.0.html
 
+// @BasePath /api/v1
+
 // @securityDefinitions.apikey BearerAuth
 // @in header
 // @name Authorization
@@ -61,24 +71,73 @@ func main() {
 
 	repo := repository.New(connPool)
 
+	dateParser, err := util.NewDateParser(cfg.Date.Formats, cfg.Date.Timezone)
+	if err != nil {
+		log.Fatal("invalid date config: ", err)
+	}
+
 	authService := service.NewAuthService()
-	voucherService := service.NewVoucherService(repo)
+	webhookService, err := service.NewWebhookService(connPool, repo, cfg.Webhook)
+	if err != nil {
+		log.Fatal("invalid webhook config: ", err)
+	}
+	voucherService := service.NewVoucherService(connPool, repo, cfg.Import, cfg.Concurrency, dateParser)
+	importMappingService := service.NewImportMappingService(repo)
+
+	storages, err := storage.NewBackends(cfg.Export)
+	if err != nil {
+		log.Fatal("invalid export storage config: ", err)
+	}
+	scheduledExportService := service.NewScheduledExportService(repo, voucherService, storages, dateParser.Location(), cfg.Export)
+	if cfg.Export.SchedulerEnabled {
+		if err := scheduledExportService.Start(ctx); err != nil {
+			log.Fatal("cannot start export scheduler: ", err)
+		}
+	}
+	if cfg.Webhook.DispatcherEnabled {
+		webhookService.Start()
+	}
+
+	sinks, err := eventsink.NewSinks(cfg.Outbox, webhookService)
+	if err != nil {
+		log.Fatal("invalid outbox config: ", err)
+	}
+	outboxRelay := service.NewOutboxRelay(connPool, repo, cfg.Outbox, sinks)
+	if cfg.Outbox.RelayEnabled {
+		outboxRelay.Start()
+	}
 
 	authHandler := handler.NewAuthHandler(authService)
 	voucherHandler := handler.NewVoucherHandler(voucherService)
+	importMappingHandler := handler.NewImportMappingHandler(importMappingService)
+	scheduledExportHandler := handler.NewScheduledExportHandler(scheduledExportService)
+	graphQLHandler := handler.NewGraphQLHandler(graphqlapi.NewSchema(voucherService))
+	webhookHandler := handler.NewWebhookHandler(webhookService)
 
 	router := gin.Default()
 
 	config := cors.DefaultConfig()
 	config.AllowOrigins = []string{"*"}
-	config.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
-	config.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization"}
+	config.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	config.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Accept-Language", "Authorization", "If-Match", "If-None-Match", middleware.RequestIDHeader}
+	config.ExposeHeaders = []string{"Link", "Content-Language", "ETag", "Deprecation", "Sunset", middleware.RequestIDHeader}
 	router.Use(cors.New(config))
-
-	routes.SetupAuthRoutes(router, authHandler)
-	routes.SetupVoucherRoutes(router, voucherHandler)
+	router.Use(middleware.RequestID(), middleware.ErrorHandler())
+
+	routes.SetupAPIRoutes(router, routes.V1Handlers{
+		Auth:            authHandler,
+		Voucher:         voucherHandler,
+		ImportMapping:   importMappingHandler,
+		ScheduledExport: scheduledExportHandler,
+		GraphQL:         graphQLHandler,
+		Webhook:         webhookHandler,
+	}, cfg.API)
 	routes.SetupHealthRoutes(router)
 
+	router.NoRoute(func(ctx *gin.Context) {
+		ctx.Error(apperror.NotFound("route_not_found", "no route for %s %s", ctx.Request.Method, ctx.Request.URL.Path))
+	})
+
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	// 1. Buat object http.Server secara eksplisit
@@ -94,6 +153,22 @@ func main() {
 		}
 	}()
 
+	var grpcServer *grpcapi.Server
+	if cfg.GRPC.Enabled {
+		listener, err := net.Listen("tcp", ":"+cfg.GRPC.Port)
+		if err != nil {
+			log.Fatal("cannot listen for gRPC: ", err)
+		}
+
+		grpcServer = grpcapi.NewServer(voucherService)
+		go func() {
+			log.Printf("gRPC server starting on port %s", cfg.GRPC.Port)
+			if err := grpcServer.Serve(listener); err != nil {
+				log.Fatalf("grpc serve: %s\n", err)
+			}
+		}()
+	}
+
 	<-ctx.Done()
 
 	stop()
@@ -106,5 +181,27 @@ func main() {
 		log.Fatal("Server forced to shutdown: ", err)
 	}
 
+	if grpcServer != nil {
+		if err := grpcServer.Stop(shutdownCtx); err != nil {
+			log.Println("gRPC server forced to stop: ", err)
+		}
+	}
+
+	// Give running exports a little longer to finish before cancelling them.
+	exportCtx, cancelExports := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancelExports()
+
+	if err := scheduledExportService.Stop(exportCtx); err != nil {
+		log.Println("Scheduled exports cancelled: ", err)
+	}
+
+	if err := outboxRelay.Stop(exportCtx); err != nil {
+		log.Println("Outbox relay cancelled: ", err)
+	}
+
+	if err := webhookService.Stop(exportCtx); err != nil {
+		log.Println("Webhook deliveries cancelled: ", err)
+	}
+
 	log.Println("Server exiting")
 }
@@ -0,0 +1,87 @@
+// Command webhookstub is a local webhook receiver for trying out and
+// debugging webhook subscriptions. It checks the signature and timestamp of
+// every delivery, logs the event and answers with a configurable status, so
+// retries and dead deliveries can be reproduced too.
+//
+//	go run ./cmd/webhookstub -secret whsec_... -status 500
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/alifdwt/techtest-indico-be/internal/service"
+)
+
+func main() {
+	addr := flag.String("addr", ":8090", "address to listen on")
+	secret := flag.String("secret", "", "subscription secret; signatures are not checked when empty")
+	status := flag.Int("status", http.StatusOK, "status code to answer with")
+	tolerance := flag.Duration("tolerance", 5*time.Minute, "maximum age of a delivery timestamp")
+	flag.Parse()
+
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "cannot read body", http.StatusBadRequest)
+			return
+		}
+
+		if *secret != "" {
+			if err := verify(r.Header, body, *secret, *tolerance); err != nil {
+				log.Printf("rejected delivery %s: %v", r.Header.Get(service.WebhookDeliveryHeader), err)
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+		}
+
+		log.Printf("%s event=%s delivery=%s\n%s",
+			r.Header.Get(service.WebhookEventHeader),
+			r.Header.Get(service.WebhookEventIDHeader),
+			r.Header.Get(service.WebhookDeliveryHeader),
+			body,
+		)
+
+		w.WriteHeader(*status)
+		fmt.Fprintf(w, "received with status %d\n", *status)
+	})
+
+	log.Printf("webhook stub listening on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, nil))
+}
+
+// verify checks a delivery the way a receiver should: the timestamp must be
+// recent, and the signature must be the HMAC-SHA256 of "<timestamp>.<body>".
+func verify(header http.Header, body []byte, secret string, tolerance time.Duration) error {
+	timestamp, err := strconv.ParseInt(header.Get(service.WebhookTimestampHeader), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid %s header", service.WebhookTimestampHeader)
+	}
+	if age := time.Since(time.Unix(timestamp, 0)); age > tolerance || age < -tolerance {
+		return fmt.Errorf("timestamp outside the tolerance of %s", tolerance)
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(expected), []byte(header.Get(service.WebhookSignatureHeader))) {
+		return fmt.Errorf("signature mismatch")
+	}
+
+	return nil
+}
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS voucher_import_failed_rows;
+DROP TABLE IF EXISTS voucher_imports;
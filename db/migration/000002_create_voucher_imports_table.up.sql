@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS voucher_imports (
+    -- id, file_name, headers, success_count, failed_count, created_at
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    file_name VARCHAR(255) NOT NULL,
+    headers TEXT[] NOT NULL,
+    success_count INTEGER NOT NULL DEFAULT 0,
+    failed_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS voucher_import_failed_rows (
+    -- id, import_id, row_number, record, reason
+    id BIGSERIAL PRIMARY KEY,
+    import_id uuid NOT NULL REFERENCES voucher_imports(id) ON DELETE CASCADE,
+    row_number INTEGER NOT NULL,
+    record TEXT[] NOT NULL,
+    reason TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_voucher_import_failed_rows_import_id ON voucher_import_failed_rows(import_id);
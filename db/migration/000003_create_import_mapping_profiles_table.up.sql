@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS import_mapping_profiles (
+    -- id, name, mapping, created_at, updated_at
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) UNIQUE NOT NULL,
+    mapping JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS scheduled_export_runs;
+DROP TABLE IF EXISTS scheduled_exports;
\ No newline at end of file
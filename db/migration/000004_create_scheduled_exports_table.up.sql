@@ -0,0 +1,44 @@
+-- filter holds the list filters, a JSON object with the query parameters of
+-- GET /vouchers/export, and sort the sort spec, applied as in an export made
+-- on demand.
+CREATE TABLE IF NOT EXISTS scheduled_exports (
+    -- id, name, cron_expression, format, profile, sort_by, sort_order, columns, storage, path_prefix, enabled, created_at, updated_at, filter, sort
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) UNIQUE NOT NULL,
+    cron_expression VARCHAR(100) NOT NULL,
+    format VARCHAR(10) NOT NULL DEFAULT 'csv',
+    profile VARCHAR(10) NOT NULL DEFAULT 'display',
+    sort_by VARCHAR(50) NOT NULL DEFAULT '',
+    sort_order VARCHAR(4) NOT NULL DEFAULT '',
+    columns VARCHAR(255) NOT NULL DEFAULT '',
+    storage VARCHAR(20) NOT NULL,
+    path_prefix VARCHAR(255) NOT NULL DEFAULT '',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    filter JSONB NOT NULL DEFAULT '{}',
+    sort VARCHAR(255) NOT NULL DEFAULT ''
+);
+
+-- The instance running an export renews the run's lease while it works on
+-- it. A run still running after its lease ran out was interrupted.
+-- scheduled_for is the schedule slot of a scheduled run, taken by one
+-- instance only; it is NULL for manual runs.
+CREATE TABLE IF NOT EXISTS scheduled_export_runs (
+    -- id, scheduled_export_id, triggered_by, status, location, size_bytes, error, started_at, finished_at, lease_expires_at, scheduled_for
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    scheduled_export_id uuid NOT NULL REFERENCES scheduled_exports(id) ON DELETE CASCADE,
+    triggered_by VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'running',
+    location TEXT NOT NULL DEFAULT '',
+    size_bytes BIGINT NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMP,
+    lease_expires_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    scheduled_for TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_export_runs_export_id ON scheduled_export_runs(scheduled_export_id, started_at DESC);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_scheduled_export_runs_slot ON scheduled_export_runs(scheduled_export_id, scheduled_for);
+CREATE INDEX IF NOT EXISTS idx_scheduled_export_runs_running ON scheduled_export_runs(lease_expires_at) WHERE status = 'running';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_vouchers_updated_at_id;
+DROP INDEX IF EXISTS idx_vouchers_created_at_id;
+DROP INDEX IF EXISTS idx_vouchers_discount_percent_id;
+DROP INDEX IF EXISTS idx_vouchers_expiry_date_id;
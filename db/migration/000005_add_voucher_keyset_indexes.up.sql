@@ -0,0 +1,6 @@
+-- Composite indexes matching the list ordering (sort column, then id), so
+-- keyset pages are read straight from the index.
+CREATE INDEX IF NOT EXISTS idx_vouchers_expiry_date_id ON vouchers(expiry_date, id);
+CREATE INDEX IF NOT EXISTS idx_vouchers_discount_percent_id ON vouchers(discount_percent, id);
+CREATE INDEX IF NOT EXISTS idx_vouchers_created_at_id ON vouchers(created_at, id);
+CREATE INDEX IF NOT EXISTS idx_vouchers_updated_at_id ON vouchers(updated_at, id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_vouchers_voucher_code_lower_pattern;
+DROP INDEX IF EXISTS idx_vouchers_voucher_code_trgm;
@@ -0,0 +1,7 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Trigram index for contains (ILIKE '%term%') and fuzzy (%) searches.
+CREATE INDEX IF NOT EXISTS idx_vouchers_voucher_code_trgm ON vouchers USING GIN (voucher_code gin_trgm_ops);
+
+-- Case-insensitive prefix searches and autocomplete, in index order.
+CREATE INDEX IF NOT EXISTS idx_vouchers_voucher_code_lower_pattern ON vouchers (lower(voucher_code) text_pattern_ops);
@@ -0,0 +1,3 @@
+-- Incremented on every update; exposed as the voucher's ETag so concurrent
+-- edits can be detected with If-Match.
+ALTER TABLE vouchers ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS voucher_expiry_notifications;
+DROP TABLE IF EXISTS webhook_delivery_attempts;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_subscriptions;
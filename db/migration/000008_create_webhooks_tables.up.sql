@@ -0,0 +1,67 @@
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    -- id, name, url, secret, events, enabled, created_at, updated_at
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) UNIQUE NOT NULL,
+    url TEXT NOT NULL,
+    secret VARCHAR(255) NOT NULL,
+    events TEXT[] NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- One row per event and subscription. status is pending until the event is
+-- delivered (succeeded) or the attempts run out (dead). Deliveries of a
+-- voucher to a subscription are sent one at a time, in the order they were
+-- created. A redelivery is a new delivery of the event of an earlier one;
+-- every other delivery is unique per subscription and event, so an event
+-- published again is not queued twice.
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    -- id, subscription_id, event_id, event_type, payload, status, attempts, next_attempt_at, last_error, created_at, completed_at, voucher_id, redelivery_of
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    subscription_id uuid NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    event_id uuid NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP,
+    voucher_id uuid NOT NULL,
+    redelivery_of uuid REFERENCES webhook_deliveries(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription_event ON webhook_deliveries(subscription_id, event_id) WHERE redelivery_of IS NULL;
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription_id ON webhook_deliveries(subscription_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_pending_voucher ON webhook_deliveries(subscription_id, voucher_id, created_at) WHERE status = 'pending';
+
+CREATE TABLE IF NOT EXISTS webhook_delivery_attempts (
+    -- id, delivery_id, attempt, status_code (0 without a response), error, response_body, duration_ms, attempted_at
+    id BIGSERIAL PRIMARY KEY,
+    delivery_id uuid NOT NULL REFERENCES webhook_deliveries(id) ON DELETE CASCADE,
+    attempt INTEGER NOT NULL,
+    status_code INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    response_body TEXT NOT NULL DEFAULT '',
+    duration_ms INTEGER NOT NULL,
+    attempted_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_attempts_delivery_id ON webhook_delivery_attempts(delivery_id, attempt);
+
+-- The expiry date each voucher.expired event was sent for, so that every
+-- expiry is reported once, and again if the expiry date is moved.
+CREATE TABLE IF NOT EXISTS voucher_expiry_notifications (
+    -- voucher_id, expiry_date, notified_at
+    voucher_id uuid PRIMARY KEY REFERENCES vouchers(id) ON DELETE CASCADE,
+    expiry_date TIMESTAMPTZ NOT NULL,
+    notified_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Vouchers that expired before webhooks existed are not reported.
+INSERT INTO voucher_expiry_notifications (voucher_id, expiry_date)
+SELECT id, expiry_date FROM vouchers WHERE expiry_date < NOW()
+ON CONFLICT (voucher_id) DO NOTHING;
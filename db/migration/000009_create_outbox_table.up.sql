@@ -0,0 +1,22 @@
+-- Events written in the same transaction as the change they describe, and
+-- handed to the event sinks by the outbox relay. id gives the order of the
+-- events of each aggregate; published_at is set once every sink took one,
+-- and failed_at when the relay gave up on one, which is then skipped.
+CREATE TABLE IF NOT EXISTS outbox (
+    -- id, event_id, aggregate_type, aggregate_id, event_type, payload, attempts, next_attempt_at, last_error, created_at, published_at, failed_at
+    id BIGSERIAL PRIMARY KEY,
+    event_id uuid UNIQUE NOT NULL,
+    aggregate_type VARCHAR(50) NOT NULL,
+    aggregate_id uuid NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    published_at TIMESTAMP,
+    failed_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_unpublished ON outbox(aggregate_id, id) WHERE published_at IS NULL AND failed_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_published_at ON outbox(published_at) WHERE published_at IS NOT NULL;
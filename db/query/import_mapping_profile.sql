@@ -0,0 +1,31 @@
+-- name: CreateImportMappingProfile :one
+INSERT INTO import_mapping_profiles (
+    name,
+    mapping
+) VALUES (
+    $1, $2
+) RETURNING *;
+
+-- name: GetImportMappingProfileByID :one
+SELECT * FROM import_mapping_profiles WHERE id = $1 LIMIT 1;
+
+-- name: GetImportMappingProfileByName :one
+SELECT * FROM import_mapping_profiles WHERE name = $1 LIMIT 1;
+
+-- name: ListImportMappingProfiles :many
+SELECT * FROM import_mapping_profiles ORDER BY name ASC, id ASC
+LIMIT $1 OFFSET $2;
+
+-- name: CountImportMappingProfiles :one
+SELECT COUNT(*) FROM import_mapping_profiles;
+
+-- name: UpdateImportMappingProfile :one
+UPDATE import_mapping_profiles SET
+    name = $2,
+    mapping = $3,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteImportMappingProfile :exec
+DELETE FROM import_mapping_profiles WHERE id = $1;
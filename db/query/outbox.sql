@@ -0,0 +1,61 @@
+-- name: CreateOutboxEvent :exec
+INSERT INTO outbox (
+    event_id,
+    aggregate_type,
+    aggregate_id,
+    event_type,
+    payload
+) VALUES (
+    $1, $2, $3, $4, $5
+);
+
+-- name: ClaimOutboxEvents :many
+-- Picks the oldest unpublished event of each aggregate, when it is due, and
+-- moves its next attempt back by the lease so that other relays skip it
+-- while it is published. Later events of an aggregate wait until the ones
+-- before them are published or failed, which keeps them in order.
+UPDATE outbox SET
+    next_attempt_at = NOW() + sqlc.arg(lease)::interval
+WHERE id IN (
+    SELECT o.id FROM outbox o
+    WHERE o.published_at IS NULL
+      AND o.failed_at IS NULL
+      AND o.next_attempt_at <= NOW()
+      AND NOT EXISTS (
+          SELECT 1 FROM outbox earlier
+          WHERE earlier.aggregate_id = o.aggregate_id
+            AND earlier.published_at IS NULL
+            AND earlier.failed_at IS NULL
+            AND earlier.id < o.id
+      )
+    ORDER BY o.id ASC
+    LIMIT sqlc.arg(max_results)
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: MarkOutboxEventPublished :exec
+UPDATE outbox SET
+    published_at = NOW(),
+    last_error = ''
+WHERE id = $1;
+
+-- name: FailOutboxEvent :exec
+UPDATE outbox SET
+    attempts = attempts + 1,
+    next_attempt_at = sqlc.arg(next_attempt_at),
+    last_error = sqlc.arg(last_error)
+WHERE id = sqlc.arg(id);
+
+-- name: DeadLetterOutboxEvent :exec
+-- Gives up on an event: it is not published again, and later events of
+-- its aggregate go ahead.
+UPDATE outbox SET
+    attempts = attempts + 1,
+    failed_at = NOW(),
+    last_error = sqlc.arg(last_error)
+WHERE id = sqlc.arg(id);
+
+-- name: DeletePublishedOutboxEvents :execrows
+DELETE FROM outbox
+WHERE published_at < NOW() - sqlc.arg(retention)::interval;
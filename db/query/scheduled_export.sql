@@ -0,0 +1,99 @@
+-- name: CreateScheduledExport :one
+INSERT INTO scheduled_exports (
+    name,
+    cron_expression,
+    format,
+    profile,
+    filter,
+    sort,
+    sort_by,
+    sort_order,
+    columns,
+    storage,
+    path_prefix,
+    enabled
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+) RETURNING *;
+
+-- name: GetScheduledExportByID :one
+SELECT * FROM scheduled_exports WHERE id = $1 LIMIT 1;
+
+-- name: ListScheduledExports :many
+SELECT * FROM scheduled_exports ORDER BY name ASC, id ASC
+LIMIT $1 OFFSET $2;
+
+-- name: CountScheduledExports :one
+SELECT COUNT(*) FROM scheduled_exports;
+
+-- name: ListEnabledScheduledExports :many
+SELECT * FROM scheduled_exports WHERE enabled = TRUE ORDER BY name ASC;
+
+-- name: UpdateScheduledExport :one
+UPDATE scheduled_exports SET
+    name = $2,
+    cron_expression = $3,
+    format = $4,
+    profile = $5,
+    filter = $6,
+    sort = $7,
+    sort_by = $8,
+    sort_order = $9,
+    columns = $10,
+    storage = $11,
+    path_prefix = $12,
+    enabled = $13,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteScheduledExport :exec
+DELETE FROM scheduled_exports WHERE id = $1;
+
+-- name: CreateScheduledExportRun :one
+-- Records a run. A scheduled run takes its slot: when another instance
+-- already took it, nothing is recorded and no row is returned.
+INSERT INTO scheduled_export_runs (
+    scheduled_export_id,
+    triggered_by,
+    scheduled_for,
+    lease_expires_at
+) VALUES (
+    $1, $2, sqlc.narg(scheduled_for), NOW() + sqlc.arg(lease)::interval
+)
+ON CONFLICT (scheduled_export_id, scheduled_for) DO NOTHING
+RETURNING *;
+
+-- name: RenewScheduledExportRunLease :exec
+UPDATE scheduled_export_runs SET
+    lease_expires_at = NOW() + sqlc.arg(lease)::interval
+WHERE id = sqlc.arg(id) AND status = 'running';
+
+-- name: FinishScheduledExportRun :one
+UPDATE scheduled_export_runs SET
+    status = $2,
+    location = $3,
+    size_bytes = $4,
+    error = $5,
+    finished_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: FailExpiredScheduledExportRuns :exec
+-- Fails the runs whose lease ran out: the instance running them stopped
+-- before they finished.
+UPDATE scheduled_export_runs SET
+    status = 'failed',
+    error = @reason,
+    finished_at = NOW()
+WHERE status = 'running' AND lease_expires_at < NOW();
+
+-- name: ListScheduledExportRuns :many
+SELECT * FROM scheduled_export_runs
+WHERE scheduled_export_id = $1
+ORDER BY started_at DESC, id ASC
+LIMIT $2 OFFSET $3;
+
+-- name: CountScheduledExportRuns :one
+SELECT COUNT(*) FROM scheduled_export_runs
+WHERE scheduled_export_id = $1;
@@ -13,40 +13,72 @@ SELECT * FROM vouchers WHERE id = $1 LIMIT 1;
 -- name: GetVoucherByCode :one
 SELECT * FROM vouchers WHERE voucher_code = $1 LIMIT 1;
 
--- name: ListVouchers :many
+-- name: ListVouchersByIDs :many
 SELECT * FROM vouchers
-WHERE (sqlc.narg(search)::text IS NULL OR voucher_code ILIKE '%' || sqlc.narg(search) || '%')
-ORDER BY 
-    -- 1. DESCENDING SORTS
-    CASE WHEN sqlc.narg(sort_order)::text = 'desc' AND sqlc.narg(sort_by)::text = 'expiry_date' THEN expiry_date END DESC,
-    CASE WHEN sqlc.narg(sort_order)::text = 'desc' AND sqlc.narg(sort_by)::text = 'discount_percent' THEN discount_percent END DESC,
-    CASE WHEN sqlc.narg(sort_order)::text = 'desc' AND sqlc.narg(sort_by)::text = 'created_at' THEN created_at END DESC,
-    CASE WHEN sqlc.narg(sort_order)::text = 'desc' AND sqlc.narg(sort_by)::text = 'updated_at' THEN updated_at END DESC,
-
-    -- 2. ASCENDING SORTS
-    CASE WHEN sqlc.narg(sort_order)::text = 'asc' AND sqlc.narg(sort_by)::text = 'expiry_date' THEN expiry_date END ASC,
-    CASE WHEN sqlc.narg(sort_order)::text = 'asc' AND sqlc.narg(sort_by)::text = 'discount_percent' THEN discount_percent END ASC,
-    CASE WHEN sqlc.narg(sort_order)::text = 'asc' AND sqlc.narg(sort_by)::text = 'created_at' THEN created_at END ASC,
-    CASE WHEN sqlc.narg(sort_order)::text = 'asc' AND sqlc.narg(sort_by)::text = 'updated_at' THEN updated_at END ASC,
-
-    id ASC
-LIMIT $1 OFFSET $2;
+WHERE id = ANY(sqlc.arg(ids)::uuid[])
+ORDER BY created_at ASC, id ASC;
 
 -- name: UpdateVoucher :one
+-- expected_version, when set, makes the update apply only to that version.
 UPDATE vouchers SET
-    voucher_code = $2,
-    discount_percent = $3,
-    expiry_date = $4,
+    voucher_code = sqlc.arg(voucher_code),
+    discount_percent = sqlc.arg(discount_percent),
+    expiry_date = sqlc.arg(expiry_date),
+    version = version + 1,
     updated_at = NOW()
-WHERE id = $1
+WHERE id = sqlc.arg(id)
+  AND version = COALESCE(sqlc.narg(expected_version), version)
 RETURNING *;
 
--- name: DeleteVoucher :exec
-DELETE FROM vouchers WHERE id = $1;
+-- name: PatchVoucher :one
+UPDATE vouchers SET
+    voucher_code = COALESCE(sqlc.narg(voucher_code), voucher_code),
+    discount_percent = COALESCE(sqlc.narg(discount_percent), discount_percent),
+    expiry_date = COALESCE(sqlc.narg(expiry_date), expiry_date),
+    version = version + 1,
+    updated_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND version = COALESCE(sqlc.narg(expected_version), version)
+RETURNING *;
+
+-- name: DeleteVoucher :execrows
+DELETE FROM vouchers
+WHERE id = sqlc.arg(id)
+  AND version = COALESCE(sqlc.narg(expected_version), version);
+
+-- name: ExtendVoucherExpiry :one
+UPDATE vouchers SET
+    expiry_date = expiry_date + make_interval(days => sqlc.arg(days)::int),
+    version = version + 1,
+    updated_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND version = COALESCE(sqlc.narg(expected_version), version)
+RETURNING *;
 
--- name: CountVouchers :one
-SELECT COUNT(*) FROM vouchers
-WHERE (sqlc.narg(search)::text IS NULL OR voucher_code ILIKE '%' || sqlc.narg(search) || '%');
+-- name: AutocompleteVoucherCodes :many
+SELECT voucher_code FROM vouchers
+WHERE lower(voucher_code) LIKE sqlc.arg(pattern)
+ORDER BY lower(voucher_code) ASC
+LIMIT sqlc.arg(max_results);
 
--- name: GetAllVouchersForExport :many
-SELECT * FROM vouchers ORDER BY created_at DESC;
\ No newline at end of file
+-- name: ClaimExpiredVouchers :many
+-- Records the vouchers that expired since they were last reported and
+-- returns them. A voucher whose expiry date was moved is returned again
+-- when it expires anew; vouchers another instance just claimed are skipped.
+WITH claimed AS (
+    INSERT INTO voucher_expiry_notifications (voucher_id, expiry_date)
+    SELECT v.id, v.expiry_date FROM vouchers v
+    LEFT JOIN voucher_expiry_notifications n ON n.voucher_id = v.id
+    WHERE v.expiry_date < NOW()
+      AND (n.voucher_id IS NULL OR n.expiry_date <> v.expiry_date)
+    ORDER BY v.expiry_date ASC
+    LIMIT sqlc.arg(max_results)
+    ON CONFLICT (voucher_id) DO UPDATE SET
+        expiry_date = EXCLUDED.expiry_date,
+        notified_at = NOW()
+    WHERE voucher_expiry_notifications.expiry_date <> EXCLUDED.expiry_date
+    RETURNING voucher_id
+)
+SELECT vouchers.* FROM vouchers
+JOIN claimed ON claimed.voucher_id = vouchers.id
+ORDER BY vouchers.expiry_date ASC, vouchers.id ASC;
@@ -0,0 +1,27 @@
+-- name: CreateVoucherImport :one
+INSERT INTO voucher_imports (
+    file_name,
+    headers,
+    success_count,
+    failed_count
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetVoucherImportByID :one
+SELECT * FROM voucher_imports WHERE id = $1 LIMIT 1;
+
+-- name: CreateVoucherImportFailedRow :exec
+INSERT INTO voucher_import_failed_rows (
+    import_id,
+    row_number,
+    record,
+    reason
+) VALUES (
+    $1, $2, $3, $4
+);
+
+-- name: ListVoucherImportFailedRows :many
+SELECT * FROM voucher_import_failed_rows
+WHERE import_id = $1
+ORDER BY row_number ASC, id ASC;
@@ -0,0 +1,141 @@
+-- name: CreateWebhookSubscription :one
+INSERT INTO webhook_subscriptions (
+    name,
+    url,
+    secret,
+    events,
+    enabled
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetWebhookSubscriptionByID :one
+SELECT * FROM webhook_subscriptions WHERE id = $1 LIMIT 1;
+
+-- name: ListWebhookSubscriptions :many
+SELECT * FROM webhook_subscriptions ORDER BY name ASC, id ASC
+LIMIT $1 OFFSET $2;
+
+-- name: CountWebhookSubscriptions :one
+SELECT COUNT(*) FROM webhook_subscriptions;
+
+-- name: ListWebhookSubscriptionsForEvent :many
+SELECT * FROM webhook_subscriptions
+WHERE enabled = TRUE AND sqlc.arg(event_type)::text = ANY(events)
+ORDER BY created_at ASC, id ASC;
+
+-- name: UpdateWebhookSubscription :one
+-- secret, when null, keeps the current secret.
+UPDATE webhook_subscriptions SET
+    name = sqlc.arg(name),
+    url = sqlc.arg(url),
+    secret = COALESCE(sqlc.narg(secret), secret),
+    events = sqlc.arg(events),
+    enabled = sqlc.arg(enabled),
+    updated_at = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: DeleteWebhookSubscription :exec
+DELETE FROM webhook_subscriptions WHERE id = $1;
+
+-- name: CreateWebhookDelivery :exec
+-- Queues an event for a subscription, unless it already is: the outbox
+-- relay may publish an event more than once.
+INSERT INTO webhook_deliveries (
+    subscription_id,
+    event_id,
+    voucher_id,
+    event_type,
+    payload
+) VALUES (
+    $1, $2, $3, $4, $5
+) ON CONFLICT (subscription_id, event_id) WHERE redelivery_of IS NULL DO NOTHING;
+
+-- name: CreateWebhookRedelivery :one
+-- Queues the event of a delivery again, as a new delivery.
+INSERT INTO webhook_deliveries (
+    subscription_id,
+    event_id,
+    voucher_id,
+    event_type,
+    payload,
+    redelivery_of
+)
+SELECT subscription_id, event_id, voucher_id, event_type, payload, id
+FROM webhook_deliveries
+WHERE id = $1
+RETURNING *;
+
+-- name: GetWebhookDelivery :one
+SELECT * FROM webhook_deliveries
+WHERE id = $1 AND subscription_id = $2
+LIMIT 1;
+
+-- name: ListWebhookDeliveries :many
+SELECT * FROM webhook_deliveries
+WHERE subscription_id = sqlc.arg(subscription_id)
+  AND (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status))
+ORDER BY created_at DESC, id ASC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountWebhookDeliveries :one
+SELECT COUNT(*) FROM webhook_deliveries
+WHERE subscription_id = sqlc.arg(subscription_id)
+  AND (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status));
+
+-- name: ClaimDueWebhookDeliveries :many
+-- Picks the oldest pending delivery of each voucher to each enabled
+-- subscription, when it is due, and moves its next attempt back by the
+-- lease so that other instances skip it while it is sent. A delivery whose
+-- sender dies is picked up again when the lease runs out. Later deliveries
+-- of a voucher wait while an earlier one is retried, which keeps them in
+-- order.
+UPDATE webhook_deliveries SET
+    next_attempt_at = NOW() + sqlc.arg(lease)::interval
+WHERE id IN (
+    SELECT d.id FROM webhook_deliveries d
+    JOIN webhook_subscriptions s ON s.id = d.subscription_id
+    WHERE d.status = 'pending'
+      AND d.next_attempt_at <= NOW()
+      AND s.enabled = TRUE
+      AND NOT EXISTS (
+          SELECT 1 FROM webhook_deliveries earlier
+          WHERE earlier.subscription_id = d.subscription_id
+            AND earlier.voucher_id = d.voucher_id
+            AND earlier.status = 'pending'
+            AND (earlier.created_at, earlier.id) < (d.created_at, d.id)
+      )
+    ORDER BY d.next_attempt_at ASC
+    LIMIT sqlc.arg(max_results)
+    FOR UPDATE OF d SKIP LOCKED
+)
+RETURNING *;
+
+-- name: FinishWebhookDeliveryAttempt :exec
+-- status stays pending, with the time of the next attempt, when the
+-- delivery will be retried.
+UPDATE webhook_deliveries SET
+    status = sqlc.arg(status),
+    attempts = sqlc.arg(attempts),
+    next_attempt_at = sqlc.arg(next_attempt_at),
+    last_error = sqlc.arg(last_error),
+    completed_at = CASE WHEN sqlc.arg(status)::text = 'pending' THEN NULL ELSE NOW() END
+WHERE id = sqlc.arg(id);
+
+-- name: CreateWebhookDeliveryAttempt :exec
+INSERT INTO webhook_delivery_attempts (
+    delivery_id,
+    attempt,
+    status_code,
+    error,
+    response_body,
+    duration_ms
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+);
+
+-- name: ListWebhookDeliveryAttempts :many
+SELECT * FROM webhook_delivery_attempts
+WHERE delivery_id = $1
+ORDER BY attempt ASC, id ASC;
@@ -24,9 +24,14 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/login": {
+        "/graphql": {
             "post": {
-                "description": "Authenticate user and return token",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Query vouchers and voucher statistics for the admin dashboard. The schema is in internal/graphqlapi/schema.graphql and can be introspected. As GraphQL responses go, errors of single fields are reported in the errors list of a 200 response; only a malformed request body gets a problem response.",
                 "consumes": [
                     "application/json"
                 ],
@@ -34,17 +39,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "graphql"
                 ],
-                "summary": "User login",
+                "summary": "Run a GraphQL query",
                 "parameters": [
                     {
-                        "description": "Login request",
-                        "name": "login",
+                        "description": "GraphQL request",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.LoginRequest"
+                            "$ref": "#/definitions/dto.GraphQLRequest"
                         }
                     }
                 ],
@@ -52,54 +57,39 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/util.Response"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/dto.LoginResponse"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/dto.GraphQLResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/util.Response"
+                            "$ref": "#/definitions/util.Problem"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/util.Response"
+                            "$ref": "#/definitions/util.Problem"
                         }
                     }
                 }
             }
         },
-        "/vouchers": {
+        "/import-mappings": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve a list of vouchers",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retrieve saved import mapping profiles ordered by name. Links to the neighbouring pages are sent in the Link header.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "vouchers"
+                    "import-mappings"
                 ],
-                "summary": "List vouchers",
+                "summary": "List import mapping profiles",
                 "parameters": [
                     {
                         "type": "integer",
@@ -110,30 +100,10 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "default": 10,
+                        "default": 20,
                         "description": "Number of items per page",
                         "name": "limit",
                         "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Search term",
-                        "name": "search",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "default": "expiry_date",
-                        "description": "Sort by field",
-                        "name": "sort_by",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "default": "asc",
-                        "description": "Sort order (asc or desc)",
-                        "name": "sort_order",
-                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -142,7 +112,7 @@ const docTemplate = `{
                         "schema": {
                             "allOf": [
                                 {
-                                    "$ref": "#/definitions/util.Response"
+                                    "$ref": "#/definitions/util.PaginatedResponse"
                                 },
                                 {
                                     "type": "object",
@@ -150,7 +120,7 @@ const docTemplate = `{
                                         "data": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/dto.VoucherResponse"
+                                                "$ref": "#/definitions/dto.ImportMappingProfileResponse"
                                             }
                                         }
                                     }
@@ -161,13 +131,13 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/util.Response"
+                            "$ref": "#/definitions/util.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/util.Response"
+                            "$ref": "#/definitions/util.Problem"
                         }
                     }
                 }
@@ -178,7 +148,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new voucher with the provided details",
+                "description": "Save a mapping from file headers (e.g. \"Kode Voucher\") to voucher fields, selectable per upload",
                 "consumes": [
                     "application/json"
                 ],
@@ -186,17 +156,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "vouchers"
+                    "import-mappings"
                 ],
-                "summary": "Create a new voucher",
+                "summary": "Create an import mapping profile",
                 "parameters": [
                     {
-                        "description": "Voucher data",
-                        "name": "voucher",
+                        "description": "Mapping profile",
+                        "name": "profile",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CreateVoucherRequest"
+                            "$ref": "#/definitions/dto.ImportMappingProfileRequest"
                         }
                     }
                 ],
@@ -212,7 +182,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.VoucherResponse"
+                                            "$ref": "#/definitions/dto.ImportMappingProfileResponse"
                                         }
                                     }
                                 }
@@ -222,73 +192,45 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/util.Response"
+                            "$ref": "#/definitions/util.Problem"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/util.Response"
-                        }
-                    }
-                }
-            }
-        },
-        "/vouchers/export": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Export all vouchers as a CSV file",
-                "produces": [
-                    "text/csv"
-                ],
-                "tags": [
-                    "vouchers"
-                ],
-                "summary": "Export vouchers to CSV",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
-                            "type": "file"
+                            "$ref": "#/definitions/util.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/util.Response"
+                            "$ref": "#/definitions/util.Problem"
                         }
                     }
                 }
             }
         },
-        "/vouchers/upload-csv": {
-            "post": {
+        "/import-mappings/{id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Upload vouchers from a CSV file",
-                "consumes": [
-                    "multipart/form-data"
-                ],
+                "description": "Get a specific import mapping profile by its ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "vouchers"
+                    "import-mappings"
                 ],
-                "summary": "Upload vouchers from CSV",
+                "summary": "Get import mapping profile by ID",
                 "parameters": [
                     {
-                        "type": "file",
-                        "description": "CSV file",
-                        "name": "file",
-                        "in": "formData",
+                        "type": "string",
+                        "description": "Mapping profile ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -304,7 +246,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.CSVUploadResponse"
+                                            "$ref": "#/definitions/dto.ImportMappingProfileResponse"
                                         }
                                     }
                                 }
@@ -314,40 +256,56 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/util.Response"
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/util.Response"
+                            "$ref": "#/definitions/util.Problem"
                         }
                     }
                 }
-            }
-        },
-        "/vouchers/{id}": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a specific voucher by its ID",
+                "description": "Replace the name and mapping of an existing profile",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "vouchers"
+                    "import-mappings"
                 ],
-                "summary": "Get voucher by ID",
+                "summary": "Update an import mapping profile",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Voucher ID",
+                        "description": "Mapping profile ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Mapping profile",
+                        "name": "profile",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ImportMappingProfileRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -362,7 +320,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.VoucherResponse"
+                                            "$ref": "#/definitions/dto.ImportMappingProfileResponse"
                                         }
                                     }
                                 }
@@ -372,55 +330,101 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/util.Response"
+                            "$ref": "#/definitions/util.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/util.Response"
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/util.Response"
+                            "$ref": "#/definitions/util.Problem"
                         }
                     }
                 }
             },
-            "put": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update an existing voucher with new details",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Delete an import mapping profile by its ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "vouchers"
+                    "import-mappings"
                 ],
-                "summary": "Update a voucher",
+                "summary": "Delete an import mapping profile",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Voucher ID",
+                        "description": "Mapping profile ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
                     },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/login": {
+            "post": {
+                "description": "Authenticate user and return token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "User login",
+                "parameters": [
                     {
-                        "description": "Updated voucher data",
-                        "name": "voucher",
+                        "description": "Login request",
+                        "name": "login",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdateVoucherRequest"
+                            "$ref": "#/definitions/dto.LoginRequest"
                         }
                     }
                 ],
@@ -436,7 +440,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.VoucherResponse"
+                                            "$ref": "#/definitions/dto.LoginResponse"
                                         }
                                     }
                                 }
@@ -446,94 +450,2643 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/util.Response"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/util.Response"
+                            "$ref": "#/definitions/util.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/util.Response"
+                            "$ref": "#/definitions/util.Problem"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/scheduled-exports": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a voucher by its ID",
+                "description": "Retrieve scheduled exports ordered by name, with their next run time. Links to the neighbouring pages are sent in the Link header.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "vouchers"
+                    "scheduled-exports"
                 ],
-                "summary": "Delete a voucher",
+                "summary": "List scheduled exports",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Voucher ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Number of items per page",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/util.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.ScheduledExportResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/util.Response"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/util.Response"
+                            "$ref": "#/definitions/util.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/util.Response"
+                            "$ref": "#/definitions/util.Problem"
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Define a recurring voucher export (cron expression, filters and format) written to a storage backend",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scheduled-exports"
+                ],
+                "summary": "Create a scheduled export",
+                "parameters": [
+                    {
+                        "description": "Scheduled export",
+                        "name": "export",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ScheduledExportRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.ScheduledExportResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/scheduled-exports/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a specific scheduled export by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scheduled-exports"
+                ],
+                "summary": "Get scheduled export by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scheduled export ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.ScheduledExportResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace the definition of a scheduled export; the new schedule applies immediately",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scheduled-exports"
+                ],
+                "summary": "Update a scheduled export",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scheduled export ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Scheduled export",
+                        "name": "export",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ScheduledExportRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.ScheduledExportResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a scheduled export and its run history",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scheduled-exports"
+                ],
+                "summary": "Delete a scheduled export",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scheduled export ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/scheduled-exports/{id}/run": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Start a run of the export immediately, outside its schedule. The run continues in the background; poll the run history for its outcome.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scheduled-exports"
+                ],
+                "summary": "Run a scheduled export now",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scheduled export ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.ScheduledExportRunResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/scheduled-exports/{id}/runs": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the runs of a scheduled export, most recent first, with their status, output location and failure reason. Links to the neighbouring pages are sent in the Link header.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scheduled-exports"
+                ],
+                "summary": "List runs of a scheduled export",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scheduled export ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Number of runs per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.ScheduledExportRunResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/vouchers": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a list of vouchers. Pages can be addressed by page number or, stable while vouchers are being added, by the meta.next_cursor/meta.prev_cursor returned with every page. Links to the neighbouring pages are sent in the Link header.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "vouchers"
+                ],
+                "summary": "List vouchers",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Number of items per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search term",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "contains",
+                        "description": "How search matches voucher codes: contains, prefix or fuzzy (typo tolerant)",
+                        "name": "search_mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum discount percent (inclusive)",
+                        "name": "discount_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum discount percent (inclusive)",
+                        "name": "discount_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Expiring on or after this date",
+                        "name": "expiry_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Expiring on or before this date",
+                        "name": "expiry_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created on or after this date",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created on or before this date",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "active (not expired yet) or expired",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only active vouchers expiring within this many days",
+                        "name": "expiring_within_days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated sort fields, prefixed with - for descending, e.g. -discount_percent,expiry_date (voucher_code, expiry_date, discount_percent, created_at, updated_at); replaces sort_by and sort_order",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "expiry_date",
+                        "description": "Sort by field; relevance requires search_mode=fuzzy",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "asc",
+                        "description": "Sort order (asc or desc)",
+                        "name": "sort_order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from a previous page (next_cursor or prev_cursor); page is ignored when set",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.VoucherResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new voucher with the provided details",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "vouchers"
+                ],
+                "summary": "Create a new voucher",
+                "parameters": [
+                    {
+                        "description": "Voucher data",
+                        "name": "voucher",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateVoucherRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.VoucherResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Voucher version"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/vouchers/autocomplete": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Suggest voucher codes starting with the given text, case-insensitively and in alphabetical order",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "vouchers"
+                ],
+                "summary": "Autocomplete voucher codes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Beginning of the voucher code",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Maximum number of codes",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "type": "string"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/vouchers/bulk": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Apply one operation (extend_expiry, set_discount, expire or delete) to the vouchers listed in ids or matching filter, which takes the same filters as the voucher list. Everything runs in one transaction, so an error changes nothing, and is refused when more vouchers than max_affected (at most 1000) match. Vouchers changed or deleted concurrently are left alone and reported as conflict; the others are still changed. With dry_run the changes are only previewed. The report lists the outcome for every voucher.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "vouchers"
+                ],
+                "summary": "Update or delete many vouchers",
+                "parameters": [
+                    {
+                        "description": "Bulk operation",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.BulkVoucherRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.BulkVoucherResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/vouchers/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Export vouchers as a CSV file, a formatted XLSX workbook or streamed NDJSON. Rows are streamed from the database and can be filtered and sorted like the voucher list.",
+                "produces": [
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "vouchers"
+                ],
+                "summary": "Export vouchers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "csv",
+                        "description": "Export format (csv, xlsx or ndjson)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "display",
+                        "description": "Header profile: display for readable headers, import for a file that can be uploaded again as-is",
+                        "name": "profile",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only export vouchers whose code matches this term",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "contains",
+                        "description": "How search matches voucher codes: contains, prefix or fuzzy (typo tolerant)",
+                        "name": "search_mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum discount percent (inclusive)",
+                        "name": "discount_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum discount percent (inclusive)",
+                        "name": "discount_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Expiring on or after this date",
+                        "name": "expiry_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Expiring on or before this date",
+                        "name": "expiry_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created on or after this date",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created on or before this date",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "active (not expired yet) or expired",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only active vouchers expiring within this many days",
+                        "name": "expiring_within_days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated sort fields, prefixed with - for descending, e.g. -discount_percent,expiry_date; replaces sort_by and sort_order",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "created_at",
+                        "description": "Sort by field (voucher_code, expiry_date, discount_percent, created_at, updated_at, relevance)",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "desc",
+                        "description": "Sort order (asc or desc)",
+                        "name": "sort_order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated columns to include in CSV and XLSX exports (id, voucher_code, discount_percent, expiry_date, created_at, updated_at, version)",
+                        "name": "columns",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/vouchers/import-json": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Import vouchers from a JSON array or newline-delimited JSON (NDJSON) of voucher objects. The body is streamed and every item is validated like a CSV row.",
+                "consumes": [
+                    "application/json",
+                    "application/x-ndjson"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "vouchers"
+                ],
+                "summary": "Bulk import vouchers from JSON",
+                "parameters": [
+                    {
+                        "description": "Vouchers to import",
+                        "name": "vouchers",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.CreateVoucherRequest"
+                            }
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "default": "Asia/Jakarta",
+                        "description": "Timezone for dates without an offset",
+                        "name": "timezone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Update the voucher named in the id field, at the version in the version field if any, instead of creating one",
+                        "name": "match_by_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.CSVUploadResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/vouchers/imports/{id}/error-report": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Download the failing rows of a CSV import with an extra error_reason column, ready to be fixed and uploaded again",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "vouchers"
+                ],
+                "summary": "Download the error report of an import",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Import ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/vouchers/upload-csv": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Upload vouchers from a CSV file or an .xlsx workbook",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "vouchers"
+                ],
+                "summary": "Upload vouchers from CSV or XLSX",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV or XLSX file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Worksheet to read from an XLSX file, defaults to the first sheet",
+                        "name": "sheet",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Field delimiter (, ; | or tab), detected from the header row when empty",
+                        "name": "delimiter",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "File encoding (utf-8, windows-1252 or iso-8859-1), detected when empty",
+                        "name": "encoding",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID or name of a saved import mapping profile",
+                        "name": "mapping_profile",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "default": "Asia/Jakarta",
+                        "description": "Timezone for dates without an offset",
+                        "name": "timezone",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Update the voucher named in the id column, at the version in the version column if any, instead of creating one",
+                        "name": "match_by_id",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.CSVUploadResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/vouchers/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a specific voucher by its ID. The voucher version is returned in the ETag header; send it back in If-None-Match to get a 304 when the voucher has not changed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "vouchers"
+                ],
+                "summary": "Get voucher by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Voucher ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached copy",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.VoucherResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Voucher version"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update an existing voucher with new details",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "vouchers"
+                ],
+                "summary": "Update a voucher",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Voucher ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated voucher data",
+                        "name": "voucher",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateVoucherRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag the voucher must still have",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.VoucherResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New voucher version"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a voucher by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "vouchers"
+                ],
+                "summary": "Delete a voucher",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Voucher ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag the voucher must still have",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change some fields of a voucher with a JSON Merge Patch (RFC 7396). Fields left out of the patch are not changed; null is rejected because every voucher field is required.",
+                "consumes": [
+                    "application/json",
+                    "application/merge-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "vouchers"
+                ],
+                "summary": "Partially update a voucher",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Voucher ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "voucher",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PatchVoucherRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag the voucher must still have",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.VoucherResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New voucher version"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve webhook subscriptions ordered by name. Links to the neighbouring pages are sent in the Link header.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhook subscriptions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Number of items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.WebhookSubscriptionResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Subscribe a URL to voucher events. Every event is POSTed as JSON, signed in X-Webhook-Signature with HMAC-SHA256 of \"\u003cX-Webhook-Timestamp\u003e.\u003cbody\u003e\" under the subscription secret. The secret is generated when none is given and is only returned by this call.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Create a webhook subscription",
+                "parameters": [
+                    {
+                        "description": "Webhook subscription",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.WebhookSubscriptionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.WebhookSubscriptionResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a specific webhook subscription by its ID; the secret is not included",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Get webhook subscription by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.WebhookSubscriptionResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace a webhook subscription. Without a secret the current one is kept; a new secret is returned once. Disabling a subscription pauses its pending deliveries.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Update a webhook subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Webhook subscription",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.WebhookSubscriptionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.WebhookSubscriptionResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a webhook subscription together with its deliveries and their logs",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Delete a webhook subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the deliveries of a webhook subscription, most recent first: pending ones with their next attempt, succeeded ones, and dead ones that ran out of attempts. Links to the neighbouring pages are sent in the Link header.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List deliveries of a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "succeeded",
+                            "dead"
+                        ],
+                        "type": "string",
+                        "description": "Delivery status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Number of deliveries per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.WebhookDeliveryResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries/{delivery_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a delivery with its payload and the log of its attempts: status code, error, start of the response body and duration of each",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Get a webhook delivery",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Delivery ID",
+                        "name": "delivery_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.WebhookDeliveryResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries/{delivery_id}/redeliver": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Send the event of a delivery again, e.g. a dead one after the receiver was fixed. The event is queued as a new delivery with a fresh set of attempts and the same X-Webhook-Id.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Redeliver a webhook event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Delivery ID",
+                        "name": "delivery_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.WebhookDeliveryResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.Problem"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "apperror.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "param": {
+                    "type": "string"
+                },
+                "rule": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.BulkVoucherRequest": {
+            "type": "object",
+            "required": [
+                "operation"
+            ],
+            "properties": {
+                "discount_percent": {
+                    "description": "discount_percent, for set_discount",
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "dry_run": {
+                    "description": "dry_run, report what would change without changing anything",
+                    "type": "boolean"
+                },
+                "extend_by_days": {
+                    "description": "extend_by_days, for extend_expiry",
+                    "type": "integer",
+                    "maximum": 3650,
+                    "minimum": 1
+                },
+                "filter": {
+                    "$ref": "#/definitions/dto.VoucherFilterQuery"
+                },
+                "ids": {
+                    "description": "ids or filter, exactly one of them",
+                    "type": "array",
+                    "maxItems": 1000,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "max_affected": {
+                    "description": "max_affected, refuse to run when more vouchers match; 1000 at most",
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": 1
+                },
+                "operation": {
+                    "description": "operation: extend_expiry, set_discount, expire (set the expiry to now)\nor delete",
+                    "type": "string",
+                    "enum": [
+                        "extend_expiry",
+                        "set_discount",
+                        "expire",
+                        "delete"
+                    ]
+                }
+            }
+        },
+        "dto.BulkVoucherResponse": {
+            "type": "object",
+            "properties": {
+                "affected": {
+                    "type": "integer"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "matched": {
+                    "description": "matched, vouchers selected; affected, vouchers changed or deleted",
+                    "type": "integer"
+                },
+                "operation": {
+                    "type": "string"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.BulkVoucherResult"
+                    }
+                }
+            }
+        },
+        "dto.BulkVoucherResult": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "voucher": {
+                    "$ref": "#/definitions/dto.VoucherResponse"
+                }
+            }
+        },
         "dto.CSVUploadResponse": {
             "type": "object",
             "properties": {
-                "failed_count": {
-                    "type": "integer"
+                "delimiter": {
+                    "type": "string"
+                },
+                "encoding": {
+                    "type": "string"
+                },
+                "failed_count": {
+                    "type": "integer"
+                },
+                "failed_rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.FailedRow"
+                    }
+                },
+                "format": {
+                    "type": "string"
+                },
+                "import_id": {
+                    "type": "string"
+                },
+                "success_count": {
+                    "type": "integer"
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "updated_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.CreateVoucherRequest": {
+            "type": "object",
+            "required": [
+                "discount_percent",
+                "expiry_date",
+                "voucher_code"
+            ],
+            "properties": {
+                "discount_percent": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "expiry_date": {
+                    "type": "string"
+                },
+                "voucher_code": {
+                    "description": "voucher_code, discount_percent, expiry_date",
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "dto.FailedRow": {
+            "type": "object",
+            "properties": {
+                "duplicate_of_row": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "row_number": {
+                    "type": "integer"
+                },
+                "voucher_code": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.GraphQLError": {
+            "type": "object",
+            "properties": {
+                "extensions": {
+                    "description": "extensions, the error code and, for validation errors, the invalid\nfields",
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "message": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "array",
+                    "items": {}
+                }
+            }
+        },
+        "dto.GraphQLRequest": {
+            "type": "object",
+            "required": [
+                "query"
+            ],
+            "properties": {
+                "operationName": {
+                    "type": "string"
+                },
+                "query": {
+                    "description": "query, operationName, variables",
+                    "type": "string"
+                },
+                "variables": {
+                    "type": "object",
+                    "additionalProperties": {}
+                }
+            }
+        },
+        "dto.GraphQLResponse": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.GraphQLError"
+                    }
+                }
+            }
+        },
+        "dto.ImportMappingProfileRequest": {
+            "type": "object",
+            "required": [
+                "mapping",
+                "name"
+            ],
+            "properties": {
+                "mapping": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "description": "name, mapping (source header -\u003e voucher field)",
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "dto.ImportMappingProfileResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "mapping": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.LoginRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PatchVoucherRequest": {
+            "type": "object",
+            "properties": {
+                "discount_percent": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "expiry_date": {
+                    "type": "string",
+                    "minLength": 1
+                },
+                "voucher_code": {
+                    "description": "voucher_code, discount_percent, expiry_date, all optional",
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 1
+                }
+            }
+        },
+        "dto.ScheduledExportRequest": {
+            "type": "object",
+            "required": [
+                "cron_expression",
+                "name"
+            ],
+            "properties": {
+                "columns": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "cron_expression": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "enabled": {
+                    "description": "enabled, defaults to true",
+                    "type": "boolean"
+                },
+                "filter": {
+                    "description": "filter, the list filters of GET /vouchers/export (search, status,\ndiscount_min, expiry_from, ...)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.VoucherFilterQuery"
+                        }
+                    ]
+                },
+                "format": {
+                    "description": "format, profile, sort, sort_by, sort_order, columns; the same\noptions as GET /vouchers/export",
+                    "type": "string",
+                    "enum": [
+                        "csv",
+                        "xlsx",
+                        "ndjson"
+                    ]
+                },
+                "name": {
+                    "description": "name, cron_expression (standard 5 field cron or a descriptor such as @daily)",
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "path_prefix": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "profile": {
+                    "type": "string",
+                    "enum": [
+                        "display",
+                        "import"
+                    ]
+                },
+                "sort": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "sort_by": {
+                    "type": "string",
+                    "enum": [
+                        "voucher_code",
+                        "expiry_date",
+                        "discount_percent",
+                        "created_at",
+                        "updated_at",
+                        "relevance"
+                    ]
+                },
+                "sort_order": {
+                    "type": "string",
+                    "enum": [
+                        "asc",
+                        "desc"
+                    ]
+                },
+                "storage": {
+                    "description": "storage (local or s3), path_prefix inside the storage backend",
+                    "type": "string",
+                    "enum": [
+                        "local",
+                        "s3"
+                    ]
+                }
+            }
+        },
+        "dto.ScheduledExportResponse": {
+            "type": "object",
+            "properties": {
+                "columns": {
+                    "type": "string"
                 },
-                "failed_rows": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dto.FailedRow"
-                    }
+                "created_at": {
+                    "type": "string"
                 },
-                "success_count": {
+                "cron_expression": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "filter": {
+                    "$ref": "#/definitions/dto.VoucherFilterQuery"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "next_run_at": {
+                    "type": "string"
+                },
+                "path_prefix": {
+                    "type": "string"
+                },
+                "profile": {
+                    "type": "string"
+                },
+                "sort": {
+                    "type": "string"
+                },
+                "sort_by": {
+                    "type": "string"
+                },
+                "sort_order": {
+                    "type": "string"
+                },
+                "storage": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ScheduledExportRunResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "scheduled_export_id": {
+                    "type": "string"
+                },
+                "scheduled_for": {
+                    "description": "ScheduledFor is the schedule slot of a scheduled run.",
+                    "type": "string"
+                },
+                "size_bytes": {
                     "type": "integer"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "triggered_by": {
+                    "type": "string"
                 }
             }
         },
-        "dto.CreateVoucherRequest": {
+        "dto.UpdateVoucherRequest": {
             "type": "object",
             "required": [
                 "discount_percent",
@@ -556,13 +3109,81 @@ const docTemplate = `{
                 }
             }
         },
-        "dto.FailedRow": {
+        "dto.VoucherFilterQuery": {
             "type": "object",
             "properties": {
-                "reason": {
+                "created_from": {
                     "type": "string"
                 },
-                "row_number": {
+                "created_to": {
+                    "type": "string"
+                },
+                "discount_max": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "discount_min": {
+                    "description": "discount_min, discount_max, inclusive",
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "expiring_within_days": {
+                    "description": "expiring_within_days, active vouchers expiring in the next N days",
+                    "type": "integer",
+                    "maximum": 3650,
+                    "minimum": 1
+                },
+                "expiry_from": {
+                    "description": "expiry_from, expiry_to, created_from, created_to, inclusive",
+                    "type": "string"
+                },
+                "expiry_to": {
+                    "type": "string"
+                },
+                "search": {
+                    "description": "search, search_mode (contains, prefix or fuzzy; contains by default)",
+                    "type": "string"
+                },
+                "search_mode": {
+                    "type": "string",
+                    "enum": [
+                        "contains",
+                        "prefix",
+                        "fuzzy"
+                    ]
+                },
+                "status": {
+                    "description": "status, active (not expired yet) or expired",
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "expired"
+                    ]
+                }
+            }
+        },
+        "dto.VoucherResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "discount_percent": {
+                    "type": "integer"
+                },
+                "expiry_date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "version, incremented on every update and sent as the ETag",
                     "type": "integer"
                 },
                 "voucher_code": {
@@ -570,67 +3191,220 @@ const docTemplate = `{
                 }
             }
         },
-        "dto.LoginRequest": {
+        "dto.WebhookDeliveryAttemptResponse": {
             "type": "object",
             "properties": {
-                "email": {
+                "attempt": {
+                    "type": "integer"
+                },
+                "attempted_at": {
                     "type": "string"
                 },
-                "password": {
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "response_body": {
                     "type": "string"
+                },
+                "status_code": {
+                    "description": "status_code, 0 when there was no response",
+                    "type": "integer"
                 }
             }
         },
-        "dto.LoginResponse": {
+        "dto.WebhookDeliveryResponse": {
             "type": "object",
             "properties": {
-                "token": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "completed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "log": {
+                    "description": "log, the attempts so far; only included for a single delivery",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.WebhookDeliveryAttemptResponse"
+                    }
+                },
+                "next_attempt_at": {
+                    "description": "next_attempt_at, while pending",
+                    "type": "string"
+                },
+                "payload": {
+                    "description": "payload, the JSON body that is sent",
+                    "type": "object"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subscription_id": {
                     "type": "string"
                 }
             }
         },
-        "dto.UpdateVoucherRequest": {
+        "dto.WebhookSubscriptionRequest": {
             "type": "object",
             "required": [
-                "discount_percent",
-                "expiry_date",
-                "voucher_code"
+                "events",
+                "name",
+                "url"
             ],
             "properties": {
-                "discount_percent": {
-                    "type": "number",
-                    "maximum": 100,
-                    "minimum": 0
+                "enabled": {
+                    "description": "enabled, defaults to true",
+                    "type": "boolean"
                 },
-                "expiry_date": {
-                    "type": "string"
+                "events": {
+                    "description": "events, any of voucher.created, voucher.updated, voucher.deleted and\nvoucher.expired",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "voucher_code": {
-                    "description": "voucher_code, discount_percent, expiry_date",
+                "name": {
+                    "description": "name, url (http or https)",
                     "type": "string",
                     "maxLength": 255
+                },
+                "secret": {
+                    "description": "secret, key of the payload signatures; generated when creating a\nsubscription without one, kept when updating without one",
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 16
+                },
+                "url": {
+                    "type": "string",
+                    "maxLength": 2048
                 }
             }
         },
-        "dto.VoucherResponse": {
+        "dto.WebhookSubscriptionResponse": {
             "type": "object",
             "properties": {
                 "created_at": {
                     "type": "string"
                 },
-                "discount_percent": {
-                    "type": "integer"
+                "enabled": {
+                    "type": "boolean"
                 },
-                "expiry_date": {
-                    "type": "string"
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
                 "id": {
                     "type": "string"
                 },
+                "name": {
+                    "type": "string"
+                },
+                "secret": {
+                    "description": "secret, only returned when it is set or generated",
+                    "type": "string"
+                },
                 "updated_at": {
                     "type": "string"
                 },
-                "voucher_code": {
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "util.Meta": {
+            "type": "object",
+            "properties": {
+                "has_next": {
+                    "type": "boolean"
+                },
+                "has_prev": {
+                    "type": "boolean"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "description": "NextCursor, PrevCursor are set by endpoints that support keyset\npagination.",
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "prev_cursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "util.PaginatedResponse": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "message": {
+                    "type": "string"
+                },
+                "meta": {
+                    "$ref": "#/definitions/util.Meta"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "util.Problem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/apperror.FieldError"
+                    }
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
                     "type": "string"
                 }
             }
@@ -662,7 +3436,7 @@ const docTemplate = `{
 var SwaggerInfo = &swag.Spec{
 	Version:          "1.0",
 	Host:             "",
-	BasePath:         "",
+	BasePath:         "/api/v1",
 	Schemes:          []string{},
 	Title:            "Technical Test Indico API",
 	Description:      "API for managing vouchers",
@@ -0,0 +1,128 @@
+// Package apperror defines the errors services return for failures a client
+// can act on. Every error has a kind, which decides the HTTP status, and a
+// stable machine readable code; any other error is treated as internal.
+package apperror
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Kinds of application errors, matched with errors.Is.
+var (
+	ErrNotFound             = errors.New("not found")
+	ErrConflict             = errors.New("conflict")
+	ErrValidation           = errors.New("validation failed")
+	ErrInvalidID            = errors.New("invalid id")
+	ErrBadRequest           = errors.New("bad request")
+	ErrUnauthorized         = errors.New("unauthorized")
+	ErrForbidden            = errors.New("forbidden")
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+	ErrPreconditionFailed   = errors.New("precondition failed")
+	ErrPreconditionRequired = errors.New("precondition required")
+)
+
+// FieldError describes why a single request field is invalid. Rule and
+// Param name the failed validation rule, e.g. max and 100.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule,omitempty"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+
+	// MessageID identifies Message in the translation catalog, so it can
+	// be shown in the client's language. Empty for untranslated messages.
+	MessageID string `json:"-"`
+}
+
+// Error is an application error. Message is safe to show to clients and,
+// like field messages, may be translated through MessageID. Err is an
+// optional underlying cause kept for logging.
+type Error struct {
+	Kind      error
+	Code      string
+	Message   string
+	MessageID string
+	Fields    []FieldError
+	Err       error
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Wrap records the underlying cause of the error.
+func (e *Error) Wrap(err error) *Error {
+	e.Err = err
+	return e
+}
+
+func New(kind error, code, format string, args ...any) *Error {
+	return &Error{Kind: kind, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+func NotFound(code, format string, args ...any) *Error {
+	return New(ErrNotFound, code, format, args...)
+}
+
+func Conflict(code, format string, args ...any) *Error {
+	return New(ErrConflict, code, format, args...)
+}
+
+func InvalidID(code, format string, args ...any) *Error {
+	return New(ErrInvalidID, code, format, args...)
+}
+
+func BadRequest(code, format string, args ...any) *Error {
+	return New(ErrBadRequest, code, format, args...)
+}
+
+func Unauthorized(code, format string, args ...any) *Error {
+	return New(ErrUnauthorized, code, format, args...)
+}
+
+func Forbidden(code, format string, args ...any) *Error {
+	return New(ErrForbidden, code, format, args...)
+}
+
+func UnsupportedMediaType(code, format string, args ...any) *Error {
+	return New(ErrUnsupportedMediaType, code, format, args...)
+}
+
+func PreconditionFailed(code, format string, args ...any) *Error {
+	return New(ErrPreconditionFailed, code, format, args...)
+}
+
+func PreconditionRequired(code, format string, args ...any) *Error {
+	return New(ErrPreconditionRequired, code, format, args...)
+}
+
+// Validation reports invalid input, optionally naming the offending fields.
+func Validation(code, message string, fields ...FieldError) *Error {
+	return &Error{Kind: ErrValidation, Code: code, Message: message, Fields: fields}
+}
+
+// FieldInvalid reports a single invalid field, using the message for both
+// the error and the field.
+func FieldInvalid(code, field, format string, args ...any) *Error {
+	message := fmt.Sprintf(format, args...)
+	return Validation(code, message, FieldError{Field: field, Message: message})
+}
+
+// As returns the application error in err's chain, if any.
+func As(err error) (*Error, bool) {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr, true
+	}
+
+	return nil, false
+}
@@ -3,6 +3,9 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type ServerConfig struct {
@@ -10,6 +13,12 @@ type ServerConfig struct {
 	Mode string
 }
 
+type GRPCConfig struct {
+	// Enabled serves the gRPC API on Port next to the REST API.
+	Enabled bool
+	Port    string
+}
+
 type DatabaseConfig struct {
 	Host     string
 	Port     string
@@ -18,9 +27,105 @@ type DatabaseConfig struct {
 	DBName   string
 }
 
+type APIConfig struct {
+	// LegacyRoutesEnabled keeps serving the unversioned paths, e.g.
+	// /vouchers, as deprecated aliases of /api/v1 until LegacySunsetAt.
+	LegacyRoutesEnabled bool
+	LegacyDeprecatedAt  time.Time
+	LegacySunsetAt      time.Time
+}
+
+type ImportConfig struct {
+	CaseInsensitiveCodes bool
+}
+
+type ConcurrencyConfig struct {
+	// RequireIfMatch rejects voucher updates and deletes that do not send
+	// the voucher's ETag in If-Match.
+	RequireIfMatch bool
+}
+
+type DateConfig struct {
+	// Formats are the accepted date formats, e.g. "YYYY-MM-DD" or "RFC3339"
+	Formats  []string
+	Timezone string
+}
+
+type ExportConfig struct {
+	// SchedulerEnabled runs scheduled exports in this process. Several
+	// instances may run it; each scheduled run happens on one of them.
+	SchedulerEnabled bool
+	// ReloadInterval is how often the scheduler reloads the definitions,
+	// which other instances may have changed.
+	ReloadInterval time.Duration
+	LocalDir       string
+	S3             S3Config
+}
+
+type WebhookConfig struct {
+	// DispatcherEnabled sends webhook deliveries from this process. Several
+	// instances may run it; a delivery is only sent by one at a time.
+	DispatcherEnabled bool
+	// MaxAttempts is how often a delivery is tried before it is dead.
+	MaxAttempts int
+	// RetryBaseDelay is the wait before the first retry; it doubles with
+	// every further attempt.
+	RetryBaseDelay time.Duration
+	Timeout        time.Duration
+	PollInterval   time.Duration
+	// AllowedNetworks are CIDRs or addresses webhooks may reach although
+	// they are loopback, private or link-local, e.g. 127.0.0.1 for a local
+	// stub. Any other such address is refused.
+	AllowedNetworks []string
+}
+
+type OutboxConfig struct {
+	// RelayEnabled publishes outbox events from this process. Several
+	// instances may run it; events of a voucher still go out in order.
+	RelayEnabled bool
+	// Sinks are where events are published: webhook, log and nats.
+	Sinks []string
+	// RetryBaseDelay is the wait before publishing a failed event again; it
+	// doubles with every further attempt, up to a minute.
+	RetryBaseDelay time.Duration
+	// MaxAttempts is how often an event is tried before the relay gives up
+	// on it, so that it no longer holds back the later events of its
+	// voucher.
+	MaxAttempts    int
+	PublishTimeout time.Duration
+	PollInterval   time.Duration
+	// Retention is how long published events are kept.
+	Retention time.Duration
+	NATS      NATSConfig
+}
+
+type NATSConfig struct {
+	URL string
+	// SubjectPrefix is put before the event type, e.g. "events" publishes
+	// voucher.created events on "events.voucher.created".
+	SubjectPrefix string
+}
+
+type S3Config struct {
+	Endpoint  string
+	AccessKey string
+	SecretKey string
+	Bucket    string
+	Region    string
+	UseSSL    bool
+}
+
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
+	Server      ServerConfig
+	API         APIConfig
+	GRPC        GRPCConfig
+	Database    DatabaseConfig
+	Import      ImportConfig
+	Concurrency ConcurrencyConfig
+	Date        DateConfig
+	Export      ExportConfig
+	Webhook     WebhookConfig
+	Outbox      OutboxConfig
 }
 
 func getEnv(key, defaultValue string) string {
@@ -31,12 +136,75 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+func getEnvBool(key string, defaultValue bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+
+	return value
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+
+	return value
+}
+
+// getEnvDuration reads a Go duration such as "30s" or "5m".
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+
+	return value
+}
+
+// getEnvDate reads a YYYY-MM-DD date, in UTC.
+func getEnvDate(key, defaultValue string) time.Time {
+	value, err := time.Parse(time.DateOnly, getEnv(key, defaultValue))
+	if err != nil {
+		value, _ = time.Parse(time.DateOnly, defaultValue)
+	}
+
+	return value
+}
+
+func getEnvList(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+
+	return list
+}
+
 func LoadConfig() *Config {
 	return &Config{
 		Server: ServerConfig{
 			Port: getEnv("PORT", "8080"),
 			Mode: getEnv("MODE", "debug"),
 		},
+		API: APIConfig{
+			LegacyRoutesEnabled: getEnvBool("LEGACY_ROUTES_ENABLED", true),
+			LegacyDeprecatedAt:  getEnvDate("LEGACY_ROUTES_DEPRECATED_AT", "2026-10-18"),
+			LegacySunsetAt:      getEnvDate("LEGACY_ROUTES_SUNSET", "2027-04-30"),
+		},
+		GRPC: GRPCConfig{
+			Enabled: getEnvBool("GRPC_ENABLED", true),
+			Port:    getEnv("GRPC_PORT", "9090"),
+		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
 			Port:     getEnv("DB_PORT", "2050"),
@@ -44,6 +212,50 @@ func LoadConfig() *Config {
 			Password: getEnv("DB_PASSWORD", "postgres"),
 			DBName:   getEnv("DB_NAME", "techtest_indico"),
 		},
+		Import: ImportConfig{
+			CaseInsensitiveCodes: getEnvBool("IMPORT_CASE_INSENSITIVE_CODES", false),
+		},
+		Concurrency: ConcurrencyConfig{
+			RequireIfMatch: getEnvBool("REQUIRE_IF_MATCH", false),
+		},
+		Date: DateConfig{
+			Formats:  getEnvList("DATE_FORMATS", nil),
+			Timezone: getEnv("TIMEZONE", "Asia/Jakarta"),
+		},
+		Export: ExportConfig{
+			SchedulerEnabled: getEnvBool("EXPORT_SCHEDULER_ENABLED", true),
+			ReloadInterval:   getEnvDuration("EXPORT_SCHEDULER_RELOAD_INTERVAL", 30*time.Second),
+			LocalDir:         getEnv("EXPORT_LOCAL_DIR", "./exports"),
+			S3: S3Config{
+				Endpoint:  getEnv("EXPORT_S3_ENDPOINT", ""),
+				AccessKey: getEnv("EXPORT_S3_ACCESS_KEY", ""),
+				SecretKey: getEnv("EXPORT_S3_SECRET_KEY", ""),
+				Bucket:    getEnv("EXPORT_S3_BUCKET", ""),
+				Region:    getEnv("EXPORT_S3_REGION", ""),
+				UseSSL:    getEnvBool("EXPORT_S3_USE_SSL", false),
+			},
+		},
+		Webhook: WebhookConfig{
+			DispatcherEnabled: getEnvBool("WEBHOOK_DISPATCHER_ENABLED", true),
+			MaxAttempts:       getEnvInt("WEBHOOK_MAX_ATTEMPTS", 8),
+			RetryBaseDelay:    getEnvDuration("WEBHOOK_RETRY_BASE_DELAY", 30*time.Second),
+			Timeout:           getEnvDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+			PollInterval:      getEnvDuration("WEBHOOK_POLL_INTERVAL", 5*time.Second),
+			AllowedNetworks:   getEnvList("WEBHOOK_ALLOWED_NETWORKS", nil),
+		},
+		Outbox: OutboxConfig{
+			RelayEnabled:   getEnvBool("OUTBOX_RELAY_ENABLED", true),
+			Sinks:          getEnvList("OUTBOX_SINKS", []string{"webhook"}),
+			RetryBaseDelay: getEnvDuration("OUTBOX_RETRY_BASE_DELAY", time.Second),
+			MaxAttempts:    getEnvInt("OUTBOX_MAX_ATTEMPTS", 25),
+			PublishTimeout: getEnvDuration("OUTBOX_PUBLISH_TIMEOUT", 10*time.Second),
+			PollInterval:   getEnvDuration("OUTBOX_POLL_INTERVAL", time.Second),
+			Retention:      getEnvDuration("OUTBOX_RETENTION", 7*24*time.Hour),
+			NATS: NATSConfig{
+				URL:           getEnv("NATS_URL", "nats://localhost:4222"),
+				SubjectPrefix: getEnv("NATS_SUBJECT_PREFIX", "events"),
+			},
+		},
 	}
 }
 
@@ -0,0 +1,24 @@
+package dto
+
+// GraphQLRequest is the body of a GraphQL request over HTTP.
+type GraphQLRequest struct {
+	// query, operationName, variables
+	Query         string         `json:"query" binding:"required"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// GraphQLResponse documents the body of a GraphQL response; data is null
+// or missing when the request failed as a whole.
+type GraphQLResponse struct {
+	Data   any            `json:"data,omitempty"`
+	Errors []GraphQLError `json:"errors,omitempty"`
+}
+
+type GraphQLError struct {
+	Message string `json:"message"`
+	Path    []any  `json:"path,omitempty"`
+	// extensions, the error code and, for validation errors, the invalid
+	// fields
+	Extensions map[string]any `json:"extensions,omitempty"`
+}
@@ -0,0 +1,21 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type ImportMappingProfileRequest struct {
+	// name, mapping (source header -> voucher field)
+	Name    string            `json:"name" binding:"required" validate:"max=255"`
+	Mapping map[string]string `json:"mapping" binding:"required" validate:"min=1"`
+}
+
+type ImportMappingProfileResponse struct {
+	ID        pgtype.UUID       `json:"id"`
+	Name      string            `json:"name"`
+	Mapping   map[string]string `json:"mapping"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
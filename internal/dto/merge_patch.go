@@ -0,0 +1,84 @@
+package dto
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/alifdwt/techtest-indico-be/internal/apperror"
+	"github.com/alifdwt/techtest-indico-be/internal/i18n"
+)
+
+// MergePatchContentType is the media type of JSON Merge Patch (RFC 7396).
+const MergePatchContentType = "application/merge-patch+json"
+
+// DecodeMergePatch decodes a JSON Merge Patch document into obj, a pointer
+// to a struct of pointer fields. The patch must be a JSON object. Members
+// that obj does not have, and null members, which would remove a field
+// the resource cannot go without, are reported as field errors. Malformed
+// JSON and values of the wrong type are returned as decoding errors.
+func DecodeMergePatch(data []byte, obj any) error {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil || members == nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return err
+		}
+		return apperror.BadRequest("invalid_merge_patch", "merge patch must be a JSON object")
+	}
+
+	known := jsonFieldNames(obj)
+	names := make([]string, 0, len(members))
+	for name := range members {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var fields []apperror.FieldError
+	for _, name := range names {
+		switch {
+		case !known[name]:
+			fields = append(fields, patchFieldError(name, "unknown", i18n.MsgUnknownField))
+		case bytes.Equal(bytes.TrimSpace(members[name]), []byte("null")):
+			fields = append(fields, patchFieldError(name, "not_null", i18n.MsgNotNull))
+		}
+	}
+	if len(fields) > 0 {
+		validationErr := apperror.Validation("validation_failed", i18n.Translate(i18n.Default, i18n.MsgValidationFailed), fields...)
+		validationErr.MessageID = i18n.MsgValidationFailed
+		return validationErr
+	}
+
+	return json.Unmarshal(data, obj)
+}
+
+func patchFieldError(field, rule, messageID string) apperror.FieldError {
+	return apperror.FieldError{
+		Field:     field,
+		Rule:      rule,
+		Message:   i18n.FieldMessage(i18n.Default, messageID, rule, ""),
+		MessageID: messageID,
+	}
+}
+
+// jsonFieldNames returns the JSON names of the fields of the struct obj
+// points to.
+func jsonFieldNames(obj any) map[string]bool {
+	t := reflect.TypeOf(obj)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	names := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names[name] = true
+		}
+	}
+
+	return names
+}
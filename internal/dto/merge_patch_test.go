@@ -0,0 +1,113 @@
+package dto
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/alifdwt/techtest-indico-be/internal/apperror"
+)
+
+func TestDecodeMergePatch(t *testing.T) {
+	code := "NEW10"
+	discount := 12.5
+	expiry := "2030-01-31"
+
+	tests := []struct {
+		name string
+		body string
+		// want is the decoded patch, when it decodes.
+		want PatchVoucherRequest
+		// wantErr is the code of the expected application error, or
+		// "syntax" and "type" for JSON decoding errors.
+		wantErr    string
+		wantFields []string
+	}{
+		{name: "empty patch", body: `{}`},
+		{name: "one member", body: `{"voucher_code":"NEW10"}`, want: PatchVoucherRequest{VoucherCode: &code}},
+		{
+			name: "all members",
+			body: `{"voucher_code":"NEW10","discount_percent":12.5,"expiry_date":"2030-01-31"}`,
+			want: PatchVoucherRequest{VoucherCode: &code, DiscountPercent: &discount, ExpiryDate: &expiry},
+		},
+		{name: "array", body: `[]`, wantErr: "invalid_merge_patch"},
+		{name: "null", body: `null`, wantErr: "invalid_merge_patch"},
+		{name: "string", body: `"patch"`, wantErr: "invalid_merge_patch"},
+		{name: "malformed", body: `{"voucher_code":`, wantErr: "syntax"},
+		{name: "wrong type", body: `{"discount_percent":"ten"}`, wantErr: "type"},
+		{
+			name:       "unknown member",
+			body:       `{"code":"NEW10"}`,
+			wantErr:    "validation_failed",
+			wantFields: []string{"code:unknown"},
+		},
+		{
+			name:       "null member",
+			body:       `{"expiry_date":null}`,
+			wantErr:    "validation_failed",
+			wantFields: []string{"expiry_date:not_null"},
+		},
+		{
+			name:       "several invalid members",
+			body:       `{"voucher_code":null,"active":true,"discount_percent": null }`,
+			wantErr:    "validation_failed",
+			wantFields: []string{"active:unknown", "discount_percent:not_null", "voucher_code:not_null"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got PatchVoucherRequest
+			err := DecodeMergePatch([]byte(tt.body), &got)
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("DecodeMergePatch() = %v, want no error", err)
+				}
+				if !equalPatch(got, tt.want) {
+					t.Errorf("DecodeMergePatch() decoded %+v, want %+v", got, tt.want)
+				}
+				return
+			}
+
+			var syntaxErr *json.SyntaxError
+			var typeErr *json.UnmarshalTypeError
+			var appErr *apperror.Error
+			switch {
+			case tt.wantErr == "syntax":
+				if !errors.As(err, &syntaxErr) {
+					t.Errorf("DecodeMergePatch() = %v, want a syntax error", err)
+				}
+			case tt.wantErr == "type":
+				if !errors.As(err, &typeErr) {
+					t.Errorf("DecodeMergePatch() = %v, want a type error", err)
+				}
+			case !errors.As(err, &appErr) || appErr.Code != tt.wantErr:
+				t.Errorf("DecodeMergePatch() = %v, want %s", err, tt.wantErr)
+			default:
+				var fields []string
+				for _, field := range appErr.Fields {
+					fields = append(fields, field.Field+":"+field.Rule)
+				}
+				if !slices.Equal(fields, tt.wantFields) {
+					t.Errorf("DecodeMergePatch() fields = %v, want %v", fields, tt.wantFields)
+				}
+			}
+		})
+	}
+}
+
+func equalPatch(a, b PatchVoucherRequest) bool {
+	return equalPtr(a.VoucherCode, b.VoucherCode) &&
+		equalPtr(a.DiscountPercent, b.DiscountPercent) &&
+		equalPtr(a.ExpiryDate, b.ExpiryDate)
+}
+
+func equalPtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
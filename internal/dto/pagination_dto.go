@@ -0,0 +1,9 @@
+package dto
+
+// PaginationQuery holds the page number and size accepted by every list
+// endpoint.
+type PaginationQuery struct {
+	// page, limit
+	Page  int `form:"page,default=1" validate:"min=1"`
+	Limit int `form:"limit,default=20" validate:"min=1,max=100"`
+}
@@ -0,0 +1,62 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type ScheduledExportRequest struct {
+	// name, cron_expression (standard 5 field cron or a descriptor such as @daily)
+	Name           string `json:"name" binding:"required" validate:"max=255"`
+	CronExpression string `json:"cron_expression" binding:"required" validate:"max=100"`
+	// format, profile, sort, sort_by, sort_order, columns; the same
+	// options as GET /vouchers/export
+	Format    string `json:"format" validate:"omitempty,oneof=csv xlsx ndjson"`
+	Profile   string `json:"profile" validate:"omitempty,oneof=display import"`
+	Sort      string `json:"sort" validate:"max=255"`
+	SortBy    string `json:"sort_by" validate:"omitempty,oneof=voucher_code expiry_date discount_percent created_at updated_at relevance"`
+	SortOrder string `json:"sort_order" validate:"omitempty,oneof=asc desc"`
+	Columns   string `json:"columns" validate:"max=255"`
+	// filter, the list filters of GET /vouchers/export (search, status,
+	// discount_min, expiry_from, ...)
+	Filter *VoucherFilterQuery `json:"filter"`
+	// storage (local or s3), path_prefix inside the storage backend
+	Storage    string `json:"storage" validate:"omitempty,oneof=local s3"`
+	PathPrefix string `json:"path_prefix" validate:"max=255"`
+	// enabled, defaults to true
+	Enabled *bool `json:"enabled"`
+}
+
+type ScheduledExportResponse struct {
+	ID             pgtype.UUID        `json:"id"`
+	Name           string             `json:"name"`
+	CronExpression string             `json:"cron_expression"`
+	Format         string             `json:"format"`
+	Profile        string             `json:"profile"`
+	Filter         VoucherFilterQuery `json:"filter"`
+	Sort           string             `json:"sort"`
+	SortBy         string             `json:"sort_by"`
+	SortOrder      string             `json:"sort_order"`
+	Columns        string             `json:"columns"`
+	Storage        string             `json:"storage"`
+	PathPrefix     string             `json:"path_prefix"`
+	Enabled        bool               `json:"enabled"`
+	NextRunAt      *time.Time         `json:"next_run_at"`
+	CreatedAt      time.Time          `json:"created_at"`
+	UpdatedAt      time.Time          `json:"updated_at"`
+}
+
+type ScheduledExportRunResponse struct {
+	ID                pgtype.UUID `json:"id"`
+	ScheduledExportID pgtype.UUID `json:"scheduled_export_id"`
+	TriggeredBy       string      `json:"triggered_by"`
+	Status            string      `json:"status"`
+	Location          string      `json:"location,omitempty"`
+	SizeBytes         int64       `json:"size_bytes"`
+	Error             string      `json:"error,omitempty"`
+	// ScheduledFor is the schedule slot of a scheduled run.
+	ScheduledFor *time.Time `json:"scheduled_for,omitempty"`
+	StartedAt    time.Time  `json:"started_at"`
+	FinishedAt   *time.Time `json:"finished_at"`
+}
@@ -1,8 +1,158 @@
 package dto
 
-import "github.com/go-playground/validator/v10"
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/alifdwt/techtest-indico-be/internal/apperror"
+	"github.com/alifdwt/techtest-indico-be/internal/i18n"
+	"github.com/go-playground/validator/v10"
+)
 
 func ValidateStruct(obj any) error {
 	validate := validator.New()
-	return validate.Struct(obj)
+	return ValidationError(obj, validate.Struct(obj))
+}
+
+// ValidateRequest checks the binding rules of obj, which gin checks while
+// binding HTTP requests, and then its validate rules. It is meant for
+// requests that do not come through gin, such as gRPC calls.
+func ValidateRequest(obj any) error {
+	binding := validator.New()
+	binding.SetTagName("binding")
+	if err := binding.Struct(obj); err != nil {
+		return ValidationError(obj, err)
+	}
+
+	return ValidateStruct(obj)
+}
+
+// ValidationError turns the validator errors for obj, from ValidateStruct
+// or from gin's binding rules, into a validation error listing each invalid
+// field by the name it has in the request, the failed rule and a message.
+// Messages are in the default language; the error middleware translates
+// them. Other errors are returned as-is.
+func ValidationError(obj any, err error) error {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return err
+	}
+
+	fields := make([]apperror.FieldError, 0, len(validationErrs))
+	for _, fieldErr := range validationErrs {
+		messageID := fieldMessageID(fieldErr)
+		fields = append(fields, apperror.FieldError{
+			Field:     requestFieldName(obj, fieldErr.StructNamespace()),
+			Rule:      fieldErr.Tag(),
+			Param:     fieldErr.Param(),
+			Message:   i18n.FieldMessage(i18n.Default, messageID, fieldErr.Tag(), fieldErr.Param()),
+			MessageID: messageID,
+		})
+	}
+
+	validationErr := apperror.Validation("validation_failed", i18n.Translate(i18n.Default, i18n.MsgValidationFailed), fields...)
+	validationErr.MessageID = i18n.MsgValidationFailed
+
+	return validationErr
+}
+
+// FieldRuleError reports a single field that failed a rule checked outside
+// the validator, e.g. by a service, the way ValidationError reports the
+// validator's: the field names the rule, its parameter and a catalog
+// message, which the error middleware translates. The detail is the more
+// specific message built from format, e.g. with the counts that broke a
+// limit, which the catalog message cannot carry.
+func FieldRuleError(code, field, rule, param, messageID, format string, args ...any) *apperror.Error {
+	err := apperror.FieldInvalid(code, field, format, args...)
+	err.Fields[0] = apperror.FieldError{
+		Field:     field,
+		Rule:      rule,
+		Param:     param,
+		Message:   i18n.FieldMessage(i18n.Default, messageID, rule, param),
+		MessageID: messageID,
+	}
+
+	return err
+}
+
+// requestFieldName returns the JSON, or else form, name of the field at a
+// validator namespace such as VoucherListQuery.VoucherCode, e.g.
+// voucher_code. Nested fields are joined with dots and keep their index,
+// e.g. filter.discount_min or ids[2]; embedded structs add no name.
+func requestFieldName(obj any, namespace string) string {
+	parts := strings.Split(namespace, ".")
+	if len(parts) < 2 {
+		return namespace
+	}
+
+	t := reflect.TypeOf(obj)
+	names := make([]string, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		for t != nil && (t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+			t = t.Elem()
+		}
+		if t == nil || t.Kind() != reflect.Struct {
+			return namespace
+		}
+
+		structField, index, _ := strings.Cut(part, "[")
+		field, ok := t.FieldByName(structField)
+		if !ok {
+			return namespace
+		}
+		t = field.Type
+
+		name := tagName(field)
+		if field.Anonymous && name == "" {
+			continue
+		}
+		if name == "" {
+			name = structField
+		}
+		if index != "" {
+			name += "[" + index
+		}
+		names = append(names, name)
+	}
+
+	return strings.Join(names, ".")
+}
+
+// tagName returns the name a struct field has in JSON or, for query
+// parameters and forms, in its form tag.
+func tagName(field reflect.StructField) string {
+	for _, key := range []string{"json", "form"} {
+		name, _, _ := strings.Cut(field.Tag.Get(key), ",")
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// fieldMessageID picks the catalog message for a failed rule. Length rules
+// read differently for text and lists than for numbers.
+func fieldMessageID(fieldErr validator.FieldError) string {
+	kind := "number"
+	switch fieldErr.Kind() {
+	case reflect.String:
+		kind = "string"
+	case reflect.Map, reflect.Slice, reflect.Array:
+		kind = "items"
+	}
+
+	switch fieldErr.Tag() {
+	case "required":
+		return i18n.MsgRequired
+	case "min", "max":
+		return fieldErr.Tag() + "." + kind
+	case "oneof":
+		return i18n.MsgOneOf
+	case "uuid":
+		return i18n.MsgUUID
+	default:
+		return i18n.MsgInvalid
+	}
 }
@@ -20,6 +20,20 @@ type UpdateVoucherRequest struct {
 	ExpiryDate      string  `json:"expiry_date" binding:"required"`
 }
 
+// PatchVoucherRequest is a JSON Merge Patch (RFC 7396) of a voucher. Only
+// the fields present in the patch are validated and changed.
+type PatchVoucherRequest struct {
+	// voucher_code, discount_percent, expiry_date, all optional
+	VoucherCode     *string  `json:"voucher_code" validate:"omitnil,min=1,max=255"`
+	DiscountPercent *float64 `json:"discount_percent" validate:"omitnil,min=0,max=100"`
+	ExpiryDate      *string  `json:"expiry_date" validate:"omitnil,min=1"`
+}
+
+// IsEmpty reports whether the patch changes nothing.
+func (r *PatchVoucherRequest) IsEmpty() bool {
+	return r.VoucherCode == nil && r.DiscountPercent == nil && r.ExpiryDate == nil
+}
+
 type VoucherResponse struct {
 	ID              pgtype.UUID `json:"id"`
 	VoucherCode     string      `json:"voucher_code"`
@@ -27,25 +41,156 @@ type VoucherResponse struct {
 	ExpiryDate      time.Time   `json:"expiry_date"`
 	CreatedAt       time.Time   `json:"created_at"`
 	UpdatedAt       time.Time   `json:"updated_at"`
+	// version, incremented on every update and sent as the ETag
+	Version int `json:"version"`
+}
+
+// VoucherFilterQuery holds the filters shared by the voucher list, export
+// and bulk endpoints. Dates accept the configured date formats; a date-only
+// value covers the whole day.
+type VoucherFilterQuery struct {
+	// search, search_mode (contains, prefix or fuzzy; contains by default)
+	Search     string `json:"search,omitempty" form:"search"`
+	SearchMode string `json:"search_mode,omitempty" form:"search_mode" validate:"omitempty,oneof=contains prefix fuzzy"`
+	// discount_min, discount_max, inclusive
+	DiscountMin *int `json:"discount_min,omitempty" form:"discount_min" validate:"omitempty,min=0,max=100"`
+	DiscountMax *int `json:"discount_max,omitempty" form:"discount_max" validate:"omitempty,min=0,max=100"`
+	// expiry_from, expiry_to, created_from, created_to, inclusive
+	ExpiryFrom  string `json:"expiry_from,omitempty" form:"expiry_from"`
+	ExpiryTo    string `json:"expiry_to,omitempty" form:"expiry_to"`
+	CreatedFrom string `json:"created_from,omitempty" form:"created_from"`
+	CreatedTo   string `json:"created_to,omitempty" form:"created_to"`
+	// status, active (not expired yet) or expired
+	Status string `json:"status,omitempty" form:"status" validate:"omitempty,oneof=active expired"`
+	// expiring_within_days, active vouchers expiring in the next N days
+	ExpiringWithinDays *int `json:"expiring_within_days,omitempty" form:"expiring_within_days" validate:"omitempty,min=1,max=3650"`
 }
 
 type VoucherListQuery struct {
-	// search, sort_by, sort_order, page, limit
-	Search    string `form:"search"`
-	SortBy    string `form:"sort_by" validate:"oneof=expiry_date discount_percent created_at updated_at"`
-	SortOrder string `form:"sort_order"`
+	VoucherFilterQuery
+	// sort, comma separated fields, "-" for descending, e.g.
+	// -discount_percent,expiry_date; replaces sort_by and sort_order
+	Sort string `form:"sort"`
+	// sort_by, sort_order, page, limit
+	SortBy    string `form:"sort_by" validate:"omitempty,oneof=voucher_code expiry_date discount_percent created_at updated_at relevance"`
+	SortOrder string `form:"sort_order" validate:"omitempty,oneof=asc desc"`
 	Page      int    `form:"page,default=1" validate:"min=1"`
-	Limit     int    `form:"limit" validate:"min=1,max=100"`
+	Limit     int    `form:"limit,default=10" validate:"min=1,max=100"`
+	// cursor, next_cursor or prev_cursor of a previous page; page is
+	// ignored when it is set
+	Cursor string `form:"cursor"`
+}
+
+type VoucherAutocompleteQuery struct {
+	// q, beginning of the voucher code; limit, maximum number of codes
+	Q     string `form:"q" binding:"required" validate:"max=255"`
+	Limit int    `form:"limit,default=10" validate:"min=1,max=50"`
+}
+
+type VoucherExportQuery struct {
+	VoucherFilterQuery
+	// sort, same as for the voucher list
+	Sort string `form:"sort"`
+	// sort_by, sort_order, format
+	SortBy    string `form:"sort_by" validate:"omitempty,oneof=voucher_code expiry_date discount_percent created_at updated_at relevance"`
+	SortOrder string `form:"sort_order" validate:"omitempty,oneof=asc desc"`
+	Format    string `form:"format,default=csv" validate:"oneof=csv xlsx ndjson"`
+	// profile, display for readable headers or import for a file that can be
+	// uploaded again as-is
+	Profile string `form:"profile,default=display" validate:"oneof=display import"`
+	// columns, comma separated columns to include (all of them when empty)
+	Columns string `form:"columns"`
+}
+
+type CSVUploadOptions struct {
+	// delimiter, encoding; both are detected from the file when empty
+	Delimiter string `form:"delimiter"`
+	Encoding  string `form:"encoding"`
+	// sheet, worksheet to read from an XLSX upload (defaults to the first one)
+	Sheet string `form:"sheet"`
+	// mapping_profile, ID or name of a saved import mapping profile
+	MappingProfile string `form:"mapping_profile"`
+	// timezone, IANA name used for dates without an offset
+	Timezone string `form:"timezone"`
+	// match_by_id, update the voucher named in the id column instead of
+	// creating a new one, at the version in the version column if any
+	MatchByID bool `form:"match_by_id"`
+}
+
+type JSONImportOptions struct {
+	// timezone, IANA name used for dates without an offset
+	Timezone string `form:"timezone"`
+	// match_by_id, update the voucher named in the id field instead of
+	// creating a new one, at the version in the version field if any
+	MatchByID bool `form:"match_by_id"`
 }
 
 type CSVUploadResponse struct {
+	ImportID     pgtype.UUID `json:"import_id"`
 	SuccessCount int         `json:"success_count"`
+	UpdatedCount int         `json:"updated_count"`
 	FailedCount  int         `json:"failed_count"`
 	FailedRows   []FailedRow `json:"failed_rows"`
+	Format       string      `json:"format"`
+	Delimiter    string      `json:"delimiter,omitempty"`
+	Encoding     string      `json:"encoding,omitempty"`
+	Timezone     string      `json:"timezone"`
 }
 
 type FailedRow struct {
-	RowNumber   int    `json:"row_number"`
-	VoucherCode string `json:"voucher_code"`
-	Reason      string `json:"reason"`
+	RowNumber      int    `json:"row_number"`
+	VoucherCode    string `json:"voucher_code"`
+	Reason         string `json:"reason"`
+	DuplicateOfRow int    `json:"duplicate_of_row,omitempty"`
+
+	// Record is the raw row as read from the file, kept for the error report.
+	Record []string `json:"-"`
+}
+
+// BulkVoucherRequest applies one operation to many vouchers, chosen either
+// by ID or by the same filters as the voucher list.
+type BulkVoucherRequest struct {
+	// operation: extend_expiry, set_discount, expire (set the expiry to now)
+	// or delete
+	Operation string `json:"operation" binding:"required" validate:"oneof=extend_expiry set_discount expire delete"`
+	// ids or filter, exactly one of them
+	IDs    []string            `json:"ids" validate:"omitempty,max=1000,dive,uuid"`
+	Filter *VoucherFilterQuery `json:"filter"`
+	// extend_by_days, for extend_expiry
+	ExtendByDays *int `json:"extend_by_days" validate:"omitnil,min=1,max=3650"`
+	// discount_percent, for set_discount
+	DiscountPercent *float64 `json:"discount_percent" validate:"omitnil,min=0,max=100"`
+	// max_affected, refuse to run when more vouchers match; 1000 at most
+	MaxAffected int `json:"max_affected" validate:"omitempty,min=1,max=1000"`
+	// dry_run, report what would change without changing anything
+	DryRun bool `json:"dry_run"`
+}
+
+type BulkVoucherResponse struct {
+	Operation string `json:"operation"`
+	DryRun    bool   `json:"dry_run"`
+	// matched, vouchers selected; affected, vouchers changed or deleted
+	Matched  int                 `json:"matched"`
+	Affected int                 `json:"affected"`
+	Results  []BulkVoucherResult `json:"results"`
+}
+
+// BulkVoucherResult is the outcome for one voucher: updated, deleted,
+// skipped when the operation would not change it, not_found, or conflict
+// when it was changed or deleted concurrently and was left alone.
+type BulkVoucherResult struct {
+	ID      string           `json:"id"`
+	Status  string           `json:"status"`
+	Message string           `json:"message,omitempty"`
+	Voucher *VoucherResponse `json:"voucher,omitempty"`
+}
+
+// VoucherEvent reports a change to a voucher: voucher.created,
+// voucher.updated, voucher.deleted or voucher.expired. Data is the voucher
+// after the change, or as it was when deleted.
+type VoucherEvent struct {
+	ID         string           `json:"id"`
+	Type       string           `json:"type"`
+	OccurredAt time.Time        `json:"occurred_at"`
+	Data       *VoucherResponse `json:"data"`
 }
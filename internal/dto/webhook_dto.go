@@ -0,0 +1,71 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type WebhookSubscriptionRequest struct {
+	// name, url (http or https)
+	Name string `json:"name" binding:"required" validate:"max=255"`
+	URL  string `json:"url" binding:"required" validate:"http_url,max=2048"`
+	// events, any of voucher.created, voucher.updated, voucher.deleted and
+	// voucher.expired
+	Events []string `json:"events" binding:"required" validate:"min=1,dive,oneof=voucher.created voucher.updated voucher.deleted voucher.expired"`
+	// secret, key of the payload signatures; generated when creating a
+	// subscription without one, kept when updating without one
+	Secret string `json:"secret" validate:"omitempty,min=16,max=255"`
+	// enabled, defaults to true
+	Enabled *bool `json:"enabled"`
+}
+
+type WebhookSubscriptionResponse struct {
+	ID      pgtype.UUID `json:"id"`
+	Name    string      `json:"name"`
+	URL     string      `json:"url"`
+	Events  []string    `json:"events"`
+	Enabled bool        `json:"enabled"`
+	// secret, only returned when it is set or generated
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type WebhookDeliveryQuery struct {
+	PaginationQuery
+	// status, pending, succeeded or dead
+	Status string `form:"status" validate:"omitempty,oneof=pending succeeded dead"`
+}
+
+// WebhookDeliveryResponse is one event sent to one subscription. It is
+// pending until the event is delivered (succeeded) or the attempts run out
+// (dead).
+type WebhookDeliveryResponse struct {
+	ID             pgtype.UUID `json:"id"`
+	SubscriptionID pgtype.UUID `json:"subscription_id"`
+	EventID        pgtype.UUID `json:"event_id"`
+	EventType      string      `json:"event_type"`
+	Status         string      `json:"status"`
+	Attempts       int         `json:"attempts"`
+	// next_attempt_at, while pending
+	NextAttemptAt *time.Time `json:"next_attempt_at"`
+	LastError     string     `json:"last_error,omitempty"`
+	// payload, the JSON body that is sent
+	Payload     json.RawMessage `json:"payload" swaggertype:"object"`
+	CreatedAt   time.Time       `json:"created_at"`
+	CompletedAt *time.Time      `json:"completed_at"`
+	// log, the attempts so far; only included for a single delivery
+	Log []WebhookDeliveryAttemptResponse `json:"log,omitempty"`
+}
+
+type WebhookDeliveryAttemptResponse struct {
+	Attempt int `json:"attempt"`
+	// status_code, 0 when there was no response
+	StatusCode   int       `json:"status_code"`
+	Error        string    `json:"error,omitempty"`
+	ResponseBody string    `json:"response_body,omitempty"`
+	DurationMS   int       `json:"duration_ms"`
+	AttemptedAt  time.Time `json:"attempted_at"`
+}
@@ -0,0 +1,41 @@
+// Package eventsink holds the destinations the outbox relay publishes
+// voucher events to.
+package eventsink
+
+import (
+	"fmt"
+
+	"github.com/alifdwt/techtest-indico-be/internal/config"
+	"github.com/alifdwt/techtest-indico-be/internal/service"
+)
+
+const (
+	SinkWebhook = "webhook"
+	SinkLog     = "log"
+	SinkNATS    = "nats"
+)
+
+// NewSinks builds the sinks named in cfg.Sinks, keyed by name. webhooks is
+// used as the webhook sink.
+func NewSinks(cfg config.OutboxConfig, webhooks service.VoucherEventPublisher) (map[string]service.VoucherEventPublisher, error) {
+	sinks := map[string]service.VoucherEventPublisher{}
+
+	for _, name := range cfg.Sinks {
+		switch name {
+		case SinkWebhook:
+			sinks[name] = webhooks
+		case SinkLog:
+			sinks[name] = NewLogSink()
+		case SinkNATS:
+			nats, err := NewNATSSink(cfg.NATS)
+			if err != nil {
+				return nil, err
+			}
+			sinks[name] = nats
+		default:
+			return nil, fmt.Errorf("unknown event sink '%s'", name)
+		}
+	}
+
+	return sinks, nil
+}
@@ -0,0 +1,27 @@
+package eventsink
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/alifdwt/techtest-indico-be/internal/dto"
+)
+
+// LogSink writes every event to the application log, for development or
+// as an audit trail.
+type LogSink struct{}
+
+func NewLogSink() *LogSink {
+	return &LogSink{}
+}
+
+func (l *LogSink) PublishVoucherEvent(ctx context.Context, event *dto.VoucherEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("event %s %s: %s", event.Type, event.ID, payload)
+	return nil
+}
@@ -0,0 +1,60 @@
+package eventsink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/alifdwt/techtest-indico-be/internal/config"
+	"github.com/alifdwt/techtest-indico-be/internal/dto"
+	"github.com/nats-io/nats.go"
+)
+
+// NATSSink publishes events to a NATS server.
+type NATSSink struct {
+	conn          *nats.Conn
+	subjectPrefix string
+}
+
+// NewNATSSink connects to the server in the background, so the API starts
+// while NATS is down; events wait in the outbox until it is reachable.
+func NewNATSSink(cfg config.NATSConfig) (*NATSSink, error) {
+	conn, err := nats.Connect(cfg.URL,
+		nats.Name("techtest-indico-be"),
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect to nats: %w", err)
+	}
+
+	return &NATSSink{
+		conn:          conn,
+		subjectPrefix: cfg.SubjectPrefix,
+	}, nil
+}
+
+// PublishVoucherEvent publishes the event as JSON on the subject
+// "<prefix>.<event type>". The event ID goes in the Nats-Msg-Id header,
+// which JetStream uses to drop duplicates. The connection is flushed, so
+// an event counts as published only once the server has it.
+func (n *NATSSink) PublishVoucherEvent(ctx context.Context, event *dto.VoucherEvent) error {
+	if !n.conn.IsConnected() {
+		return fmt.Errorf("not connected to nats (%s)", n.conn.Status())
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	msg := nats.NewMsg(n.subjectPrefix + "." + event.Type)
+	msg.Header.Set(nats.MsgIdHdr, event.ID)
+	msg.Data = payload
+
+	if err := n.conn.PublishMsg(msg); err != nil {
+		return err
+	}
+
+	return n.conn.FlushWithContext(ctx)
+}
@@ -0,0 +1,46 @@
+package graphqlapi
+
+import (
+	"log"
+
+	"github.com/alifdwt/techtest-indico-be/internal/apperror"
+)
+
+// queryError is an error reported in the errors of a response. Its code
+// and invalid fields go in the extensions, as in the REST problem body.
+type queryError struct {
+	message    string
+	extensions map[string]any
+}
+
+func (e *queryError) Error() string {
+	return e.message
+}
+
+func (e *queryError) Extensions() map[string]any {
+	return e.extensions
+}
+
+// resolverError converts the error of a resolver into a queryError.
+// Application errors keep their message and code; anything else is logged
+// and reported as a generic internal error.
+func resolverError(err error) error {
+	appErr, ok := apperror.As(err)
+	if !ok {
+		log.Printf("graphql: %v", err)
+		return &queryError{
+			message:    "An unexpected error occurred.",
+			extensions: map[string]any{"code": "internal_error"},
+		}
+	}
+	if appErr.Err != nil {
+		log.Printf("graphql: %v: %v", appErr, appErr.Err)
+	}
+
+	extensions := map[string]any{"code": appErr.Code}
+	if len(appErr.Fields) > 0 {
+		extensions["fields"] = appErr.Fields
+	}
+
+	return &queryError{message: appErr.Message, extensions: extensions}
+}
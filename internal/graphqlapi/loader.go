@@ -0,0 +1,102 @@
+package graphqlapi
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/alifdwt/techtest-indico-be/internal/dto"
+)
+
+// Batching of voucher lookups: loads made within loaderWait of the first
+// one share a query, up to loaderMaxBatch IDs.
+const (
+	loaderWait     = 2 * time.Millisecond
+	loaderMaxBatch = 100
+)
+
+type fetchVouchers func(ctx context.Context, ids []string) (map[string]*dto.VoucherResponse, error)
+
+// voucherLoader collects the voucher IDs that resolvers ask for while a
+// request runs and loads them in batches, instead of one query per field.
+// Results are cached for the rest of the request.
+type voucherLoader struct {
+	fetch fetchVouchers
+
+	mu      sync.Mutex
+	batch   *voucherBatch
+	batches map[string]*voucherBatch
+}
+
+type voucherBatch struct {
+	ids        []string
+	dispatched bool
+	done       chan struct{}
+
+	vouchers map[string]*dto.VoucherResponse
+	err      error
+}
+
+func newVoucherLoader(fetch fetchVouchers) *voucherLoader {
+	return &voucherLoader{
+		fetch:   fetch,
+		batches: map[string]*voucherBatch{},
+	}
+}
+
+// Load returns the voucher with the given ID, or nil when there is none.
+func (l *voucherLoader) Load(ctx context.Context, id string) (*dto.VoucherResponse, error) {
+	l.mu.Lock()
+	batch, ok := l.batches[id]
+	if !ok {
+		if l.batch == nil {
+			l.batch = &voucherBatch{done: make(chan struct{})}
+			b := l.batch
+			time.AfterFunc(loaderWait, func() { l.dispatch(ctx, b) })
+		}
+		batch = l.batch
+		batch.ids = append(batch.ids, id)
+		l.batches[id] = batch
+	}
+	full := len(batch.ids) >= loaderMaxBatch
+	l.mu.Unlock()
+
+	if full {
+		l.dispatch(ctx, batch)
+	}
+
+	select {
+	case <-batch.done:
+		return batch.vouchers[id], batch.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// dispatch runs the query of a batch once, whether the wait ran out or the
+// batch filled up first.
+func (l *voucherLoader) dispatch(ctx context.Context, batch *voucherBatch) {
+	l.mu.Lock()
+	if batch.dispatched {
+		l.mu.Unlock()
+		return
+	}
+	batch.dispatched = true
+	if l.batch == batch {
+		l.batch = nil
+	}
+	l.mu.Unlock()
+
+	batch.vouchers, batch.err = l.fetch(ctx, batch.ids)
+	close(batch.done)
+}
+
+type voucherLoaderKey struct{}
+
+func withVoucherLoader(ctx context.Context, loader *voucherLoader) context.Context {
+	return context.WithValue(ctx, voucherLoaderKey{}, loader)
+}
+
+func voucherLoaderFrom(ctx context.Context) *voucherLoader {
+	return ctx.Value(voucherLoaderKey{}).(*voucherLoader)
+}
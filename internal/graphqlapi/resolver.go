@@ -0,0 +1,255 @@
+package graphqlapi
+
+import (
+	"context"
+	"time"
+
+	"github.com/alifdwt/techtest-indico-be/internal/apperror"
+	"github.com/alifdwt/techtest-indico-be/internal/dto"
+	"github.com/alifdwt/techtest-indico-be/internal/service"
+	"github.com/alifdwt/techtest-indico-be/internal/util"
+	"github.com/google/uuid"
+	"github.com/graph-gophers/graphql-go"
+)
+
+type queryResolver struct {
+	voucherService *service.VoucherService
+}
+
+func (r *queryResolver) Voucher(ctx context.Context, args struct{ ID graphql.ID }) (*voucherResolver, error) {
+	// Checked here so that a malformed ID fails only its own field rather
+	// than the whole batch it would be loaded with.
+	id, err := uuid.Parse(string(args.ID))
+	if err != nil {
+		return nil, resolverError(apperror.InvalidID("invalid_voucher_id", "invalid voucher id"))
+	}
+
+	voucher, err := voucherLoaderFrom(ctx).Load(ctx, id.String())
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	if voucher == nil {
+		return nil, nil
+	}
+
+	return &voucherResolver{voucher: voucher}, nil
+}
+
+type vouchersArgs struct {
+	Filter *voucherFilter
+	Sort   *string
+	Limit  int32
+	Cursor *string
+}
+
+func (r *queryResolver) Vouchers(ctx context.Context, args vouchersArgs) (*voucherConnectionResolver, error) {
+	query := dto.VoucherListQuery{
+		VoucherFilterQuery: args.Filter.query(),
+		Sort:               stringValue(args.Sort),
+		Page:               1,
+		Limit:              int(args.Limit),
+		Cursor:             stringValue(args.Cursor),
+	}
+	if err := dto.ValidateRequest(&query); err != nil {
+		return nil, resolverError(err)
+	}
+
+	vouchers, meta, err := r.voucherService.ListVouchers(ctx, &query)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+
+	return &voucherConnectionResolver{vouchers: vouchers, meta: meta}, nil
+}
+
+func (r *queryResolver) VoucherStats(ctx context.Context, args struct{ Filter *voucherFilter }) (*voucherStatsResolver, error) {
+	filter := args.Filter.query()
+	if err := dto.ValidateRequest(&filter); err != nil {
+		return nil, resolverError(err)
+	}
+
+	return &voucherStatsResolver{voucherService: r.voucherService, filter: filter}, nil
+}
+
+// voucherFilter is the VoucherFilter input.
+type voucherFilter struct {
+	Search             *string
+	SearchMode         *string
+	DiscountMin        *int32
+	DiscountMax        *int32
+	ExpiryFrom         *string
+	ExpiryTo           *string
+	CreatedFrom        *string
+	CreatedTo          *string
+	Status             *string
+	ExpiringWithinDays *int32
+}
+
+func (f *voucherFilter) query() dto.VoucherFilterQuery {
+	if f == nil {
+		return dto.VoucherFilterQuery{}
+	}
+
+	return dto.VoucherFilterQuery{
+		Search:             stringValue(f.Search),
+		SearchMode:         stringValue(f.SearchMode),
+		DiscountMin:        intPtr(f.DiscountMin),
+		DiscountMax:        intPtr(f.DiscountMax),
+		ExpiryFrom:         stringValue(f.ExpiryFrom),
+		ExpiryTo:           stringValue(f.ExpiryTo),
+		CreatedFrom:        stringValue(f.CreatedFrom),
+		CreatedTo:          stringValue(f.CreatedTo),
+		Status:             stringValue(f.Status),
+		ExpiringWithinDays: intPtr(f.ExpiringWithinDays),
+	}
+}
+
+type voucherResolver struct {
+	voucher *dto.VoucherResponse
+}
+
+func (r *voucherResolver) ID() graphql.ID {
+	return graphql.ID(uuid.UUID(r.voucher.ID.Bytes).String())
+}
+
+func (r *voucherResolver) VoucherCode() string {
+	return r.voucher.VoucherCode
+}
+
+func (r *voucherResolver) DiscountPercent() int32 {
+	return int32(r.voucher.DiscountPercent)
+}
+
+func (r *voucherResolver) ExpiryDate() graphql.Time {
+	return graphql.Time{Time: r.voucher.ExpiryDate}
+}
+
+func (r *voucherResolver) Status() string {
+	if r.voucher.ExpiryDate.Before(time.Now()) {
+		return "expired"
+	}
+
+	return "active"
+}
+
+func (r *voucherResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: r.voucher.CreatedAt}
+}
+
+func (r *voucherResolver) UpdatedAt() graphql.Time {
+	return graphql.Time{Time: r.voucher.UpdatedAt}
+}
+
+func (r *voucherResolver) Version() int32 {
+	return int32(r.voucher.Version)
+}
+
+type voucherConnectionResolver struct {
+	vouchers []*dto.VoucherResponse
+	meta     util.Meta
+}
+
+func (r *voucherConnectionResolver) Nodes() []*voucherResolver {
+	nodes := make([]*voucherResolver, 0, len(r.vouchers))
+	for _, voucher := range r.vouchers {
+		nodes = append(nodes, &voucherResolver{voucher: voucher})
+	}
+
+	return nodes
+}
+
+func (r *voucherConnectionResolver) TotalCount() int32 {
+	return int32(r.meta.Total)
+}
+
+func (r *voucherConnectionResolver) PageInfo() *pageInfoResolver {
+	return &pageInfoResolver{meta: r.meta}
+}
+
+type pageInfoResolver struct {
+	meta util.Meta
+}
+
+func (r *pageInfoResolver) HasNextPage() bool {
+	return r.meta.HasNext
+}
+
+func (r *pageInfoResolver) HasPreviousPage() bool {
+	return r.meta.HasPrev
+}
+
+func (r *pageInfoResolver) NextCursor() *string {
+	return stringPtr(r.meta.NextCursor)
+}
+
+func (r *pageInfoResolver) PrevCursor() *string {
+	return stringPtr(r.meta.PrevCursor)
+}
+
+// voucherStatsResolver counts vouchers field by field, so a query only pays
+// for the counts it selects.
+type voucherStatsResolver struct {
+	voucherService *service.VoucherService
+	filter         dto.VoucherFilterQuery
+}
+
+func (r *voucherStatsResolver) Total(ctx context.Context) (int32, error) {
+	return r.count(ctx, r.filter)
+}
+
+func (r *voucherStatsResolver) Active(ctx context.Context) (int32, error) {
+	filter := r.filter
+	filter.Status = "active"
+	return r.count(ctx, filter)
+}
+
+func (r *voucherStatsResolver) Expired(ctx context.Context) (int32, error) {
+	filter := r.filter
+	filter.Status = "expired"
+	return r.count(ctx, filter)
+}
+
+func (r *voucherStatsResolver) ExpiringWithinDays(ctx context.Context, args struct{ Days int32 }) (int32, error) {
+	days := int(args.Days)
+	filter := r.filter
+	filter.ExpiringWithinDays = &days
+	if err := dto.ValidateRequest(&filter); err != nil {
+		return 0, resolverError(err)
+	}
+
+	return r.count(ctx, filter)
+}
+
+func (r *voucherStatsResolver) count(ctx context.Context, filter dto.VoucherFilterQuery) (int32, error) {
+	total, err := r.voucherService.CountVouchers(ctx, &filter)
+	if err != nil {
+		return 0, resolverError(err)
+	}
+
+	return int32(total), nil
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+
+	return *value
+}
+
+func stringPtr(value string) *string {
+	if value == "" {
+		return nil
+	}
+
+	return &value
+}
+
+func intPtr(value *int32) *int {
+	if value == nil {
+		return nil
+	}
+
+	converted := int(*value)
+	return &converted
+}
@@ -0,0 +1,42 @@
+// Package graphqlapi serves vouchers and voucher statistics over GraphQL
+// for the admin dashboard. Resolvers go through the same services, and so
+// the same validation and filters, as the REST and gRPC APIs.
+package graphqlapi
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/alifdwt/techtest-indico-be/internal/service"
+	"github.com/graph-gophers/graphql-go"
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+// maxDepth limits how deeply queries may nest; the schema itself is
+// shallow, so this only stops abusive introspection queries.
+const maxDepth = 10
+
+// Schema executes GraphQL requests.
+type Schema struct {
+	schema         *graphql.Schema
+	voucherService *service.VoucherService
+}
+
+func NewSchema(voucherService *service.VoucherService) *Schema {
+	resolver := &queryResolver{voucherService: voucherService}
+
+	return &Schema{
+		schema:         graphql.MustParseSchema(schemaSDL, resolver, graphql.MaxDepth(maxDepth)),
+		voucherService: voucherService,
+	}
+}
+
+// Exec runs one request. Each request gets its own voucher loader, so
+// batching and caching never cross requests.
+func (s *Schema) Exec(ctx context.Context, query, operationName string, variables map[string]any) *graphql.Response {
+	ctx = withVoucherLoader(ctx, newVoucherLoader(s.voucherService.GetVouchersByID))
+
+	return s.schema.Exec(ctx, query, operationName, variables)
+}
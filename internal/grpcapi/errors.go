@@ -0,0 +1,77 @@
+package grpcapi
+
+import (
+	"errors"
+	"log"
+
+	"github.com/alifdwt/techtest-indico-be/internal/apperror"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain is the domain of the ErrorInfo details of status errors.
+const errorDomain = "techtest-indico-be"
+
+// statusError converts an error into a gRPC status error. Application
+// errors keep their message, carry their code as the ErrorInfo reason and
+// invalid fields as BadRequest field violations; anything else is logged
+// and reported as a generic internal error, as in the REST API.
+func statusError(method string, err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	appErr, ok := apperror.As(err)
+	if !ok {
+		log.Printf("grpc %s: %v", method, err)
+		return status.Error(codes.Internal, "An unexpected error occurred.")
+	}
+	if appErr.Err != nil {
+		log.Printf("grpc %s: %v: %v", method, appErr, appErr.Err)
+	}
+
+	st := status.New(Code(err), appErr.Message)
+	if withInfo, err := st.WithDetails(&errdetails.ErrorInfo{Reason: appErr.Code, Domain: errorDomain}); err == nil {
+		st = withInfo
+	}
+
+	if len(appErr.Fields) > 0 {
+		badRequest := &errdetails.BadRequest{}
+		for _, field := range appErr.Fields {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       field.Field,
+				Description: field.Message,
+			})
+		}
+		if withFields, err := st.WithDetails(badRequest); err == nil {
+			st = withFields
+		}
+	}
+
+	return st.Err()
+}
+
+// Code maps an application error to its gRPC status code.
+func Code(err error) codes.Code {
+	switch {
+	case errors.Is(err, apperror.ErrNotFound):
+		return codes.NotFound
+	case errors.Is(err, apperror.ErrConflict):
+		return codes.AlreadyExists
+	case errors.Is(err, apperror.ErrValidation),
+		errors.Is(err, apperror.ErrInvalidID),
+		errors.Is(err, apperror.ErrBadRequest),
+		errors.Is(err, apperror.ErrUnsupportedMediaType):
+		return codes.InvalidArgument
+	case errors.Is(err, apperror.ErrUnauthorized):
+		return codes.Unauthenticated
+	case errors.Is(err, apperror.ErrForbidden):
+		return codes.PermissionDenied
+	case errors.Is(err, apperror.ErrPreconditionFailed),
+		errors.Is(err, apperror.ErrPreconditionRequired):
+		return codes.FailedPrecondition
+	default:
+		return codes.Internal
+	}
+}
@@ -0,0 +1,94 @@
+// Package grpcapi serves the voucher operations over gRPC, next to the
+// REST API and on top of the same service layer and authentication.
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/alifdwt/techtest-indico-be/internal/grpcapi/voucherpb"
+	"github.com/alifdwt/techtest-indico-be/internal/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+)
+
+type Server struct {
+	server *grpc.Server
+	health *health.Server
+}
+
+// NewServer sets up the gRPC server with the voucher service, the standard
+// health service and server reflection.
+func NewServer(voucherService *service.VoucherService) *Server {
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(errorInterceptor, authInterceptor))
+
+	voucherpb.RegisterVoucherServiceServer(server, &voucherServer{voucherService: voucherService})
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(voucherpb.VoucherService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	reflection.Register(server)
+
+	return &Server{server: server, health: healthServer}
+}
+
+// Serve accepts connections on listener until the server is stopped.
+func (s *Server) Serve(listener net.Listener) error {
+	return s.server.Serve(listener)
+}
+
+// Stop reports the server as not serving to health checks and waits for
+// running calls to finish, cancelling them when ctx ends first.
+func (s *Server) Stop(ctx context.Context) error {
+	s.health.Shutdown()
+
+	done := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+		return ctx.Err()
+	}
+}
+
+// authInterceptor requires the bearer token in the authorization metadata
+// of voucher calls. Health checks need no credentials.
+func authInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if !strings.HasPrefix(info.FullMethod, "/"+voucherpb.VoucherService_ServiceDesc.ServiceName+"/") {
+		return handler(ctx, req)
+	}
+
+	var authorization string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			authorization = values[0]
+		}
+	}
+
+	if err := service.Authenticate(authorization); err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+// errorInterceptor turns the errors of a call into gRPC status errors.
+func errorInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	res, err := handler(ctx, req)
+	if err != nil {
+		return nil, statusError(info.FullMethod, err)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,160 @@
+package grpcapi
+
+import (
+	"context"
+
+	"github.com/alifdwt/techtest-indico-be/internal/dto"
+	"github.com/alifdwt/techtest-indico-be/internal/grpcapi/voucherpb"
+	"github.com/alifdwt/techtest-indico-be/internal/service"
+	"github.com/alifdwt/techtest-indico-be/internal/util"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// voucherServer implements voucherpb.VoucherServiceServer by translating
+// calls to the DTOs of the REST API, so both share validation and rules.
+type voucherServer struct {
+	voucherpb.UnimplementedVoucherServiceServer
+	voucherService *service.VoucherService
+}
+
+func (vs *voucherServer) CreateVoucher(ctx context.Context, req *voucherpb.CreateVoucherRequest) (*voucherpb.Voucher, error) {
+	createReq := dto.CreateVoucherRequest{
+		VoucherCode:     req.GetVoucherCode(),
+		DiscountPercent: req.GetDiscountPercent(),
+		ExpiryDate:      req.GetExpiryDate(),
+	}
+	if err := dto.ValidateRequest(&createReq); err != nil {
+		return nil, err
+	}
+
+	res, err := vs.voucherService.CreateVoucher(ctx, &createReq)
+	if err != nil {
+		return nil, err
+	}
+
+	return toVoucher(res), nil
+}
+
+func (vs *voucherServer) GetVoucher(ctx context.Context, req *voucherpb.GetVoucherRequest) (*voucherpb.Voucher, error) {
+	res, err := vs.voucherService.GetVoucherByID(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	return toVoucher(res), nil
+}
+
+func (vs *voucherServer) UpdateVoucher(ctx context.Context, req *voucherpb.UpdateVoucherRequest) (*voucherpb.Voucher, error) {
+	updateReq := dto.UpdateVoucherRequest{
+		VoucherCode:     req.GetVoucherCode(),
+		DiscountPercent: req.GetDiscountPercent(),
+		ExpiryDate:      req.GetExpiryDate(),
+	}
+	if err := dto.ValidateRequest(&updateReq); err != nil {
+		return nil, err
+	}
+
+	res, err := vs.voucherService.UpdateVoucher(ctx, req.GetId(), &updateReq, ifMatch(req.ExpectedVersion))
+	if err != nil {
+		return nil, err
+	}
+
+	return toVoucher(res), nil
+}
+
+func (vs *voucherServer) DeleteVoucher(ctx context.Context, req *voucherpb.DeleteVoucherRequest) (*voucherpb.DeleteVoucherResponse, error) {
+	if err := vs.voucherService.DeleteVoucher(ctx, req.GetId(), ifMatch(req.ExpectedVersion)); err != nil {
+		return nil, err
+	}
+
+	return &voucherpb.DeleteVoucherResponse{}, nil
+}
+
+func (vs *voucherServer) ListVouchers(ctx context.Context, req *voucherpb.ListVouchersRequest) (*voucherpb.ListVouchersResponse, error) {
+	query := dto.VoucherListQuery{
+		VoucherFilterQuery: dto.VoucherFilterQuery{
+			Search:             req.GetSearch(),
+			SearchMode:         req.GetSearchMode(),
+			DiscountMin:        intPtr(req.DiscountMin),
+			DiscountMax:        intPtr(req.DiscountMax),
+			ExpiryFrom:         req.GetExpiryFrom(),
+			ExpiryTo:           req.GetExpiryTo(),
+			CreatedFrom:        req.GetCreatedFrom(),
+			CreatedTo:          req.GetCreatedTo(),
+			Status:             req.GetStatus(),
+			ExpiringWithinDays: intPtr(req.ExpiringWithinDays),
+		},
+		Sort:      req.GetSort(),
+		SortBy:    req.GetSortBy(),
+		SortOrder: req.GetSortOrder(),
+		Page:      int(req.GetPage()),
+		Limit:     int(req.GetLimit()),
+		Cursor:    req.GetCursor(),
+	}
+	// The same defaults as the query parameters of GET /vouchers.
+	if query.Page == 0 {
+		query.Page = 1
+	}
+	if query.Limit == 0 {
+		query.Limit = 10
+	}
+	if err := dto.ValidateRequest(&query); err != nil {
+		return nil, err
+	}
+
+	vouchers, meta, err := vs.voucherService.ListVouchers(ctx, &query)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &voucherpb.ListVouchersResponse{
+		Vouchers: make([]*voucherpb.Voucher, 0, len(vouchers)),
+		Meta: &voucherpb.PageMeta{
+			Total:      int64(meta.Total),
+			Page:       int32(meta.Page),
+			Limit:      int32(meta.Limit),
+			TotalPages: int32(meta.TotalPages),
+			HasNext:    meta.HasNext,
+			HasPrev:    meta.HasPrev,
+			NextCursor: meta.NextCursor,
+			PrevCursor: meta.PrevCursor,
+		},
+	}
+	for _, voucher := range vouchers {
+		res.Vouchers = append(res.Vouchers, toVoucher(voucher))
+	}
+
+	return res, nil
+}
+
+func toVoucher(res *dto.VoucherResponse) *voucherpb.Voucher {
+	return &voucherpb.Voucher{
+		Id:              uuid.UUID(res.ID.Bytes).String(),
+		VoucherCode:     res.VoucherCode,
+		DiscountPercent: int32(res.DiscountPercent),
+		ExpiryDate:      timestamppb.New(res.ExpiryDate),
+		CreatedAt:       timestamppb.New(res.CreatedAt),
+		UpdatedAt:       timestamppb.New(res.UpdatedAt),
+		Version:         int32(res.Version),
+	}
+}
+
+// ifMatch turns an expected version into the If-Match value the service
+// takes; no version means no precondition.
+func ifMatch(expectedVersion *int32) string {
+	if expectedVersion == nil {
+		return ""
+	}
+
+	return util.ETag(int(*expectedVersion))
+}
+
+func intPtr(value *int32) *int {
+	if value == nil {
+		return nil
+	}
+
+	converted := int(*value)
+	return &converted
+}
@@ -0,0 +1,868 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.32.0
+// source: voucher/v1/voucher.proto
+
+package voucherpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Voucher struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	VoucherCode     string                 `protobuf:"bytes,2,opt,name=voucher_code,json=voucherCode,proto3" json:"voucher_code,omitempty"`
+	DiscountPercent int32                  `protobuf:"varint,3,opt,name=discount_percent,json=discountPercent,proto3" json:"discount_percent,omitempty"`
+	ExpiryDate      *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=expiry_date,json=expiryDate,proto3" json:"expiry_date,omitempty"`
+	CreatedAt       *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt       *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// Incremented on every update; pass it as expected_version to detect
+	// concurrent changes.
+	Version       int32 `protobuf:"varint,7,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Voucher) Reset() {
+	*x = Voucher{}
+	mi := &file_voucher_v1_voucher_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Voucher) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Voucher) ProtoMessage() {}
+
+func (x *Voucher) ProtoReflect() protoreflect.Message {
+	mi := &file_voucher_v1_voucher_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Voucher.ProtoReflect.Descriptor instead.
+func (*Voucher) Descriptor() ([]byte, []int) {
+	return file_voucher_v1_voucher_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Voucher) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Voucher) GetVoucherCode() string {
+	if x != nil {
+		return x.VoucherCode
+	}
+	return ""
+}
+
+func (x *Voucher) GetDiscountPercent() int32 {
+	if x != nil {
+		return x.DiscountPercent
+	}
+	return 0
+}
+
+func (x *Voucher) GetExpiryDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiryDate
+	}
+	return nil
+}
+
+func (x *Voucher) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Voucher) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Voucher) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type CreateVoucherRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	VoucherCode     string                 `protobuf:"bytes,1,opt,name=voucher_code,json=voucherCode,proto3" json:"voucher_code,omitempty"`
+	DiscountPercent float64                `protobuf:"fixed64,2,opt,name=discount_percent,json=discountPercent,proto3" json:"discount_percent,omitempty"`
+	// Any of the accepted date formats, e.g. 2026-12-31.
+	ExpiryDate    string `protobuf:"bytes,3,opt,name=expiry_date,json=expiryDate,proto3" json:"expiry_date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateVoucherRequest) Reset() {
+	*x = CreateVoucherRequest{}
+	mi := &file_voucher_v1_voucher_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateVoucherRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateVoucherRequest) ProtoMessage() {}
+
+func (x *CreateVoucherRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_voucher_v1_voucher_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateVoucherRequest.ProtoReflect.Descriptor instead.
+func (*CreateVoucherRequest) Descriptor() ([]byte, []int) {
+	return file_voucher_v1_voucher_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateVoucherRequest) GetVoucherCode() string {
+	if x != nil {
+		return x.VoucherCode
+	}
+	return ""
+}
+
+func (x *CreateVoucherRequest) GetDiscountPercent() float64 {
+	if x != nil {
+		return x.DiscountPercent
+	}
+	return 0
+}
+
+func (x *CreateVoucherRequest) GetExpiryDate() string {
+	if x != nil {
+		return x.ExpiryDate
+	}
+	return ""
+}
+
+type GetVoucherRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetVoucherRequest) Reset() {
+	*x = GetVoucherRequest{}
+	mi := &file_voucher_v1_voucher_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetVoucherRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVoucherRequest) ProtoMessage() {}
+
+func (x *GetVoucherRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_voucher_v1_voucher_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVoucherRequest.ProtoReflect.Descriptor instead.
+func (*GetVoucherRequest) Descriptor() ([]byte, []int) {
+	return file_voucher_v1_voucher_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetVoucherRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type UpdateVoucherRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	VoucherCode     string                 `protobuf:"bytes,2,opt,name=voucher_code,json=voucherCode,proto3" json:"voucher_code,omitempty"`
+	DiscountPercent float64                `protobuf:"fixed64,3,opt,name=discount_percent,json=discountPercent,proto3" json:"discount_percent,omitempty"`
+	ExpiryDate      string                 `protobuf:"bytes,4,opt,name=expiry_date,json=expiryDate,proto3" json:"expiry_date,omitempty"`
+	// When set, the update fails with FAILED_PRECONDITION unless the voucher
+	// still has this version.
+	ExpectedVersion *int32 `protobuf:"varint,5,opt,name=expected_version,json=expectedVersion,proto3,oneof" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *UpdateVoucherRequest) Reset() {
+	*x = UpdateVoucherRequest{}
+	mi := &file_voucher_v1_voucher_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateVoucherRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateVoucherRequest) ProtoMessage() {}
+
+func (x *UpdateVoucherRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_voucher_v1_voucher_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateVoucherRequest.ProtoReflect.Descriptor instead.
+func (*UpdateVoucherRequest) Descriptor() ([]byte, []int) {
+	return file_voucher_v1_voucher_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *UpdateVoucherRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateVoucherRequest) GetVoucherCode() string {
+	if x != nil {
+		return x.VoucherCode
+	}
+	return ""
+}
+
+func (x *UpdateVoucherRequest) GetDiscountPercent() float64 {
+	if x != nil {
+		return x.DiscountPercent
+	}
+	return 0
+}
+
+func (x *UpdateVoucherRequest) GetExpiryDate() string {
+	if x != nil {
+		return x.ExpiryDate
+	}
+	return ""
+}
+
+func (x *UpdateVoucherRequest) GetExpectedVersion() int32 {
+	if x != nil && x.ExpectedVersion != nil {
+		return *x.ExpectedVersion
+	}
+	return 0
+}
+
+type DeleteVoucherRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ExpectedVersion *int32                 `protobuf:"varint,2,opt,name=expected_version,json=expectedVersion,proto3,oneof" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *DeleteVoucherRequest) Reset() {
+	*x = DeleteVoucherRequest{}
+	mi := &file_voucher_v1_voucher_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteVoucherRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteVoucherRequest) ProtoMessage() {}
+
+func (x *DeleteVoucherRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_voucher_v1_voucher_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteVoucherRequest.ProtoReflect.Descriptor instead.
+func (*DeleteVoucherRequest) Descriptor() ([]byte, []int) {
+	return file_voucher_v1_voucher_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DeleteVoucherRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeleteVoucherRequest) GetExpectedVersion() int32 {
+	if x != nil && x.ExpectedVersion != nil {
+		return *x.ExpectedVersion
+	}
+	return 0
+}
+
+type DeleteVoucherResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteVoucherResponse) Reset() {
+	*x = DeleteVoucherResponse{}
+	mi := &file_voucher_v1_voucher_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteVoucherResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteVoucherResponse) ProtoMessage() {}
+
+func (x *DeleteVoucherResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_voucher_v1_voucher_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteVoucherResponse.ProtoReflect.Descriptor instead.
+func (*DeleteVoucherResponse) Descriptor() ([]byte, []int) {
+	return file_voucher_v1_voucher_proto_rawDescGZIP(), []int{5}
+}
+
+type ListVouchersRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Search string                 `protobuf:"bytes,1,opt,name=search,proto3" json:"search,omitempty"`
+	// contains (default), prefix or fuzzy.
+	SearchMode  string `protobuf:"bytes,2,opt,name=search_mode,json=searchMode,proto3" json:"search_mode,omitempty"`
+	DiscountMin *int32 `protobuf:"varint,3,opt,name=discount_min,json=discountMin,proto3,oneof" json:"discount_min,omitempty"`
+	DiscountMax *int32 `protobuf:"varint,4,opt,name=discount_max,json=discountMax,proto3,oneof" json:"discount_max,omitempty"`
+	ExpiryFrom  string `protobuf:"bytes,5,opt,name=expiry_from,json=expiryFrom,proto3" json:"expiry_from,omitempty"`
+	ExpiryTo    string `protobuf:"bytes,6,opt,name=expiry_to,json=expiryTo,proto3" json:"expiry_to,omitempty"`
+	CreatedFrom string `protobuf:"bytes,7,opt,name=created_from,json=createdFrom,proto3" json:"created_from,omitempty"`
+	CreatedTo   string `protobuf:"bytes,8,opt,name=created_to,json=createdTo,proto3" json:"created_to,omitempty"`
+	// active or expired.
+	Status             string `protobuf:"bytes,9,opt,name=status,proto3" json:"status,omitempty"`
+	ExpiringWithinDays *int32 `protobuf:"varint,10,opt,name=expiring_within_days,json=expiringWithinDays,proto3,oneof" json:"expiring_within_days,omitempty"`
+	// Comma separated fields, "-" for descending, e.g. -discount_percent.
+	Sort      string `protobuf:"bytes,11,opt,name=sort,proto3" json:"sort,omitempty"`
+	SortBy    string `protobuf:"bytes,12,opt,name=sort_by,json=sortBy,proto3" json:"sort_by,omitempty"`
+	SortOrder string `protobuf:"bytes,13,opt,name=sort_order,json=sortOrder,proto3" json:"sort_order,omitempty"`
+	// page defaults to 1 and limit to 10; a cursor replaces page.
+	Page          int32  `protobuf:"varint,14,opt,name=page,proto3" json:"page,omitempty"`
+	Limit         int32  `protobuf:"varint,15,opt,name=limit,proto3" json:"limit,omitempty"`
+	Cursor        string `protobuf:"bytes,16,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListVouchersRequest) Reset() {
+	*x = ListVouchersRequest{}
+	mi := &file_voucher_v1_voucher_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListVouchersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVouchersRequest) ProtoMessage() {}
+
+func (x *ListVouchersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_voucher_v1_voucher_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVouchersRequest.ProtoReflect.Descriptor instead.
+func (*ListVouchersRequest) Descriptor() ([]byte, []int) {
+	return file_voucher_v1_voucher_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListVouchersRequest) GetSearch() string {
+	if x != nil {
+		return x.Search
+	}
+	return ""
+}
+
+func (x *ListVouchersRequest) GetSearchMode() string {
+	if x != nil {
+		return x.SearchMode
+	}
+	return ""
+}
+
+func (x *ListVouchersRequest) GetDiscountMin() int32 {
+	if x != nil && x.DiscountMin != nil {
+		return *x.DiscountMin
+	}
+	return 0
+}
+
+func (x *ListVouchersRequest) GetDiscountMax() int32 {
+	if x != nil && x.DiscountMax != nil {
+		return *x.DiscountMax
+	}
+	return 0
+}
+
+func (x *ListVouchersRequest) GetExpiryFrom() string {
+	if x != nil {
+		return x.ExpiryFrom
+	}
+	return ""
+}
+
+func (x *ListVouchersRequest) GetExpiryTo() string {
+	if x != nil {
+		return x.ExpiryTo
+	}
+	return ""
+}
+
+func (x *ListVouchersRequest) GetCreatedFrom() string {
+	if x != nil {
+		return x.CreatedFrom
+	}
+	return ""
+}
+
+func (x *ListVouchersRequest) GetCreatedTo() string {
+	if x != nil {
+		return x.CreatedTo
+	}
+	return ""
+}
+
+func (x *ListVouchersRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListVouchersRequest) GetExpiringWithinDays() int32 {
+	if x != nil && x.ExpiringWithinDays != nil {
+		return *x.ExpiringWithinDays
+	}
+	return 0
+}
+
+func (x *ListVouchersRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *ListVouchersRequest) GetSortBy() string {
+	if x != nil {
+		return x.SortBy
+	}
+	return ""
+}
+
+func (x *ListVouchersRequest) GetSortOrder() string {
+	if x != nil {
+		return x.SortOrder
+	}
+	return ""
+}
+
+func (x *ListVouchersRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListVouchersRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListVouchersRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+type ListVouchersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Vouchers      []*Voucher             `protobuf:"bytes,1,rep,name=vouchers,proto3" json:"vouchers,omitempty"`
+	Meta          *PageMeta              `protobuf:"bytes,2,opt,name=meta,proto3" json:"meta,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListVouchersResponse) Reset() {
+	*x = ListVouchersResponse{}
+	mi := &file_voucher_v1_voucher_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListVouchersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVouchersResponse) ProtoMessage() {}
+
+func (x *ListVouchersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_voucher_v1_voucher_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVouchersResponse.ProtoReflect.Descriptor instead.
+func (*ListVouchersResponse) Descriptor() ([]byte, []int) {
+	return file_voucher_v1_voucher_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListVouchersResponse) GetVouchers() []*Voucher {
+	if x != nil {
+		return x.Vouchers
+	}
+	return nil
+}
+
+func (x *ListVouchersResponse) GetMeta() *PageMeta {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+type PageMeta struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Total         int64                  `protobuf:"varint,1,opt,name=total,proto3" json:"total,omitempty"`
+	Page          int32                  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	TotalPages    int32                  `protobuf:"varint,4,opt,name=total_pages,json=totalPages,proto3" json:"total_pages,omitempty"`
+	HasNext       bool                   `protobuf:"varint,5,opt,name=has_next,json=hasNext,proto3" json:"has_next,omitempty"`
+	HasPrev       bool                   `protobuf:"varint,6,opt,name=has_prev,json=hasPrev,proto3" json:"has_prev,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,7,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	PrevCursor    string                 `protobuf:"bytes,8,opt,name=prev_cursor,json=prevCursor,proto3" json:"prev_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PageMeta) Reset() {
+	*x = PageMeta{}
+	mi := &file_voucher_v1_voucher_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PageMeta) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PageMeta) ProtoMessage() {}
+
+func (x *PageMeta) ProtoReflect() protoreflect.Message {
+	mi := &file_voucher_v1_voucher_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PageMeta.ProtoReflect.Descriptor instead.
+func (*PageMeta) Descriptor() ([]byte, []int) {
+	return file_voucher_v1_voucher_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *PageMeta) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *PageMeta) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *PageMeta) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *PageMeta) GetTotalPages() int32 {
+	if x != nil {
+		return x.TotalPages
+	}
+	return 0
+}
+
+func (x *PageMeta) GetHasNext() bool {
+	if x != nil {
+		return x.HasNext
+	}
+	return false
+}
+
+func (x *PageMeta) GetHasPrev() bool {
+	if x != nil {
+		return x.HasPrev
+	}
+	return false
+}
+
+func (x *PageMeta) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+func (x *PageMeta) GetPrevCursor() string {
+	if x != nil {
+		return x.PrevCursor
+	}
+	return ""
+}
+
+var File_voucher_v1_voucher_proto protoreflect.FileDescriptor
+
+const file_voucher_v1_voucher_proto_rawDesc = "" +
+	"\n" +
+	"\x18voucher/v1/voucher.proto\x12\n" +
+	"voucher.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb4\x02\n" +
+	"\aVoucher\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12!\n" +
+	"\fvoucher_code\x18\x02 \x01(\tR\vvoucherCode\x12)\n" +
+	"\x10discount_percent\x18\x03 \x01(\x05R\x0fdiscountPercent\x12;\n" +
+	"\vexpiry_date\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"expiryDate\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x18\n" +
+	"\aversion\x18\a \x01(\x05R\aversion\"\x85\x01\n" +
+	"\x14CreateVoucherRequest\x12!\n" +
+	"\fvoucher_code\x18\x01 \x01(\tR\vvoucherCode\x12)\n" +
+	"\x10discount_percent\x18\x02 \x01(\x01R\x0fdiscountPercent\x12\x1f\n" +
+	"\vexpiry_date\x18\x03 \x01(\tR\n" +
+	"expiryDate\"#\n" +
+	"\x11GetVoucherRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xda\x01\n" +
+	"\x14UpdateVoucherRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12!\n" +
+	"\fvoucher_code\x18\x02 \x01(\tR\vvoucherCode\x12)\n" +
+	"\x10discount_percent\x18\x03 \x01(\x01R\x0fdiscountPercent\x12\x1f\n" +
+	"\vexpiry_date\x18\x04 \x01(\tR\n" +
+	"expiryDate\x12.\n" +
+	"\x10expected_version\x18\x05 \x01(\x05H\x00R\x0fexpectedVersion\x88\x01\x01B\x13\n" +
+	"\x11_expected_version\"k\n" +
+	"\x14DeleteVoucherRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12.\n" +
+	"\x10expected_version\x18\x02 \x01(\x05H\x00R\x0fexpectedVersion\x88\x01\x01B\x13\n" +
+	"\x11_expected_version\"\x17\n" +
+	"\x15DeleteVoucherResponse\"\xb6\x04\n" +
+	"\x13ListVouchersRequest\x12\x16\n" +
+	"\x06search\x18\x01 \x01(\tR\x06search\x12\x1f\n" +
+	"\vsearch_mode\x18\x02 \x01(\tR\n" +
+	"searchMode\x12&\n" +
+	"\fdiscount_min\x18\x03 \x01(\x05H\x00R\vdiscountMin\x88\x01\x01\x12&\n" +
+	"\fdiscount_max\x18\x04 \x01(\x05H\x01R\vdiscountMax\x88\x01\x01\x12\x1f\n" +
+	"\vexpiry_from\x18\x05 \x01(\tR\n" +
+	"expiryFrom\x12\x1b\n" +
+	"\texpiry_to\x18\x06 \x01(\tR\bexpiryTo\x12!\n" +
+	"\fcreated_from\x18\a \x01(\tR\vcreatedFrom\x12\x1d\n" +
+	"\n" +
+	"created_to\x18\b \x01(\tR\tcreatedTo\x12\x16\n" +
+	"\x06status\x18\t \x01(\tR\x06status\x125\n" +
+	"\x14expiring_within_days\x18\n" +
+	" \x01(\x05H\x02R\x12expiringWithinDays\x88\x01\x01\x12\x12\n" +
+	"\x04sort\x18\v \x01(\tR\x04sort\x12\x17\n" +
+	"\asort_by\x18\f \x01(\tR\x06sortBy\x12\x1d\n" +
+	"\n" +
+	"sort_order\x18\r \x01(\tR\tsortOrder\x12\x12\n" +
+	"\x04page\x18\x0e \x01(\x05R\x04page\x12\x14\n" +
+	"\x05limit\x18\x0f \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x10 \x01(\tR\x06cursorB\x0f\n" +
+	"\r_discount_minB\x0f\n" +
+	"\r_discount_maxB\x17\n" +
+	"\x15_expiring_within_days\"q\n" +
+	"\x14ListVouchersResponse\x12/\n" +
+	"\bvouchers\x18\x01 \x03(\v2\x13.voucher.v1.VoucherR\bvouchers\x12(\n" +
+	"\x04meta\x18\x02 \x01(\v2\x14.voucher.v1.PageMetaR\x04meta\"\xe3\x01\n" +
+	"\bPageMeta\x12\x14\n" +
+	"\x05total\x18\x01 \x01(\x03R\x05total\x12\x12\n" +
+	"\x04page\x18\x02 \x01(\x05R\x04page\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x1f\n" +
+	"\vtotal_pages\x18\x04 \x01(\x05R\n" +
+	"totalPages\x12\x19\n" +
+	"\bhas_next\x18\x05 \x01(\bR\ahasNext\x12\x19\n" +
+	"\bhas_prev\x18\x06 \x01(\bR\ahasPrev\x12\x1f\n" +
+	"\vnext_cursor\x18\a \x01(\tR\n" +
+	"nextCursor\x12\x1f\n" +
+	"\vprev_cursor\x18\b \x01(\tR\n" +
+	"prevCursor2\x8b\x03\n" +
+	"\x0eVoucherService\x12F\n" +
+	"\rCreateVoucher\x12 .voucher.v1.CreateVoucherRequest\x1a\x13.voucher.v1.Voucher\x12@\n" +
+	"\n" +
+	"GetVoucher\x12\x1d.voucher.v1.GetVoucherRequest\x1a\x13.voucher.v1.Voucher\x12F\n" +
+	"\rUpdateVoucher\x12 .voucher.v1.UpdateVoucherRequest\x1a\x13.voucher.v1.Voucher\x12T\n" +
+	"\rDeleteVoucher\x12 .voucher.v1.DeleteVoucherRequest\x1a!.voucher.v1.DeleteVoucherResponse\x12Q\n" +
+	"\fListVouchers\x12\x1f.voucher.v1.ListVouchersRequest\x1a .voucher.v1.ListVouchersResponseBLZJgithub.com/alifdwt/techtest-indico-be/internal/grpcapi/voucherpb;voucherpbb\x06proto3"
+
+var (
+	file_voucher_v1_voucher_proto_rawDescOnce sync.Once
+	file_voucher_v1_voucher_proto_rawDescData []byte
+)
+
+func file_voucher_v1_voucher_proto_rawDescGZIP() []byte {
+	file_voucher_v1_voucher_proto_rawDescOnce.Do(func() {
+		file_voucher_v1_voucher_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_voucher_v1_voucher_proto_rawDesc), len(file_voucher_v1_voucher_proto_rawDesc)))
+	})
+	return file_voucher_v1_voucher_proto_rawDescData
+}
+
+var file_voucher_v1_voucher_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_voucher_v1_voucher_proto_goTypes = []any{
+	(*Voucher)(nil),               // 0: voucher.v1.Voucher
+	(*CreateVoucherRequest)(nil),  // 1: voucher.v1.CreateVoucherRequest
+	(*GetVoucherRequest)(nil),     // 2: voucher.v1.GetVoucherRequest
+	(*UpdateVoucherRequest)(nil),  // 3: voucher.v1.UpdateVoucherRequest
+	(*DeleteVoucherRequest)(nil),  // 4: voucher.v1.DeleteVoucherRequest
+	(*DeleteVoucherResponse)(nil), // 5: voucher.v1.DeleteVoucherResponse
+	(*ListVouchersRequest)(nil),   // 6: voucher.v1.ListVouchersRequest
+	(*ListVouchersResponse)(nil),  // 7: voucher.v1.ListVouchersResponse
+	(*PageMeta)(nil),              // 8: voucher.v1.PageMeta
+	(*timestamppb.Timestamp)(nil), // 9: google.protobuf.Timestamp
+}
+var file_voucher_v1_voucher_proto_depIdxs = []int32{
+	9,  // 0: voucher.v1.Voucher.expiry_date:type_name -> google.protobuf.Timestamp
+	9,  // 1: voucher.v1.Voucher.created_at:type_name -> google.protobuf.Timestamp
+	9,  // 2: voucher.v1.Voucher.updated_at:type_name -> google.protobuf.Timestamp
+	0,  // 3: voucher.v1.ListVouchersResponse.vouchers:type_name -> voucher.v1.Voucher
+	8,  // 4: voucher.v1.ListVouchersResponse.meta:type_name -> voucher.v1.PageMeta
+	1,  // 5: voucher.v1.VoucherService.CreateVoucher:input_type -> voucher.v1.CreateVoucherRequest
+	2,  // 6: voucher.v1.VoucherService.GetVoucher:input_type -> voucher.v1.GetVoucherRequest
+	3,  // 7: voucher.v1.VoucherService.UpdateVoucher:input_type -> voucher.v1.UpdateVoucherRequest
+	4,  // 8: voucher.v1.VoucherService.DeleteVoucher:input_type -> voucher.v1.DeleteVoucherRequest
+	6,  // 9: voucher.v1.VoucherService.ListVouchers:input_type -> voucher.v1.ListVouchersRequest
+	0,  // 10: voucher.v1.VoucherService.CreateVoucher:output_type -> voucher.v1.Voucher
+	0,  // 11: voucher.v1.VoucherService.GetVoucher:output_type -> voucher.v1.Voucher
+	0,  // 12: voucher.v1.VoucherService.UpdateVoucher:output_type -> voucher.v1.Voucher
+	5,  // 13: voucher.v1.VoucherService.DeleteVoucher:output_type -> voucher.v1.DeleteVoucherResponse
+	7,  // 14: voucher.v1.VoucherService.ListVouchers:output_type -> voucher.v1.ListVouchersResponse
+	10, // [10:15] is the sub-list for method output_type
+	5,  // [5:10] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_voucher_v1_voucher_proto_init() }
+func file_voucher_v1_voucher_proto_init() {
+	if File_voucher_v1_voucher_proto != nil {
+		return
+	}
+	file_voucher_v1_voucher_proto_msgTypes[3].OneofWrappers = []any{}
+	file_voucher_v1_voucher_proto_msgTypes[4].OneofWrappers = []any{}
+	file_voucher_v1_voucher_proto_msgTypes[6].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_voucher_v1_voucher_proto_rawDesc), len(file_voucher_v1_voucher_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_voucher_v1_voucher_proto_goTypes,
+		DependencyIndexes: file_voucher_v1_voucher_proto_depIdxs,
+		MessageInfos:      file_voucher_v1_voucher_proto_msgTypes,
+	}.Build()
+	File_voucher_v1_voucher_proto = out.File
+	file_voucher_v1_voucher_proto_goTypes = nil
+	file_voucher_v1_voucher_proto_depIdxs = nil
+}
@@ -0,0 +1,283 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.32.0
+// source: voucher/v1/voucher.proto
+
+package voucherpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	VoucherService_CreateVoucher_FullMethodName = "/voucher.v1.VoucherService/CreateVoucher"
+	VoucherService_GetVoucher_FullMethodName    = "/voucher.v1.VoucherService/GetVoucher"
+	VoucherService_UpdateVoucher_FullMethodName = "/voucher.v1.VoucherService/UpdateVoucher"
+	VoucherService_DeleteVoucher_FullMethodName = "/voucher.v1.VoucherService/DeleteVoucher"
+	VoucherService_ListVouchers_FullMethodName  = "/voucher.v1.VoucherService/ListVouchers"
+)
+
+// VoucherServiceClient is the client API for VoucherService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// VoucherService manages vouchers, with the same rules as the REST API.
+// Calls must send the bearer token in the authorization metadata.
+type VoucherServiceClient interface {
+	CreateVoucher(ctx context.Context, in *CreateVoucherRequest, opts ...grpc.CallOption) (*Voucher, error)
+	GetVoucher(ctx context.Context, in *GetVoucherRequest, opts ...grpc.CallOption) (*Voucher, error)
+	// UpdateVoucher replaces every field of a voucher.
+	UpdateVoucher(ctx context.Context, in *UpdateVoucherRequest, opts ...grpc.CallOption) (*Voucher, error)
+	DeleteVoucher(ctx context.Context, in *DeleteVoucherRequest, opts ...grpc.CallOption) (*DeleteVoucherResponse, error)
+	// ListVouchers takes the filters, sorting and pagination of GET /vouchers.
+	ListVouchers(ctx context.Context, in *ListVouchersRequest, opts ...grpc.CallOption) (*ListVouchersResponse, error)
+}
+
+type voucherServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewVoucherServiceClient(cc grpc.ClientConnInterface) VoucherServiceClient {
+	return &voucherServiceClient{cc}
+}
+
+func (c *voucherServiceClient) CreateVoucher(ctx context.Context, in *CreateVoucherRequest, opts ...grpc.CallOption) (*Voucher, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Voucher)
+	err := c.cc.Invoke(ctx, VoucherService_CreateVoucher_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *voucherServiceClient) GetVoucher(ctx context.Context, in *GetVoucherRequest, opts ...grpc.CallOption) (*Voucher, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Voucher)
+	err := c.cc.Invoke(ctx, VoucherService_GetVoucher_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *voucherServiceClient) UpdateVoucher(ctx context.Context, in *UpdateVoucherRequest, opts ...grpc.CallOption) (*Voucher, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Voucher)
+	err := c.cc.Invoke(ctx, VoucherService_UpdateVoucher_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *voucherServiceClient) DeleteVoucher(ctx context.Context, in *DeleteVoucherRequest, opts ...grpc.CallOption) (*DeleteVoucherResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteVoucherResponse)
+	err := c.cc.Invoke(ctx, VoucherService_DeleteVoucher_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *voucherServiceClient) ListVouchers(ctx context.Context, in *ListVouchersRequest, opts ...grpc.CallOption) (*ListVouchersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListVouchersResponse)
+	err := c.cc.Invoke(ctx, VoucherService_ListVouchers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// VoucherServiceServer is the server API for VoucherService service.
+// All implementations must embed UnimplementedVoucherServiceServer
+// for forward compatibility.
+//
+// VoucherService manages vouchers, with the same rules as the REST API.
+// Calls must send the bearer token in the authorization metadata.
+type VoucherServiceServer interface {
+	CreateVoucher(context.Context, *CreateVoucherRequest) (*Voucher, error)
+	GetVoucher(context.Context, *GetVoucherRequest) (*Voucher, error)
+	// UpdateVoucher replaces every field of a voucher.
+	UpdateVoucher(context.Context, *UpdateVoucherRequest) (*Voucher, error)
+	DeleteVoucher(context.Context, *DeleteVoucherRequest) (*DeleteVoucherResponse, error)
+	// ListVouchers takes the filters, sorting and pagination of GET /vouchers.
+	ListVouchers(context.Context, *ListVouchersRequest) (*ListVouchersResponse, error)
+	mustEmbedUnimplementedVoucherServiceServer()
+}
+
+// UnimplementedVoucherServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedVoucherServiceServer struct{}
+
+func (UnimplementedVoucherServiceServer) CreateVoucher(context.Context, *CreateVoucherRequest) (*Voucher, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateVoucher not implemented")
+}
+func (UnimplementedVoucherServiceServer) GetVoucher(context.Context, *GetVoucherRequest) (*Voucher, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVoucher not implemented")
+}
+func (UnimplementedVoucherServiceServer) UpdateVoucher(context.Context, *UpdateVoucherRequest) (*Voucher, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateVoucher not implemented")
+}
+func (UnimplementedVoucherServiceServer) DeleteVoucher(context.Context, *DeleteVoucherRequest) (*DeleteVoucherResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteVoucher not implemented")
+}
+func (UnimplementedVoucherServiceServer) ListVouchers(context.Context, *ListVouchersRequest) (*ListVouchersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListVouchers not implemented")
+}
+func (UnimplementedVoucherServiceServer) mustEmbedUnimplementedVoucherServiceServer() {}
+func (UnimplementedVoucherServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafeVoucherServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to VoucherServiceServer will
+// result in compilation errors.
+type UnsafeVoucherServiceServer interface {
+	mustEmbedUnimplementedVoucherServiceServer()
+}
+
+func RegisterVoucherServiceServer(s grpc.ServiceRegistrar, srv VoucherServiceServer) {
+	// If the following call pancis, it indicates UnimplementedVoucherServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&VoucherService_ServiceDesc, srv)
+}
+
+func _VoucherService_CreateVoucher_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateVoucherRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VoucherServiceServer).CreateVoucher(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VoucherService_CreateVoucher_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VoucherServiceServer).CreateVoucher(ctx, req.(*CreateVoucherRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VoucherService_GetVoucher_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVoucherRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VoucherServiceServer).GetVoucher(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VoucherService_GetVoucher_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VoucherServiceServer).GetVoucher(ctx, req.(*GetVoucherRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VoucherService_UpdateVoucher_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateVoucherRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VoucherServiceServer).UpdateVoucher(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VoucherService_UpdateVoucher_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VoucherServiceServer).UpdateVoucher(ctx, req.(*UpdateVoucherRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VoucherService_DeleteVoucher_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteVoucherRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VoucherServiceServer).DeleteVoucher(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VoucherService_DeleteVoucher_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VoucherServiceServer).DeleteVoucher(ctx, req.(*DeleteVoucherRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VoucherService_ListVouchers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListVouchersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VoucherServiceServer).ListVouchers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VoucherService_ListVouchers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VoucherServiceServer).ListVouchers(ctx, req.(*ListVouchersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// VoucherService_ServiceDesc is the grpc.ServiceDesc for VoucherService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var VoucherService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "voucher.v1.VoucherService",
+	HandlerType: (*VoucherServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateVoucher",
+			Handler:    _VoucherService_CreateVoucher_Handler,
+		},
+		{
+			MethodName: "GetVoucher",
+			Handler:    _VoucherService_GetVoucher_Handler,
+		},
+		{
+			MethodName: "UpdateVoucher",
+			Handler:    _VoucherService_UpdateVoucher_Handler,
+		},
+		{
+			MethodName: "DeleteVoucher",
+			Handler:    _VoucherService_DeleteVoucher_Handler,
+		},
+		{
+			MethodName: "ListVouchers",
+			Handler:    _VoucherService_ListVouchers_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "voucher/v1/voucher.proto",
+}
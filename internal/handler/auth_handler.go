@@ -27,24 +27,24 @@ func NewAuthHandler(authService *service.AuthService) *AuthHandler {
 // @Produce json
 // @Param login body dto.LoginRequest true "Login request"
 // @Success 200 {object} util.Response{data=dto.LoginResponse}
-// @Failure 400 {object} util.Response
-// @Failure 500 {object} util.Response
+// @Failure 400 {object} util.Problem
+// @Failure 500 {object} util.Problem
 // @Router /login [post]
 func (ah *AuthHandler) Login(ctx *gin.Context) {
 	var req dto.LoginRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		util.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request format: "+err.Error())
+		ctx.Error(bindError(&req, err))
 		return
 	}
 
 	if err := dto.ValidateStruct(&req); err != nil {
-		util.ErrorResponse(ctx, http.StatusBadRequest, "Validation error: "+err.Error())
+		ctx.Error(err)
 		return
 	}
 
 	res, err := ah.authService.Login(&req)
 	if err != nil {
-		util.ErrorResponse(ctx, http.StatusInternalServerError, "Login failed: "+err.Error())
+		ctx.Error(err)
 		return
 	}
 
@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/alifdwt/techtest-indico-be/internal/apperror"
+	"github.com/alifdwt/techtest-indico-be/internal/dto"
+)
+
+// bindError reports a request that could not be bound into obj. Failed
+// binding rules, such as a missing required field, become field errors;
+// anything else, like malformed JSON, is a bad request.
+func bindError(obj any, err error) error {
+	if validationErr := dto.ValidationError(obj, err); errors.Is(validationErr, apperror.ErrValidation) {
+		return validationErr
+	}
+	if _, ok := apperror.As(err); ok {
+		return err
+	}
+
+	return apperror.BadRequest("invalid_request", "Invalid request format: %s", err.Error())
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/alifdwt/techtest-indico-be/internal/dto"
+	"github.com/alifdwt/techtest-indico-be/internal/graphqlapi"
+	"github.com/gin-gonic/gin"
+)
+
+type GraphQLHandler struct {
+	schema *graphqlapi.Schema
+}
+
+func NewGraphQLHandler(schema *graphqlapi.Schema) *GraphQLHandler {
+	return &GraphQLHandler{
+		schema: schema,
+	}
+}
+
+// Query godoc
+// @Summary Run a GraphQL query
+// @Description Query vouchers and voucher statistics for the admin dashboard. The schema is in internal/graphqlapi/schema.graphql and can be introspected. As GraphQL responses go, errors of single fields are reported in the errors list of a 200 response; only a malformed request body gets a problem response.
+// @Tags graphql
+// @Accept json
+// @Produce json
+// @Param request body dto.GraphQLRequest true "GraphQL request"
+// @Success 200 {object} dto.GraphQLResponse
+// @Failure 400 {object} util.Problem
+// @Failure 401 {object} util.Problem
+// @Router /graphql [post]
+// @Security BearerAuth
+func (gh *GraphQLHandler) Query(ctx *gin.Context) {
+	var req dto.GraphQLRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(bindError(&req, err))
+		return
+	}
+
+	res := gh.schema.Exec(ctx.Request.Context(), req.Query, req.OperationName, req.Variables)
+	ctx.JSON(http.StatusOK, res)
+}
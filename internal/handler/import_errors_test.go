@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alifdwt/techtest-indico-be/internal/apperror"
+	"github.com/alifdwt/techtest-indico-be/internal/config"
+	"github.com/alifdwt/techtest-indico-be/internal/i18n"
+	"github.com/alifdwt/techtest-indico-be/internal/middleware"
+	"github.com/alifdwt/techtest-indico-be/internal/service"
+	"github.com/alifdwt/techtest-indico-be/internal/util"
+	"github.com/gin-gonic/gin"
+)
+
+// importRouter serves the import endpoints without a database; every case
+// below must be rejected before the first query.
+func importRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	dateParser, err := util.NewDateParser(nil, "Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	voucherHandler := NewVoucherHandler(service.NewVoucherService(nil, nil, config.ImportConfig{}, config.ConcurrencyConfig{}, dateParser))
+	importMappingHandler := NewImportMappingHandler(service.NewImportMappingService(nil))
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.POST("/vouchers/upload-csv", voucherHandler.UploadCSV)
+	router.POST("/vouchers/import-json", voucherHandler.ImportJSON)
+	router.POST("/import-mappings", importMappingHandler.CreateProfile)
+
+	return router
+}
+
+func uploadRequest(t *testing.T, fields map[string]string, content string, withFile bool) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for name, value := range fields {
+		writer.WriteField(name, value)
+	}
+	if withFile {
+		part, err := writer.CreateFormFile("file", "vouchers.csv")
+		if err != nil {
+			t.Fatal(err)
+		}
+		part.Write([]byte(content))
+	}
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/vouchers/upload-csv", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestImportInputErrorsAreClientErrors(t *testing.T) {
+	router := importRouter(t)
+	validCSV := "voucher_code,discount_percent,expiry_date\nA1,10,2030-01-01\n"
+
+	tests := []struct {
+		name string
+		req  *http.Request
+		code string
+	}{
+		{"missing file", uploadRequest(t, nil, "", false), "file_required"},
+		{"empty csv", uploadRequest(t, nil, "", true), "invalid_import_file"},
+		{"unsupported encoding", uploadRequest(t, map[string]string{"encoding": "ebcdic"}, validCSV, true), "unsupported_encoding"},
+		{"invalid delimiter", uploadRequest(t, map[string]string{"delimiter": "ab"}, validCSV, true), "invalid_delimiter"},
+		{"missing column", uploadRequest(t, nil, "voucher_code,discount_percent\nA1,10\n", true), "invalid_import_file"},
+		{"match by id without id column", uploadRequest(t, map[string]string{"match_by_id": "true"}, validCSV, true), "invalid_import_file"},
+		{"unknown timezone", uploadRequest(t, map[string]string{"timezone": "Mars/Olympus"}, validCSV, true), "invalid_timezone"},
+		{
+			"empty json body",
+			httptest.NewRequest(http.MethodPost, "/vouchers/import-json", strings.NewReader("  ")),
+			"invalid_import_file",
+		},
+		{
+			"json unknown timezone",
+			httptest.NewRequest(http.MethodPost, "/vouchers/import-json?timezone=Mars/Olympus", strings.NewReader("[]")),
+			"invalid_timezone",
+		},
+		{
+			"invalid mapping profile",
+			httptest.NewRequest(http.MethodPost, "/import-mappings", strings.NewReader(`{"name":"bank","mapping":{"Kode":"code"}}`)),
+			"invalid_mapping",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, tt.req)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want %d; body %s", rec.Code, http.StatusBadRequest, rec.Body)
+			}
+
+			var problem util.Problem
+			if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+				t.Fatal(err)
+			}
+			if problem.Code != tt.code {
+				t.Errorf("code = %q, want %q (%s)", problem.Code, tt.code, problem.Detail)
+			}
+		})
+	}
+}
+
+// Field errors raised by the services are translated like those of request
+// validation.
+func TestImportFieldErrorsAreTranslated(t *testing.T) {
+	router := importRouter(t)
+
+	req := uploadRequest(t, map[string]string{"encoding": "ebcdic"}, "voucher_code\n", true)
+	req.Header.Set("Accept-Language", "id-ID")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	var problem util.Problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+		t.Fatal(err)
+	}
+	if want := "unsupported encoding 'ebcdic', use utf-8, windows-1252 or iso-8859-1"; problem.Detail != want {
+		t.Errorf("detail = %q, want %q", problem.Detail, want)
+	}
+	if lang := rec.Header().Get("Content-Language"); lang != i18n.Indonesian {
+		t.Errorf("Content-Language = %q, want %q", lang, i18n.Indonesian)
+	}
+	if len(problem.Errors) != 1 {
+		t.Fatalf("errors = %+v, want one field error", problem.Errors)
+	}
+
+	field := problem.Errors[0]
+	want := apperror.FieldError{
+		Field:   "encoding",
+		Rule:    "oneof",
+		Param:   "utf-8 windows-1252 iso-8859-1",
+		Message: "harus salah satu dari: utf-8, windows-1252, iso-8859-1",
+	}
+	if field != want {
+		t.Errorf("field error = %+v, want %+v", field, want)
+	}
+}
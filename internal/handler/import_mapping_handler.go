@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/alifdwt/techtest-indico-be/internal/apperror"
+	"github.com/alifdwt/techtest-indico-be/internal/dto"
+	"github.com/alifdwt/techtest-indico-be/internal/service"
+	"github.com/alifdwt/techtest-indico-be/internal/util"
+	"github.com/gin-gonic/gin"
+)
+
+type ImportMappingHandler struct {
+	importMappingService *service.ImportMappingService
+}
+
+func NewImportMappingHandler(importMappingService *service.ImportMappingService) *ImportMappingHandler {
+	return &ImportMappingHandler{
+		importMappingService: importMappingService,
+	}
+}
+
+// CreateProfile godoc
+// @Summary Create an import mapping profile
+// @Description Save a mapping from file headers (e.g. "Kode Voucher") to voucher fields, selectable per upload
+// @Tags import-mappings
+// @Accept json
+// @Produce json
+// @Param profile body dto.ImportMappingProfileRequest true "Mapping profile"
+// @Success 201 {object} util.Response{data=dto.ImportMappingProfileResponse}
+// @Failure 400 {object} util.Problem
+// @Failure 409 {object} util.Problem
+// @Failure 500 {object} util.Problem
+// @Router /import-mappings [post]
+// @Security BearerAuth
+func (mh *ImportMappingHandler) CreateProfile(ctx *gin.Context) {
+	var req dto.ImportMappingProfileRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(bindError(&req, err))
+		return
+	}
+
+	if err := dto.ValidateStruct(&req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	res, err := mh.importMappingService.CreateProfile(ctx, &req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.SuccessResponse(ctx, http.StatusCreated, "Mapping profile created", res)
+}
+
+// ListProfiles godoc
+// @Summary List import mapping profiles
+// @Description Retrieve saved import mapping profiles ordered by name. Links to the neighbouring pages are sent in the Link header.
+// @Tags import-mappings
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Number of items per page" default(20)
+// @Success 200 {object} util.PaginatedResponse{data=[]dto.ImportMappingProfileResponse}
+// @Failure 400 {object} util.Problem
+// @Failure 500 {object} util.Problem
+// @Router /import-mappings [get]
+// @Security BearerAuth
+func (mh *ImportMappingHandler) ListProfiles(ctx *gin.Context) {
+	var req dto.PaginationQuery
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.Error(bindError(&req, err))
+		return
+	}
+
+	if err := dto.ValidateStruct(&req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	res, meta, err := mh.importMappingService.ListProfiles(ctx, &req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.PaginatedSuccessResponse(ctx, http.StatusOK, "Mapping profiles listed", res, meta)
+}
+
+// GetProfile godoc
+// @Summary Get import mapping profile by ID
+// @Description Get a specific import mapping profile by its ID
+// @Tags import-mappings
+// @Produce json
+// @Param id path string true "Mapping profile ID"
+// @Success 200 {object} util.Response{data=dto.ImportMappingProfileResponse}
+// @Failure 400 {object} util.Problem
+// @Failure 404 {object} util.Problem
+// @Failure 500 {object} util.Problem
+// @Router /import-mappings/{id} [get]
+// @Security BearerAuth
+func (mh *ImportMappingHandler) GetProfile(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		ctx.Error(apperror.InvalidID("missing_id", "Mapping profile ID is required"))
+		return
+	}
+
+	res, err := mh.importMappingService.GetProfileByID(ctx, id)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.SuccessResponse(ctx, http.StatusOK, "Mapping profile retrieved", res)
+}
+
+// UpdateProfile godoc
+// @Summary Update an import mapping profile
+// @Description Replace the name and mapping of an existing profile
+// @Tags import-mappings
+// @Accept json
+// @Produce json
+// @Param id path string true "Mapping profile ID"
+// @Param profile body dto.ImportMappingProfileRequest true "Mapping profile"
+// @Success 200 {object} util.Response{data=dto.ImportMappingProfileResponse}
+// @Failure 400 {object} util.Problem
+// @Failure 404 {object} util.Problem
+// @Failure 409 {object} util.Problem
+// @Failure 500 {object} util.Problem
+// @Router /import-mappings/{id} [put]
+// @Security BearerAuth
+func (mh *ImportMappingHandler) UpdateProfile(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		ctx.Error(apperror.InvalidID("missing_id", "Mapping profile ID is required"))
+		return
+	}
+
+	var req dto.ImportMappingProfileRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(bindError(&req, err))
+		return
+	}
+
+	if err := dto.ValidateStruct(&req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	res, err := mh.importMappingService.UpdateProfile(ctx, id, &req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.SuccessResponse(ctx, http.StatusOK, "Mapping profile updated", res)
+}
+
+// DeleteProfile godoc
+// @Summary Delete an import mapping profile
+// @Description Delete an import mapping profile by its ID
+// @Tags import-mappings
+// @Produce json
+// @Param id path string true "Mapping profile ID"
+// @Success 200 {object} util.Response
+// @Failure 400 {object} util.Problem
+// @Failure 404 {object} util.Problem
+// @Failure 500 {object} util.Problem
+// @Router /import-mappings/{id} [delete]
+// @Security BearerAuth
+func (mh *ImportMappingHandler) DeleteProfile(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		ctx.Error(apperror.InvalidID("missing_id", "Mapping profile ID is required"))
+		return
+	}
+
+	err := mh.importMappingService.DeleteProfile(ctx, id)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.SuccessResponse(ctx, http.StatusOK, "Mapping profile deleted", nil)
+}
@@ -0,0 +1,255 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/alifdwt/techtest-indico-be/internal/apperror"
+	"github.com/alifdwt/techtest-indico-be/internal/dto"
+	"github.com/alifdwt/techtest-indico-be/internal/service"
+	"github.com/alifdwt/techtest-indico-be/internal/util"
+	"github.com/gin-gonic/gin"
+)
+
+type ScheduledExportHandler struct {
+	scheduledExportService *service.ScheduledExportService
+}
+
+func NewScheduledExportHandler(scheduledExportService *service.ScheduledExportService) *ScheduledExportHandler {
+	return &ScheduledExportHandler{
+		scheduledExportService: scheduledExportService,
+	}
+}
+
+// CreateExport godoc
+// @Summary Create a scheduled export
+// @Description Define a recurring voucher export (cron expression, filters and format) written to a storage backend
+// @Tags scheduled-exports
+// @Accept json
+// @Produce json
+// @Param export body dto.ScheduledExportRequest true "Scheduled export"
+// @Success 201 {object} util.Response{data=dto.ScheduledExportResponse}
+// @Failure 400 {object} util.Problem
+// @Failure 409 {object} util.Problem
+// @Failure 500 {object} util.Problem
+// @Router /scheduled-exports [post]
+// @Security BearerAuth
+func (sh *ScheduledExportHandler) CreateExport(ctx *gin.Context) {
+	var req dto.ScheduledExportRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(bindError(&req, err))
+		return
+	}
+
+	if err := dto.ValidateStruct(&req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	res, err := sh.scheduledExportService.CreateExport(ctx, &req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.SuccessResponse(ctx, http.StatusCreated, "Scheduled export created", res)
+}
+
+// ListExports godoc
+// @Summary List scheduled exports
+// @Description Retrieve scheduled exports ordered by name, with their next run time. Links to the neighbouring pages are sent in the Link header.
+// @Tags scheduled-exports
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Number of items per page" default(20)
+// @Success 200 {object} util.PaginatedResponse{data=[]dto.ScheduledExportResponse}
+// @Failure 400 {object} util.Problem
+// @Failure 500 {object} util.Problem
+// @Router /scheduled-exports [get]
+// @Security BearerAuth
+func (sh *ScheduledExportHandler) ListExports(ctx *gin.Context) {
+	var req dto.PaginationQuery
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.Error(bindError(&req, err))
+		return
+	}
+
+	if err := dto.ValidateStruct(&req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	res, meta, err := sh.scheduledExportService.ListExports(ctx, &req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.PaginatedSuccessResponse(ctx, http.StatusOK, "Scheduled exports listed", res, meta)
+}
+
+// GetExport godoc
+// @Summary Get scheduled export by ID
+// @Description Get a specific scheduled export by its ID
+// @Tags scheduled-exports
+// @Produce json
+// @Param id path string true "Scheduled export ID"
+// @Success 200 {object} util.Response{data=dto.ScheduledExportResponse}
+// @Failure 400 {object} util.Problem
+// @Failure 404 {object} util.Problem
+// @Failure 500 {object} util.Problem
+// @Router /scheduled-exports/{id} [get]
+// @Security BearerAuth
+func (sh *ScheduledExportHandler) GetExport(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		ctx.Error(apperror.InvalidID("missing_id", "Scheduled export ID is required"))
+		return
+	}
+
+	res, err := sh.scheduledExportService.GetExportByID(ctx, id)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.SuccessResponse(ctx, http.StatusOK, "Scheduled export retrieved", res)
+}
+
+// UpdateExport godoc
+// @Summary Update a scheduled export
+// @Description Replace the definition of a scheduled export; the new schedule applies immediately
+// @Tags scheduled-exports
+// @Accept json
+// @Produce json
+// @Param id path string true "Scheduled export ID"
+// @Param export body dto.ScheduledExportRequest true "Scheduled export"
+// @Success 200 {object} util.Response{data=dto.ScheduledExportResponse}
+// @Failure 400 {object} util.Problem
+// @Failure 404 {object} util.Problem
+// @Failure 409 {object} util.Problem
+// @Failure 500 {object} util.Problem
+// @Router /scheduled-exports/{id} [put]
+// @Security BearerAuth
+func (sh *ScheduledExportHandler) UpdateExport(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		ctx.Error(apperror.InvalidID("missing_id", "Scheduled export ID is required"))
+		return
+	}
+
+	var req dto.ScheduledExportRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(bindError(&req, err))
+		return
+	}
+
+	if err := dto.ValidateStruct(&req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	res, err := sh.scheduledExportService.UpdateExport(ctx, id, &req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.SuccessResponse(ctx, http.StatusOK, "Scheduled export updated", res)
+}
+
+// DeleteExport godoc
+// @Summary Delete a scheduled export
+// @Description Delete a scheduled export and its run history
+// @Tags scheduled-exports
+// @Produce json
+// @Param id path string true "Scheduled export ID"
+// @Success 200 {object} util.Response
+// @Failure 400 {object} util.Problem
+// @Failure 404 {object} util.Problem
+// @Failure 500 {object} util.Problem
+// @Router /scheduled-exports/{id} [delete]
+// @Security BearerAuth
+func (sh *ScheduledExportHandler) DeleteExport(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		ctx.Error(apperror.InvalidID("missing_id", "Scheduled export ID is required"))
+		return
+	}
+
+	err := sh.scheduledExportService.DeleteExport(ctx, id)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.SuccessResponse(ctx, http.StatusOK, "Scheduled export deleted", nil)
+}
+
+// RunExport godoc
+// @Summary Run a scheduled export now
+// @Description Start a run of the export immediately, outside its schedule. The run continues in the background; poll the run history for its outcome.
+// @Tags scheduled-exports
+// @Produce json
+// @Param id path string true "Scheduled export ID"
+// @Success 202 {object} util.Response{data=dto.ScheduledExportRunResponse}
+// @Failure 400 {object} util.Problem
+// @Failure 404 {object} util.Problem
+// @Failure 500 {object} util.Problem
+// @Router /scheduled-exports/{id}/run [post]
+// @Security BearerAuth
+func (sh *ScheduledExportHandler) RunExport(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		ctx.Error(apperror.InvalidID("missing_id", "Scheduled export ID is required"))
+		return
+	}
+
+	res, err := sh.scheduledExportService.RunExport(ctx, id)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.SuccessResponse(ctx, http.StatusAccepted, "Scheduled export started", res)
+}
+
+// ListRuns godoc
+// @Summary List runs of a scheduled export
+// @Description Retrieve the runs of a scheduled export, most recent first, with their status, output location and failure reason. Links to the neighbouring pages are sent in the Link header.
+// @Tags scheduled-exports
+// @Produce json
+// @Param id path string true "Scheduled export ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Number of runs per page" default(20)
+// @Success 200 {object} util.PaginatedResponse{data=[]dto.ScheduledExportRunResponse}
+// @Failure 400 {object} util.Problem
+// @Failure 404 {object} util.Problem
+// @Failure 500 {object} util.Problem
+// @Router /scheduled-exports/{id}/runs [get]
+// @Security BearerAuth
+func (sh *ScheduledExportHandler) ListRuns(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		ctx.Error(apperror.InvalidID("missing_id", "Scheduled export ID is required"))
+		return
+	}
+
+	var req dto.PaginationQuery
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.Error(bindError(&req, err))
+		return
+	}
+
+	if err := dto.ValidateStruct(&req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	res, meta, err := sh.scheduledExportService.ListRuns(ctx, id, &req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.PaginatedSuccessResponse(ctx, http.StatusOK, "Scheduled export runs listed", res, meta)
+}
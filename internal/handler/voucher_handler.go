@@ -1,10 +1,13 @@
 package handler
 
 import (
+	"bytes"
+	"encoding/csv"
 	"net/http"
-	"strings"
 
+	"github.com/alifdwt/techtest-indico-be/internal/apperror"
 	"github.com/alifdwt/techtest-indico-be/internal/dto"
+	"github.com/alifdwt/techtest-indico-be/internal/i18n"
 	"github.com/alifdwt/techtest-indico-be/internal/service"
 	"github.com/alifdwt/techtest-indico-be/internal/util"
 	"github.com/gin-gonic/gin"
@@ -28,97 +31,147 @@ func NewVoucherHandler(voucherService *service.VoucherService) *VoucherHandler {
 // @Produce json
 // @Param voucher body dto.CreateVoucherRequest true "Voucher data"
 // @Success 201 {object} util.Response{data=dto.VoucherResponse}
-// @Failure 400 {object} util.Response
-// @Failure 500 {object} util.Response
+// @Header 201 {string} ETag "Voucher version"
+// @Failure 400 {object} util.Problem
+// @Failure 409 {object} util.Problem
+// @Failure 500 {object} util.Problem
 // @Router /vouchers [post]
 // @Security BearerAuth
 func (vh *VoucherHandler) CreateVoucher(ctx *gin.Context) {
 	var req dto.CreateVoucherRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		util.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request format: "+err.Error())
+		ctx.Error(bindError(&req, err))
 		return
 	}
 
 	if err := dto.ValidateStruct(&req); err != nil {
-		util.ErrorResponse(ctx, http.StatusBadRequest, "Validation error: "+err.Error())
+		ctx.Error(err)
 		return
 	}
 
 	res, err := vh.voucherService.CreateVoucher(ctx, &req)
 	if err != nil {
-		util.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to create voucher: "+err.Error())
+		ctx.Error(err)
 		return
 	}
 
+	ctx.Header("ETag", util.ETag(res.Version))
 	util.SuccessResponse(ctx, http.StatusCreated, "Voucher created", res)
 }
 
 // ListVouchers godoc
 // @Summary List vouchers
-// @Description Retrieve a list of vouchers
+// @Description Retrieve a list of vouchers. Pages can be addressed by page number or, stable while vouchers are being added, by the meta.next_cursor/meta.prev_cursor returned with every page. Links to the neighbouring pages are sent in the Link header.
 // @Tags vouchers
 // @Accept json
 // @Produce json
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Number of items per page" default(10)
 // @Param search query string false "Search term"
-// @Param sort_by query string false "Sort by field" default(expiry_date)
+// @Param search_mode query string false "How search matches voucher codes: contains, prefix or fuzzy (typo tolerant)" default(contains)
+// @Param discount_min query int false "Minimum discount percent (inclusive)"
+// @Param discount_max query int false "Maximum discount percent (inclusive)"
+// @Param expiry_from query string false "Expiring on or after this date"
+// @Param expiry_to query string false "Expiring on or before this date"
+// @Param created_from query string false "Created on or after this date"
+// @Param created_to query string false "Created on or before this date"
+// @Param status query string false "active (not expired yet) or expired"
+// @Param expiring_within_days query int false "Only active vouchers expiring within this many days"
+// @Param sort query string false "Comma separated sort fields, prefixed with - for descending, e.g. -discount_percent,expiry_date (voucher_code, expiry_date, discount_percent, created_at, updated_at); replaces sort_by and sort_order"
+// @Param sort_by query string false "Sort by field; relevance requires search_mode=fuzzy" default(expiry_date)
 // @Param sort_order query string false "Sort order (asc or desc)" default(asc)
-// @Success 200 {object} util.Response{data=[]dto.VoucherResponse}
-// @Failure 400 {object} util.Response
-// @Failure 500 {object} util.Response
+// @Param cursor query string false "Cursor from a previous page (next_cursor or prev_cursor); page is ignored when set"
+// @Success 200 {object} util.PaginatedResponse{data=[]dto.VoucherResponse}
+// @Failure 400 {object} util.Problem
+// @Failure 500 {object} util.Problem
 // @Router /vouchers [get]
 // @Security BearerAuth
 func (vh *VoucherHandler) ListVouchers(ctx *gin.Context) {
 	var req dto.VoucherListQuery
 	if err := ctx.ShouldBindQuery(&req); err != nil {
-		util.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request format: "+err.Error())
+		ctx.Error(bindError(&req, err))
 		return
 	}
 
 	if err := dto.ValidateStruct(&req); err != nil {
-		util.ErrorResponse(ctx, http.StatusBadRequest, "Validation error: "+err.Error())
+		ctx.Error(err)
 		return
 	}
 
-	res, total, err := vh.voucherService.ListVouchers(ctx, &req)
+	res, meta, err := vh.voucherService.ListVouchers(ctx, &req)
 	if err != nil {
-		util.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to list vouchers: "+err.Error())
+		ctx.Error(err)
 		return
 	}
 
-	util.SuccessResponse(ctx, http.StatusOK, "Vouchers listed", gin.H{
-		"vouchers": res,
-		"total":    total,
-	})
+	util.PaginatedSuccessResponse(ctx, http.StatusOK, "Vouchers listed", res, meta)
+}
+
+// AutocompleteVouchers godoc
+// @Summary Autocomplete voucher codes
+// @Description Suggest voucher codes starting with the given text, case-insensitively and in alphabetical order
+// @Tags vouchers
+// @Produce json
+// @Param q query string true "Beginning of the voucher code"
+// @Param limit query int false "Maximum number of codes" default(10)
+// @Success 200 {object} util.Response{data=[]string}
+// @Failure 400 {object} util.Problem
+// @Failure 500 {object} util.Problem
+// @Router /vouchers/autocomplete [get]
+// @Security BearerAuth
+func (vh *VoucherHandler) AutocompleteVouchers(ctx *gin.Context) {
+	var req dto.VoucherAutocompleteQuery
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.Error(bindError(&req, err))
+		return
+	}
+
+	if err := dto.ValidateStruct(&req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	res, err := vh.voucherService.AutocompleteCodes(ctx, &req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.SuccessResponse(ctx, http.StatusOK, "Voucher codes found", res)
 }
 
 // GetVoucher godoc
 // @Summary Get voucher by ID
-// @Description Get a specific voucher by its ID
+// @Description Get a specific voucher by its ID. The voucher version is returned in the ETag header; send it back in If-None-Match to get a 304 when the voucher has not changed.
 // @Tags vouchers
 // @Produce json
 // @Param id path string true "Voucher ID"
+// @Param If-None-Match header string false "ETag of a cached copy"
 // @Success 200 {object} util.Response{data=dto.VoucherResponse}
-// @Failure 400 {object} util.Response
-// @Failure 404 {object} util.Response
-// @Failure 500 {object} util.Response
+// @Header 200 {string} ETag "Voucher version"
+// @Success 304 "Not modified"
+// @Failure 400 {object} util.Problem
+// @Failure 404 {object} util.Problem
+// @Failure 500 {object} util.Problem
 // @Router /vouchers/{id} [get]
 // @Security BearerAuth
 func (vh *VoucherHandler) GetVoucher(ctx *gin.Context) {
 	id := ctx.Param("id")
 	if id == "" {
-		util.ErrorResponse(ctx, http.StatusBadRequest, "Voucher ID is required")
+		ctx.Error(apperror.InvalidID("missing_id", "Voucher ID is required"))
 		return
 	}
 
 	res, err := vh.voucherService.GetVoucherByID(ctx, id)
 	if err != nil {
-		if err.Error() == "voucher not found" {
-			util.ErrorResponse(ctx, http.StatusNotFound, "Voucher not found")
-			return
-		}
-		util.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to get voucher: "+err.Error())
+		ctx.Error(err)
+		return
+	}
+
+	etag := util.ETag(res.Version)
+	ctx.Header("ETag", etag)
+	if ifNoneMatch := ctx.GetHeader("If-None-Match"); ifNoneMatch != "" && util.MatchETag(ifNoneMatch, etag, true) {
+		ctx.Status(http.StatusNotModified)
 		return
 	}
 
@@ -133,40 +186,102 @@ func (vh *VoucherHandler) GetVoucher(ctx *gin.Context) {
 // @Produce json
 // @Param id path string true "Voucher ID"
 // @Param voucher body dto.UpdateVoucherRequest true "Updated voucher data"
+// @Param If-Match header string false "ETag the voucher must still have"
 // @Success 200 {object} util.Response{data=dto.VoucherResponse}
-// @Failure 400 {object} util.Response
-// @Failure 404 {object} util.Response
-// @Failure 500 {object} util.Response
+// @Header 200 {string} ETag "New voucher version"
+// @Failure 400 {object} util.Problem
+// @Failure 404 {object} util.Problem
+// @Failure 409 {object} util.Problem
+// @Failure 412 {object} util.Problem
+// @Failure 428 {object} util.Problem
+// @Failure 500 {object} util.Problem
 // @Router /vouchers/{id} [put]
 // @Security BearerAuth
 func (vh *VoucherHandler) UpdateVoucher(ctx *gin.Context) {
 	id := ctx.Param("id")
 	if id == "" {
-		util.ErrorResponse(ctx, http.StatusBadRequest, "Voucher ID is required")
+		ctx.Error(apperror.InvalidID("missing_id", "Voucher ID is required"))
 		return
 	}
 
 	var req dto.UpdateVoucherRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		util.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request format: "+err.Error())
+		ctx.Error(bindError(&req, err))
 		return
 	}
 
 	if err := dto.ValidateStruct(&req); err != nil {
-		util.ErrorResponse(ctx, http.StatusBadRequest, "Validation error: "+err.Error())
+		ctx.Error(err)
 		return
 	}
 
-	res, err := vh.voucherService.UpdateVoucher(ctx, id, &req)
+	res, err := vh.voucherService.UpdateVoucher(ctx, id, &req, ctx.GetHeader("If-Match"))
 	if err != nil {
-		if err.Error() == "voucher not found" {
-			util.ErrorResponse(ctx, http.StatusNotFound, "Voucher not found")
-			return
-		}
-		util.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to update voucher: "+err.Error())
+		ctx.Error(err)
 		return
 	}
 
+	ctx.Header("ETag", util.ETag(res.Version))
+	util.SuccessResponse(ctx, http.StatusOK, "Voucher updated", res)
+}
+
+// PatchVoucher godoc
+// @Summary Partially update a voucher
+// @Description Change some fields of a voucher with a JSON Merge Patch (RFC 7396). Fields left out of the patch are not changed; null is rejected because every voucher field is required.
+// @Tags vouchers
+// @Accept json
+// @Accept application/merge-patch+json
+// @Produce json
+// @Param id path string true "Voucher ID"
+// @Param voucher body dto.PatchVoucherRequest true "Fields to change"
+// @Param If-Match header string false "ETag the voucher must still have"
+// @Success 200 {object} util.Response{data=dto.VoucherResponse}
+// @Header 200 {string} ETag "New voucher version"
+// @Failure 400 {object} util.Problem
+// @Failure 404 {object} util.Problem
+// @Failure 409 {object} util.Problem
+// @Failure 412 {object} util.Problem
+// @Failure 415 {object} util.Problem
+// @Failure 428 {object} util.Problem
+// @Failure 500 {object} util.Problem
+// @Router /vouchers/{id} [patch]
+// @Security BearerAuth
+func (vh *VoucherHandler) PatchVoucher(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		ctx.Error(apperror.InvalidID("missing_id", "Voucher ID is required"))
+		return
+	}
+
+	if contentType := ctx.ContentType(); contentType != dto.MergePatchContentType && contentType != "application/json" {
+		ctx.Error(apperror.UnsupportedMediaType("unsupported_media_type", "Content-Type must be %s or application/json", dto.MergePatchContentType))
+		return
+	}
+
+	body, err := ctx.GetRawData()
+	if err != nil {
+		ctx.Error(apperror.BadRequest("invalid_request", "Failed to read request body"))
+		return
+	}
+
+	var req dto.PatchVoucherRequest
+	if err := dto.DecodeMergePatch(body, &req); err != nil {
+		ctx.Error(bindError(&req, err))
+		return
+	}
+
+	if err := dto.ValidateStruct(&req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	res, err := vh.voucherService.PatchVoucher(ctx, id, &req, ctx.GetHeader("If-Match"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Header("ETag", util.ETag(res.Version))
 	util.SuccessResponse(ctx, http.StatusOK, "Voucher updated", res)
 }
 
@@ -176,93 +291,262 @@ func (vh *VoucherHandler) UpdateVoucher(ctx *gin.Context) {
 // @Tags vouchers
 // @Produce json
 // @Param id path string true "Voucher ID"
+// @Param If-Match header string false "ETag the voucher must still have"
 // @Success 200 {object} util.Response
-// @Failure 400 {object} util.Response
-// @Failure 404 {object} util.Response
-// @Failure 500 {object} util.Response
+// @Failure 400 {object} util.Problem
+// @Failure 404 {object} util.Problem
+// @Failure 412 {object} util.Problem
+// @Failure 428 {object} util.Problem
+// @Failure 500 {object} util.Problem
 // @Router /vouchers/{id} [delete]
 // @Security BearerAuth
 func (vh *VoucherHandler) DeleteVoucher(ctx *gin.Context) {
 	id := ctx.Param("id")
 	if id == "" {
-		util.ErrorResponse(ctx, http.StatusBadRequest, "Voucher ID is required")
+		ctx.Error(apperror.InvalidID("missing_id", "Voucher ID is required"))
 		return
 	}
 
-	err := vh.voucherService.DeleteVoucher(ctx, id)
+	err := vh.voucherService.DeleteVoucher(ctx, id, ctx.GetHeader("If-Match"))
 	if err != nil {
-		if err.Error() == "voucher not found" {
-			util.ErrorResponse(ctx, http.StatusNotFound, "Voucher not found")
-			return
-		}
-		util.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to delete voucher: "+err.Error())
+		ctx.Error(err)
 		return
 	}
 
 	util.SuccessResponse(ctx, http.StatusOK, "Voucher deleted", nil)
 }
 
+// BulkVouchers godoc
+// @Summary Update or delete many vouchers
+// @Description Apply one operation (extend_expiry, set_discount, expire or delete) to the vouchers listed in ids or matching filter, which takes the same filters as the voucher list. Everything runs in one transaction, so an error changes nothing, and is refused when more vouchers than max_affected (at most 1000) match. Vouchers changed or deleted concurrently are left alone and reported as conflict; the others are still changed. With dry_run the changes are only previewed. The report lists the outcome for every voucher.
+// @Tags vouchers
+// @Accept json
+// @Produce json
+// @Param request body dto.BulkVoucherRequest true "Bulk operation"
+// @Success 200 {object} util.Response{data=dto.BulkVoucherResponse}
+// @Failure 400 {object} util.Problem
+// @Failure 500 {object} util.Problem
+// @Router /vouchers/bulk [post]
+// @Security BearerAuth
+func (vh *VoucherHandler) BulkVouchers(ctx *gin.Context) {
+	var req dto.BulkVoucherRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(bindError(&req, err))
+		return
+	}
+
+	if err := dto.ValidateStruct(&req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	res, err := vh.voucherService.BulkVouchers(ctx, &req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	message := "Bulk operation completed"
+	if res.DryRun {
+		message = "Bulk operation previewed"
+	}
+
+	util.SuccessResponse(ctx, http.StatusOK, message, res)
+}
+
 // UploadCSV godoc
-// @Summary Upload vouchers from CSV
-// @Description Upload vouchers from a CSV file
+// @Summary Upload vouchers from CSV or XLSX
+// @Description Upload vouchers from a CSV file or an .xlsx workbook
 // @Tags vouchers
 // @Accept multipart/form-data
 // @Produce json
-// @Param file formData file true "CSV file"
+// @Param file formData file true "CSV or XLSX file"
+// @Param sheet formData string false "Worksheet to read from an XLSX file, defaults to the first sheet"
+// @Param delimiter formData string false "Field delimiter (, ; | or tab), detected from the header row when empty"
+// @Param encoding formData string false "File encoding (utf-8, windows-1252 or iso-8859-1), detected when empty"
+// @Param mapping_profile formData string false "ID or name of a saved import mapping profile"
+// @Param timezone formData string false "Timezone for dates without an offset" default(Asia/Jakarta)
+// @Param match_by_id formData bool false "Update the voucher named in the id column, at the version in the version column if any, instead of creating one" default(false)
 // @Success 200 {object} util.Response{data=dto.CSVUploadResponse}
-// @Failure 400 {object} util.Response
-// @Failure 500 {object} util.Response
+// @Failure 400 {object} util.Problem
+// @Failure 500 {object} util.Problem
 // @Router /vouchers/upload-csv [post]
 // @Security BearerAuth
 func (vh *VoucherHandler) UploadCSV(ctx *gin.Context) {
-	file, _, err := ctx.Request.FormFile("file")
+	file, fileHeader, err := ctx.Request.FormFile("file")
 	if err != nil {
-		util.ErrorResponse(ctx, http.StatusBadRequest, "Failed to retrieve file: "+err.Error())
+		ctx.Error(dto.FieldRuleError("file_required", "file", "required", "", i18n.MsgRequired, "Failed to retrieve file: %s", err.Error()))
 		return
 	}
 	defer file.Close()
 
-	res, err := vh.voucherService.UploadCSV(ctx, file)
+	var opts dto.CSVUploadOptions
+	if err := ctx.ShouldBind(&opts); err != nil {
+		ctx.Error(bindError(&opts, err))
+		return
+	}
+
+	res, err := vh.voucherService.UploadCSV(ctx, fileHeader.Filename, file, &opts)
 	if err != nil {
-		util.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to upload CSV: "+err.Error())
+		ctx.Error(err)
 		return
 	}
 
 	util.SuccessResponse(ctx, http.StatusOK, "CSV uploaded", res)
 }
 
-// ExportCSV godoc
-// @Summary Export vouchers to CSV
-// @Description Export all vouchers as a CSV file
+// ImportJSON godoc
+// @Summary Bulk import vouchers from JSON
+// @Description Import vouchers from a JSON array or newline-delimited JSON (NDJSON) of voucher objects. The body is streamed and every item is validated like a CSV row.
+// @Tags vouchers
+// @Accept json
+// @Accept application/x-ndjson
+// @Produce json
+// @Param vouchers body []dto.CreateVoucherRequest true "Vouchers to import"
+// @Param timezone query string false "Timezone for dates without an offset" default(Asia/Jakarta)
+// @Param match_by_id query bool false "Update the voucher named in the id field, at the version in the version field if any, instead of creating one" default(false)
+// @Success 200 {object} util.Response{data=dto.CSVUploadResponse}
+// @Failure 400 {object} util.Problem
+// @Failure 500 {object} util.Problem
+// @Router /vouchers/import-json [post]
+// @Security BearerAuth
+func (vh *VoucherHandler) ImportJSON(ctx *gin.Context) {
+	var opts dto.JSONImportOptions
+	if err := ctx.ShouldBindQuery(&opts); err != nil {
+		ctx.Error(bindError(&opts, err))
+		return
+	}
+
+	res, err := vh.voucherService.ImportJSON(ctx, ctx.Request.Body, &opts)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.SuccessResponse(ctx, http.StatusOK, "JSON imported", res)
+}
+
+// ImportErrorReport godoc
+// @Summary Download the error report of an import
+// @Description Download the failing rows of a CSV import with an extra error_reason column, ready to be fixed and uploaded again
 // @Tags vouchers
 // @Produce text/csv
+// @Param id path string true "Import ID"
 // @Success 200 {file} binary
-// @Failure 500 {object} util.Response
-// @Router /vouchers/export [get]
+// @Failure 400 {object} util.Problem
+// @Failure 404 {object} util.Problem
+// @Failure 500 {object} util.Problem
+// @Router /vouchers/imports/{id}/error-report [get]
 // @Security BearerAuth
-func (vh *VoucherHandler) ExportCSV(ctx *gin.Context) {
-	records, err := vh.voucherService.ExportCSV(ctx)
+func (vh *VoucherHandler) ImportErrorReport(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		ctx.Error(apperror.InvalidID("missing_id", "Import ID is required"))
+		return
+	}
+
+	records, err := vh.voucherService.ImportErrorReport(ctx, id)
 	if err != nil {
-		util.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to export CSV: "+err.Error())
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Header("Content-Type", "text/csv")
+	ctx.Header("Content-Disposition", "attachment; filename=import-"+id+"-errors.csv")
+
+	writer := csv.NewWriter(ctx.Writer)
+	if err := writer.WriteAll(records); err != nil {
+		ctx.Error(err)
+	}
+}
+
+// ExportVouchers godoc
+// @Summary Export vouchers
+// @Description Export vouchers as a CSV file, a formatted XLSX workbook or streamed NDJSON. Rows are streamed from the database and can be filtered and sorted like the voucher list.
+// @Tags vouchers
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Produce application/x-ndjson
+// @Param format query string false "Export format (csv, xlsx or ndjson)" default(csv)
+// @Param profile query string false "Header profile: display for readable headers, import for a file that can be uploaded again as-is" default(display)
+// @Param search query string false "Only export vouchers whose code matches this term"
+// @Param search_mode query string false "How search matches voucher codes: contains, prefix or fuzzy (typo tolerant)" default(contains)
+// @Param discount_min query int false "Minimum discount percent (inclusive)"
+// @Param discount_max query int false "Maximum discount percent (inclusive)"
+// @Param expiry_from query string false "Expiring on or after this date"
+// @Param expiry_to query string false "Expiring on or before this date"
+// @Param created_from query string false "Created on or after this date"
+// @Param created_to query string false "Created on or before this date"
+// @Param status query string false "active (not expired yet) or expired"
+// @Param expiring_within_days query int false "Only active vouchers expiring within this many days"
+// @Param sort query string false "Comma separated sort fields, prefixed with - for descending, e.g. -discount_percent,expiry_date; replaces sort_by and sort_order"
+// @Param sort_by query string false "Sort by field (voucher_code, expiry_date, discount_percent, created_at, updated_at, relevance)" default(created_at)
+// @Param sort_order query string false "Sort order (asc or desc)" default(desc)
+// @Param columns query string false "Comma separated columns to include in CSV and XLSX exports (id, voucher_code, discount_percent, expiry_date, created_at, updated_at, version)"
+// @Success 200 {file} binary
+// @Failure 400 {object} util.Problem
+// @Failure 500 {object} util.Problem
+// @Router /vouchers/export [get]
+// @Security BearerAuth
+func (vh *VoucherHandler) ExportVouchers(ctx *gin.Context) {
+	var req dto.VoucherExportQuery
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.Error(bindError(&req, err))
+		return
+	}
+
+	if err := dto.ValidateStruct(&req); err != nil {
+		ctx.Error(err)
 		return
 	}
 
+	switch req.Format {
+	case "xlsx":
+		vh.exportXLSX(ctx, &req)
+	case "ndjson":
+		vh.exportNDJSON(ctx, &req)
+	default:
+		vh.exportCSV(ctx, &req)
+	}
+}
+
+func (vh *VoucherHandler) exportCSV(ctx *gin.Context, req *dto.VoucherExportQuery) {
 	ctx.Header("Content-Type", "text/csv")
 	ctx.Header("Content-Disposition", "attachment; filename=vouchers.csv")
 
-	for _, record := range records {
-		line := ""
-		for i, field := range record {
-			if i > 0 {
-				line += ","
-			}
-			// Escape quotes and wrap in quotes if field contains comma or quote
-			if strings.Contains(field, ",") || strings.Contains(field, "\"") {
-				field = strings.ReplaceAll(field, "\"", "\"\"")
-				field = "\"" + field + "\""
-			}
-			line += field
-		}
-		ctx.Writer.WriteString(line + "\n")
+	if err := vh.voucherService.ExportCSV(ctx, req, ctx.Writer); err != nil {
+		exportError(ctx, err)
+	}
+}
+
+func (vh *VoucherHandler) exportXLSX(ctx *gin.Context, req *dto.VoucherExportQuery) {
+	var buf bytes.Buffer
+	if err := vh.voucherService.ExportXLSX(ctx, req, &buf); err != nil {
+		exportError(ctx, err)
+		return
+	}
+
+	ctx.Header("Content-Disposition", "attachment; filename=vouchers.xlsx")
+	ctx.Data(http.StatusOK, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", buf.Bytes())
+}
+
+func (vh *VoucherHandler) exportNDJSON(ctx *gin.Context, req *dto.VoucherExportQuery) {
+	ctx.Header("Content-Type", "application/x-ndjson")
+	ctx.Header("Content-Disposition", "attachment; filename=vouchers.ndjson")
+
+	if err := vh.voucherService.ExportNDJSON(ctx, req, ctx.Writer); err != nil {
+		exportError(ctx, err)
 	}
 }
+
+// exportError records a failed export. Before anything was streamed the
+// download headers are cleared so the error middleware can answer with a
+// problem response; afterwards the error can only be logged.
+func exportError(ctx *gin.Context, err error) {
+	if !ctx.Writer.Written() {
+		ctx.Writer.Header().Del("Content-Type")
+		ctx.Writer.Header().Del("Content-Disposition")
+	}
+
+	ctx.Error(err)
+}
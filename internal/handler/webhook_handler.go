@@ -0,0 +1,286 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/alifdwt/techtest-indico-be/internal/apperror"
+	"github.com/alifdwt/techtest-indico-be/internal/dto"
+	"github.com/alifdwt/techtest-indico-be/internal/service"
+	"github.com/alifdwt/techtest-indico-be/internal/util"
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	webhookService *service.WebhookService
+}
+
+func NewWebhookHandler(webhookService *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// CreateWebhook godoc
+// @Summary Create a webhook subscription
+// @Description Subscribe a URL to voucher events. Every event is POSTed as JSON, signed in X-Webhook-Signature with HMAC-SHA256 of "<X-Webhook-Timestamp>.<body>" under the subscription secret. The secret is generated when none is given and is only returned by this call.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param webhook body dto.WebhookSubscriptionRequest true "Webhook subscription"
+// @Success 201 {object} util.Response{data=dto.WebhookSubscriptionResponse}
+// @Failure 400 {object} util.Problem
+// @Failure 409 {object} util.Problem
+// @Failure 500 {object} util.Problem
+// @Router /webhooks [post]
+// @Security BearerAuth
+func (wh *WebhookHandler) CreateWebhook(ctx *gin.Context) {
+	var req dto.WebhookSubscriptionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(bindError(&req, err))
+		return
+	}
+
+	if err := dto.ValidateStruct(&req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	res, err := wh.webhookService.CreateSubscription(ctx, &req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.SuccessResponse(ctx, http.StatusCreated, "Webhook created", res)
+}
+
+// ListWebhooks godoc
+// @Summary List webhook subscriptions
+// @Description Retrieve webhook subscriptions ordered by name. Links to the neighbouring pages are sent in the Link header.
+// @Tags webhooks
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Number of items per page" default(20)
+// @Success 200 {object} util.PaginatedResponse{data=[]dto.WebhookSubscriptionResponse}
+// @Failure 400 {object} util.Problem
+// @Failure 500 {object} util.Problem
+// @Router /webhooks [get]
+// @Security BearerAuth
+func (wh *WebhookHandler) ListWebhooks(ctx *gin.Context) {
+	var req dto.PaginationQuery
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.Error(bindError(&req, err))
+		return
+	}
+
+	if err := dto.ValidateStruct(&req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	res, meta, err := wh.webhookService.ListSubscriptions(ctx, &req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.PaginatedSuccessResponse(ctx, http.StatusOK, "Webhooks listed", res, meta)
+}
+
+// GetWebhook godoc
+// @Summary Get webhook subscription by ID
+// @Description Get a specific webhook subscription by its ID; the secret is not included
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} util.Response{data=dto.WebhookSubscriptionResponse}
+// @Failure 400 {object} util.Problem
+// @Failure 404 {object} util.Problem
+// @Failure 500 {object} util.Problem
+// @Router /webhooks/{id} [get]
+// @Security BearerAuth
+func (wh *WebhookHandler) GetWebhook(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		ctx.Error(apperror.InvalidID("missing_id", "Webhook ID is required"))
+		return
+	}
+
+	res, err := wh.webhookService.GetSubscriptionByID(ctx, id)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.SuccessResponse(ctx, http.StatusOK, "Webhook retrieved", res)
+}
+
+// UpdateWebhook godoc
+// @Summary Update a webhook subscription
+// @Description Replace a webhook subscription. Without a secret the current one is kept; a new secret is returned once. Disabling a subscription pauses its pending deliveries.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param webhook body dto.WebhookSubscriptionRequest true "Webhook subscription"
+// @Success 200 {object} util.Response{data=dto.WebhookSubscriptionResponse}
+// @Failure 400 {object} util.Problem
+// @Failure 404 {object} util.Problem
+// @Failure 409 {object} util.Problem
+// @Failure 500 {object} util.Problem
+// @Router /webhooks/{id} [put]
+// @Security BearerAuth
+func (wh *WebhookHandler) UpdateWebhook(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		ctx.Error(apperror.InvalidID("missing_id", "Webhook ID is required"))
+		return
+	}
+
+	var req dto.WebhookSubscriptionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(bindError(&req, err))
+		return
+	}
+
+	if err := dto.ValidateStruct(&req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	res, err := wh.webhookService.UpdateSubscription(ctx, id, &req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.SuccessResponse(ctx, http.StatusOK, "Webhook updated", res)
+}
+
+// DeleteWebhook godoc
+// @Summary Delete a webhook subscription
+// @Description Delete a webhook subscription together with its deliveries and their logs
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} util.Response
+// @Failure 400 {object} util.Problem
+// @Failure 404 {object} util.Problem
+// @Failure 500 {object} util.Problem
+// @Router /webhooks/{id} [delete]
+// @Security BearerAuth
+func (wh *WebhookHandler) DeleteWebhook(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		ctx.Error(apperror.InvalidID("missing_id", "Webhook ID is required"))
+		return
+	}
+
+	err := wh.webhookService.DeleteSubscription(ctx, id)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.SuccessResponse(ctx, http.StatusOK, "Webhook deleted", nil)
+}
+
+// ListDeliveries godoc
+// @Summary List deliveries of a webhook
+// @Description Retrieve the deliveries of a webhook subscription, most recent first: pending ones with their next attempt, succeeded ones, and dead ones that ran out of attempts. Links to the neighbouring pages are sent in the Link header.
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param status query string false "Delivery status" Enums(pending, succeeded, dead)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Number of deliveries per page" default(20)
+// @Success 200 {object} util.PaginatedResponse{data=[]dto.WebhookDeliveryResponse}
+// @Failure 400 {object} util.Problem
+// @Failure 404 {object} util.Problem
+// @Failure 500 {object} util.Problem
+// @Router /webhooks/{id}/deliveries [get]
+// @Security BearerAuth
+func (wh *WebhookHandler) ListDeliveries(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		ctx.Error(apperror.InvalidID("missing_id", "Webhook ID is required"))
+		return
+	}
+
+	var req dto.WebhookDeliveryQuery
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.Error(bindError(&req, err))
+		return
+	}
+
+	if err := dto.ValidateStruct(&req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	res, meta, err := wh.webhookService.ListDeliveries(ctx, id, &req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.PaginatedSuccessResponse(ctx, http.StatusOK, "Webhook deliveries listed", res, meta)
+}
+
+// GetDelivery godoc
+// @Summary Get a webhook delivery
+// @Description Get a delivery with its payload and the log of its attempts: status code, error, start of the response body and duration of each
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param delivery_id path string true "Delivery ID"
+// @Success 200 {object} util.Response{data=dto.WebhookDeliveryResponse}
+// @Failure 400 {object} util.Problem
+// @Failure 404 {object} util.Problem
+// @Failure 500 {object} util.Problem
+// @Router /webhooks/{id}/deliveries/{delivery_id} [get]
+// @Security BearerAuth
+func (wh *WebhookHandler) GetDelivery(ctx *gin.Context) {
+	id, deliveryID := ctx.Param("id"), ctx.Param("delivery_id")
+	if id == "" || deliveryID == "" {
+		ctx.Error(apperror.InvalidID("missing_id", "Webhook ID and delivery ID are required"))
+		return
+	}
+
+	res, err := wh.webhookService.GetDelivery(ctx, id, deliveryID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.SuccessResponse(ctx, http.StatusOK, "Webhook delivery retrieved", res)
+}
+
+// RedeliverDelivery godoc
+// @Summary Redeliver a webhook event
+// @Description Send the event of a delivery again, e.g. a dead one after the receiver was fixed. The event is queued as a new delivery with a fresh set of attempts and the same X-Webhook-Id.
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param delivery_id path string true "Delivery ID"
+// @Success 202 {object} util.Response{data=dto.WebhookDeliveryResponse}
+// @Failure 400 {object} util.Problem
+// @Failure 404 {object} util.Problem
+// @Failure 500 {object} util.Problem
+// @Router /webhooks/{id}/deliveries/{delivery_id}/redeliver [post]
+// @Security BearerAuth
+func (wh *WebhookHandler) RedeliverDelivery(ctx *gin.Context) {
+	id, deliveryID := ctx.Param("id"), ctx.Param("delivery_id")
+	if id == "" || deliveryID == "" {
+		ctx.Error(apperror.InvalidID("missing_id", "Webhook ID and delivery ID are required"))
+		return
+	}
+
+	res, err := wh.webhookService.RedeliverDelivery(ctx, id, deliveryID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.SuccessResponse(ctx, http.StatusAccepted, "Webhook redelivery queued", res)
+}
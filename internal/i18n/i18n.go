@@ -0,0 +1,71 @@
+// Package i18n holds the translated messages of the API, currently English
+// and Bahasa Indonesia, and picks a language from Accept-Language.
+package i18n
+
+import (
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+const (
+	English    = "en"
+	Indonesian = "id"
+
+	// Default is used when the client asks for nothing we support.
+	Default = English
+)
+
+var matcher = language.NewMatcher([]language.Tag{language.English, language.Indonesian})
+
+// MatchLanguage returns the supported language that best matches an
+// Accept-Language header, e.g. "id-ID,id;q=0.9,en;q=0.8" gives "id".
+func MatchLanguage(acceptLanguage string) string {
+	if strings.TrimSpace(acceptLanguage) == "" {
+		return Default
+	}
+
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return Default
+	}
+
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return Default
+	}
+	if index == 1 {
+		return Indonesian
+	}
+
+	return English
+}
+
+// Translate returns the message with the given ID in lang, falling back to
+// English and then to the ID itself. {name} placeholders are replaced by
+// args, given as name, value pairs.
+func Translate(lang, id string, args ...string) string {
+	message, ok := catalog[lang][id]
+	if !ok {
+		if message, ok = catalog[English][id]; !ok {
+			return id
+		}
+	}
+
+	if len(args) == 0 {
+		return message
+	}
+
+	pairs := make([]string, 0, len(args))
+	for i := 0; i+1 < len(args); i += 2 {
+		pairs = append(pairs, "{"+args[i]+"}", args[i+1])
+	}
+
+	return strings.NewReplacer(pairs...).Replace(message)
+}
+
+// FieldMessage renders the message of a failed validation rule. A list
+// parameter such as the choices of oneof is shown comma separated.
+func FieldMessage(lang, id, rule, param string) string {
+	return Translate(lang, id, "rule", rule, "param", strings.Join(strings.Fields(param), ", "))
+}
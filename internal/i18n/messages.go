@@ -0,0 +1,101 @@
+package i18n
+
+// Message IDs of validation messages. The min and max rules have one
+// message per kind of field: numbers, text and lists. The ones after
+// MsgNotNull are for rules the services check.
+const (
+	MsgValidationFailed = "validation_failed"
+	MsgRequired         = "required"
+	MsgMinNumber        = "min.number"
+	MsgMinString        = "min.string"
+	MsgMinItems         = "min.items"
+	MsgMaxNumber        = "max.number"
+	MsgMaxString        = "max.string"
+	MsgMaxItems         = "max.items"
+	MsgOneOf            = "oneof"
+	MsgUUID             = "uuid"
+	MsgInvalid          = "invalid"
+	MsgUnknownField     = "unknown_field"
+	MsgNotNull          = "not_null"
+	MsgSingleCharacter  = "single_character"
+	MsgEmptyKey         = "empty_key"
+	MsgMappedTwice      = "mapped_twice"
+	MsgNotFound         = "not_found"
+	MsgCronExpression   = "cron_expression"
+	MsgInsideStorage    = "inside_storage"
+	MsgDate             = "date"
+	MsgTimezone         = "timezone"
+	MsgSortSpec         = "sort_spec"
+	MsgExcludedWith     = "excluded_with"
+	MsgRelevance        = "relevance"
+	MsgNotGreaterThan   = "not_greater_than"
+	MsgNotAfter         = "not_after"
+	MsgCursor           = "cursor"
+	MsgExactlyOneOf     = "exactly_one_of"
+	MsgBulkLimit        = "bulk_limit"
+)
+
+var catalog = map[string]map[string]string{
+	English: {
+		MsgValidationFailed: "The request has invalid fields.",
+		MsgRequired:         "is required",
+		MsgMinNumber:        "must be at least {param}",
+		MsgMinString:        "must be at least {param} characters long",
+		MsgMinItems:         "must have at least {param} items",
+		MsgMaxNumber:        "must be at most {param}",
+		MsgMaxString:        "must be at most {param} characters long",
+		MsgMaxItems:         "must have at most {param} items",
+		MsgOneOf:            "must be one of: {param}",
+		MsgUUID:             "must be a valid UUID",
+		MsgInvalid:          "failed the {rule} rule",
+		MsgUnknownField:     "is not a field that can be changed",
+		MsgNotNull:          "cannot be null",
+		MsgSingleCharacter:  "must be a single character such as , ; | or tab",
+		MsgEmptyKey:         "cannot have an empty key",
+		MsgMappedTwice:      "maps to {param}, which another header already maps to",
+		MsgNotFound:         "does not exist",
+		MsgCronExpression:   "must be a cron expression such as 0 1 * * * or a descriptor such as @daily",
+		MsgInsideStorage:    "must stay inside the storage backend",
+		MsgDate:             "must be a date in one of the accepted formats",
+		MsgTimezone:         "must be an IANA time zone such as Asia/Jakarta",
+		MsgSortSpec:         "must be distinct, comma separated fields from: {param}, each prefixed with - for descending order",
+		MsgExcludedWith:     "cannot be combined with {param}",
+		MsgRelevance:        "requires search_mode=fuzzy and a search term",
+		MsgNotGreaterThan:   "cannot be greater than {param}",
+		MsgNotAfter:         "cannot be after {param}",
+		MsgCursor:           "is not a valid cursor for this query and sort order",
+		MsgExactlyOneOf:     "exactly one of {param} is required",
+		MsgBulkLimit:        "is exceeded: the operation would affect more than {param} vouchers",
+	},
+	Indonesian: {
+		MsgValidationFailed: "Permintaan berisi field yang tidak valid.",
+		MsgRequired:         "wajib diisi",
+		MsgMinNumber:        "minimal {param}",
+		MsgMinString:        "minimal {param} karakter",
+		MsgMinItems:         "minimal berisi {param} item",
+		MsgMaxNumber:        "maksimal {param}",
+		MsgMaxString:        "maksimal {param} karakter",
+		MsgMaxItems:         "maksimal berisi {param} item",
+		MsgOneOf:            "harus salah satu dari: {param}",
+		MsgUUID:             "harus berupa UUID yang valid",
+		MsgInvalid:          "tidak memenuhi aturan {rule}",
+		MsgUnknownField:     "bukan field yang dapat diubah",
+		MsgNotNull:          "tidak boleh null",
+		MsgSingleCharacter:  "harus berupa satu karakter seperti , ; | atau tab",
+		MsgEmptyKey:         "tidak boleh memiliki kunci kosong",
+		MsgMappedTwice:      "dipetakan ke {param}, yang sudah dipetakan oleh header lain",
+		MsgNotFound:         "tidak ditemukan",
+		MsgCronExpression:   "harus berupa ekspresi cron seperti 0 1 * * * atau deskriptor seperti @daily",
+		MsgInsideStorage:    "harus tetap berada di dalam storage backend",
+		MsgDate:             "harus berupa tanggal dengan salah satu format yang diterima",
+		MsgTimezone:         "harus berupa zona waktu IANA seperti Asia/Jakarta",
+		MsgSortSpec:         "harus berupa field unik yang dipisahkan koma dari: {param}, diawali - untuk urutan menurun",
+		MsgExcludedWith:     "tidak dapat digabung dengan {param}",
+		MsgRelevance:        "memerlukan search_mode=fuzzy dan kata pencarian",
+		MsgNotGreaterThan:   "tidak boleh lebih besar dari {param}",
+		MsgNotAfter:         "tidak boleh setelah {param}",
+		MsgCursor:           "bukan cursor yang valid untuk query dan urutan ini",
+		MsgExactlyOneOf:     "tepat satu dari {param} wajib diisi",
+		MsgBulkLimit:        "terlampaui: operasi akan mengenai lebih dari {param} voucher",
+	},
+}
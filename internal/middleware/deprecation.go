@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecated marks the routes it is used on as deprecated. Responses carry
+// a Deprecation header (RFC 9745) with the date the routes were deprecated,
+// a Sunset header (RFC 8594) with the date they stop working, and a Link to
+// the same path and query under successorPrefix, e.g. /api/v1/vouchers?page=2
+// for /vouchers?page=2.
+func Deprecated(deprecatedAt, sunsetAt time.Time, successorPrefix string) gin.HandlerFunc {
+	deprecation := fmt.Sprintf("@%d", deprecatedAt.Unix())
+	sunset := sunsetAt.UTC().Format(http.TimeFormat)
+	successorPrefix = strings.TrimSuffix(successorPrefix, "/")
+
+	return func(ctx *gin.Context) {
+		header := ctx.Writer.Header()
+		header.Set("Deprecation", deprecation)
+		header.Set("Sunset", sunset)
+		successor := successorPrefix + ctx.Request.URL.Path
+		if ctx.Request.URL.RawQuery != "" {
+			successor += "?" + ctx.Request.URL.RawQuery
+		}
+		header.Add("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, successor))
+		ctx.Next()
+	}
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestDeprecated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	deprecatedAt := time.Date(2026, 4, 30, 0, 0, 0, 0, time.UTC)
+	sunsetAt := time.Date(2027, 4, 30, 0, 0, 0, 0, time.UTC)
+	router := gin.New()
+	router.GET("/vouchers", Deprecated(deprecatedAt, sunsetAt, "/api/v1/"), func(ctx *gin.Context) {
+		ctx.Status(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		name   string
+		target string
+		link   string
+	}{
+		{"path", "/vouchers", `</api/v1/vouchers>; rel="successor-version"`},
+		{"query", "/vouchers?page=2&search=10%25", `</api/v1/vouchers?page=2&search=10%25>; rel="successor-version"`},
+		{"empty query", "/vouchers?", `</api/v1/vouchers>; rel="successor-version"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			header := rec.Header()
+			if got := header.Get("Link"); got != tt.link {
+				t.Errorf("Link = %s, want %s", got, tt.link)
+			}
+			if got := header.Get("Deprecation"); got != "@1777507200" {
+				t.Errorf("Deprecation = %s, want @1777507200", got)
+			}
+			if got := header.Get("Sunset"); got != "Fri, 30 Apr 2027 00:00:00 GMT" {
+				t.Errorf("Sunset = %s, want Fri, 30 Apr 2027 00:00:00 GMT", got)
+			}
+		})
+	}
+}
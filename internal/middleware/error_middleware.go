@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/alifdwt/techtest-indico-be/internal/apperror"
+	"github.com/alifdwt/techtest-indico-be/internal/i18n"
+	"github.com/alifdwt/techtest-indico-be/internal/util"
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorHandler turns the last error a handler recorded with ctx.Error into
+// an RFC 7807 problem response. Application errors keep their message and
+// code; anything else is logged and reported as a generic internal error.
+// Errors raised after the response started can only be logged.
+func ErrorHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Next()
+
+		if len(ctx.Errors) == 0 {
+			return
+		}
+
+		err := ctx.Errors.Last().Err
+		requestID := GetRequestID(ctx)
+		if ctx.Writer.Written() {
+			log.Printf("request %s: error after response was sent: %v", requestID, err)
+			return
+		}
+
+		problem := util.Problem{
+			Type:      "about:blank",
+			Instance:  ctx.Request.URL.Path,
+			RequestID: requestID,
+		}
+
+		if appErr, ok := apperror.As(err); ok {
+			problem.Status = StatusCode(err)
+			problem.Code = appErr.Code
+			problem.Detail, problem.Errors = localize(ctx, appErr)
+			if appErr.Err != nil {
+				log.Printf("request %s: %v: %v", requestID, appErr, appErr.Err)
+			}
+		} else {
+			log.Printf("request %s: %v", requestID, err)
+			problem.Status = http.StatusInternalServerError
+			problem.Code = "internal_error"
+			problem.Detail = "An unexpected error occurred."
+		}
+		problem.Title = http.StatusText(problem.Status)
+
+		util.ProblemResponse(ctx, problem)
+	}
+}
+
+// localize translates the message and field messages of an error that
+// have a message ID into the language asked for in Accept-Language.
+func localize(ctx *gin.Context, appErr *apperror.Error) (string, []apperror.FieldError) {
+	lang := i18n.MatchLanguage(ctx.GetHeader("Accept-Language"))
+	translated := false
+
+	detail := appErr.Message
+	if appErr.MessageID != "" {
+		detail = i18n.Translate(lang, appErr.MessageID)
+		translated = true
+	}
+
+	fields := make([]apperror.FieldError, len(appErr.Fields))
+	for i, field := range appErr.Fields {
+		if field.MessageID != "" {
+			field.Message = i18n.FieldMessage(lang, field.MessageID, field.Rule, field.Param)
+			translated = true
+		}
+		fields[i] = field
+	}
+
+	if translated {
+		ctx.Header("Content-Language", lang)
+	}
+
+	return detail, fields
+}
+
+// StatusCode maps an error to the HTTP status of its kind.
+func StatusCode(err error) int {
+	switch {
+	case errors.Is(err, apperror.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, apperror.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, apperror.ErrValidation),
+		errors.Is(err, apperror.ErrInvalidID),
+		errors.Is(err, apperror.ErrBadRequest):
+		return http.StatusBadRequest
+	case errors.Is(err, apperror.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, apperror.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, apperror.ErrUnsupportedMediaType):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, apperror.ErrPreconditionFailed):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, apperror.ErrPreconditionRequired):
+		return http.StatusPreconditionRequired
+	default:
+		return http.StatusInternalServerError
+	}
+}
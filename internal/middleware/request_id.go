@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	RequestIDHeader = "X-Request-ID"
+
+	requestIDKey       = "request_id"
+	maxRequestIDLength = 128
+)
+
+// RequestID tags every request with an ID, reusing a well-formed one sent
+// by the client or a proxy, and echoes it in the X-Request-ID header.
+func RequestID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.NewString()
+		}
+
+		ctx.Set(requestIDKey, id)
+		ctx.Header(RequestIDHeader, id)
+		ctx.Next()
+	}
+}
+
+// GetRequestID returns the ID assigned to the request by RequestID.
+func GetRequestID(ctx *gin.Context) string {
+	return ctx.GetString(requestIDKey)
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -8,6 +8,61 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type ImportMappingProfile struct {
+	ID        pgtype.UUID      `json:"id"`
+	Name      string           `json:"name"`
+	Mapping   []byte           `json:"mapping"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+}
+
+type Outbox struct {
+	ID            int64              `json:"id"`
+	EventID       pgtype.UUID        `json:"event_id"`
+	AggregateType string             `json:"aggregate_type"`
+	AggregateID   pgtype.UUID        `json:"aggregate_id"`
+	EventType     string             `json:"event_type"`
+	Payload       []byte             `json:"payload"`
+	Attempts      int32              `json:"attempts"`
+	NextAttemptAt pgtype.Timestamptz `json:"next_attempt_at"`
+	LastError     string             `json:"last_error"`
+	CreatedAt     pgtype.Timestamp   `json:"created_at"`
+	PublishedAt   pgtype.Timestamp   `json:"published_at"`
+	FailedAt      pgtype.Timestamp   `json:"failed_at"`
+}
+
+type ScheduledExport struct {
+	ID             pgtype.UUID      `json:"id"`
+	Name           string           `json:"name"`
+	CronExpression string           `json:"cron_expression"`
+	Format         string           `json:"format"`
+	Profile        string           `json:"profile"`
+	SortBy         string           `json:"sort_by"`
+	SortOrder      string           `json:"sort_order"`
+	Columns        string           `json:"columns"`
+	Storage        string           `json:"storage"`
+	PathPrefix     string           `json:"path_prefix"`
+	Enabled        bool             `json:"enabled"`
+	CreatedAt      pgtype.Timestamp `json:"created_at"`
+	UpdatedAt      pgtype.Timestamp `json:"updated_at"`
+	Filter         []byte           `json:"filter"`
+	Sort           string           `json:"sort"`
+}
+
+type ScheduledExportRun struct {
+	ID                pgtype.UUID        `json:"id"`
+	ScheduledExportID pgtype.UUID        `json:"scheduled_export_id"`
+	TriggeredBy       string             `json:"triggered_by"`
+	Status            string             `json:"status"`
+	Location          string             `json:"location"`
+	SizeBytes         int64              `json:"size_bytes"`
+	Error             string             `json:"error"`
+	StartedAt         pgtype.Timestamp   `json:"started_at"`
+	FinishedAt        pgtype.Timestamp   `json:"finished_at"`
+	LeaseExpiresAt    pgtype.Timestamptz `json:"lease_expires_at"`
+	ScheduledFor      pgtype.Timestamptz `json:"scheduled_for"`
+}
+
 type Voucher struct {
 	ID              pgtype.UUID        `json:"id"`
 	VoucherCode     string             `json:"voucher_code"`
@@ -15,4 +70,66 @@ type Voucher struct {
 	ExpiryDate      pgtype.Timestamptz `json:"expiry_date"`
 	CreatedAt       pgtype.Timestamp   `json:"created_at"`
 	UpdatedAt       pgtype.Timestamp   `json:"updated_at"`
+	Version         int32              `json:"version"`
+}
+
+type VoucherExpiryNotification struct {
+	VoucherID  pgtype.UUID        `json:"voucher_id"`
+	ExpiryDate pgtype.Timestamptz `json:"expiry_date"`
+	NotifiedAt pgtype.Timestamp   `json:"notified_at"`
+}
+
+type VoucherImport struct {
+	ID           pgtype.UUID      `json:"id"`
+	FileName     string           `json:"file_name"`
+	Headers      []string         `json:"headers"`
+	SuccessCount int32            `json:"success_count"`
+	FailedCount  int32            `json:"failed_count"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+}
+
+type VoucherImportFailedRow struct {
+	ID        int64       `json:"id"`
+	ImportID  pgtype.UUID `json:"import_id"`
+	RowNumber int32       `json:"row_number"`
+	Record    []string    `json:"record"`
+	Reason    string      `json:"reason"`
+}
+
+type WebhookDelivery struct {
+	ID             pgtype.UUID        `json:"id"`
+	SubscriptionID pgtype.UUID        `json:"subscription_id"`
+	EventID        pgtype.UUID        `json:"event_id"`
+	EventType      string             `json:"event_type"`
+	Payload        []byte             `json:"payload"`
+	Status         string             `json:"status"`
+	Attempts       int32              `json:"attempts"`
+	NextAttemptAt  pgtype.Timestamptz `json:"next_attempt_at"`
+	LastError      string             `json:"last_error"`
+	CreatedAt      pgtype.Timestamp   `json:"created_at"`
+	CompletedAt    pgtype.Timestamp   `json:"completed_at"`
+	VoucherID      pgtype.UUID        `json:"voucher_id"`
+	RedeliveryOf   pgtype.UUID        `json:"redelivery_of"`
+}
+
+type WebhookDeliveryAttempt struct {
+	ID           int64            `json:"id"`
+	DeliveryID   pgtype.UUID      `json:"delivery_id"`
+	Attempt      int32            `json:"attempt"`
+	StatusCode   int32            `json:"status_code"`
+	Error        string           `json:"error"`
+	ResponseBody string           `json:"response_body"`
+	DurationMs   int32            `json:"duration_ms"`
+	AttemptedAt  pgtype.Timestamp `json:"attempted_at"`
+}
+
+type WebhookSubscription struct {
+	ID        pgtype.UUID      `json:"id"`
+	Name      string           `json:"name"`
+	Url       string           `json:"url"`
+	Secret    string           `json:"secret"`
+	Events    []string         `json:"events"`
+	Enabled   bool             `json:"enabled"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
 }
@@ -11,14 +11,62 @@ import (
 )
 
 type Querier interface {
-	CountVouchers(ctx context.Context, search pgtype.Text) (int64, error)
+	AutocompleteVoucherCodes(ctx context.Context, arg AutocompleteVoucherCodesParams) ([]string, error)
+	ClaimDueWebhookDeliveries(ctx context.Context, arg ClaimDueWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	ClaimExpiredVouchers(ctx context.Context, maxResults int32) ([]Voucher, error)
+	ClaimOutboxEvents(ctx context.Context, arg ClaimOutboxEventsParams) ([]Outbox, error)
+	CountImportMappingProfiles(ctx context.Context) (int64, error)
+	CountScheduledExportRuns(ctx context.Context, scheduledExportID pgtype.UUID) (int64, error)
+	CountScheduledExports(ctx context.Context) (int64, error)
+	CountWebhookDeliveries(ctx context.Context, arg CountWebhookDeliveriesParams) (int64, error)
+	CountWebhookSubscriptions(ctx context.Context) (int64, error)
+	CreateImportMappingProfile(ctx context.Context, arg CreateImportMappingProfileParams) (ImportMappingProfile, error)
+	CreateOutboxEvent(ctx context.Context, arg CreateOutboxEventParams) error
+	CreateScheduledExport(ctx context.Context, arg CreateScheduledExportParams) (ScheduledExport, error)
+	CreateScheduledExportRun(ctx context.Context, arg CreateScheduledExportRunParams) (ScheduledExportRun, error)
 	CreateVoucher(ctx context.Context, arg CreateVoucherParams) (Voucher, error)
-	DeleteVoucher(ctx context.Context, id pgtype.UUID) error
-	GetAllVouchersForExport(ctx context.Context) ([]Voucher, error)
+	CreateVoucherImport(ctx context.Context, arg CreateVoucherImportParams) (VoucherImport, error)
+	CreateVoucherImportFailedRow(ctx context.Context, arg CreateVoucherImportFailedRowParams) error
+	CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) error
+	CreateWebhookDeliveryAttempt(ctx context.Context, arg CreateWebhookDeliveryAttemptParams) error
+	CreateWebhookRedelivery(ctx context.Context, id pgtype.UUID) (WebhookDelivery, error)
+	CreateWebhookSubscription(ctx context.Context, arg CreateWebhookSubscriptionParams) (WebhookSubscription, error)
+	DeadLetterOutboxEvent(ctx context.Context, arg DeadLetterOutboxEventParams) error
+	DeleteImportMappingProfile(ctx context.Context, id pgtype.UUID) error
+	DeletePublishedOutboxEvents(ctx context.Context, retention pgtype.Interval) (int64, error)
+	DeleteScheduledExport(ctx context.Context, id pgtype.UUID) error
+	DeleteVoucher(ctx context.Context, arg DeleteVoucherParams) (int64, error)
+	DeleteWebhookSubscription(ctx context.Context, id pgtype.UUID) error
+	ExtendVoucherExpiry(ctx context.Context, arg ExtendVoucherExpiryParams) (Voucher, error)
+	FailExpiredScheduledExportRuns(ctx context.Context, reason string) error
+	FailOutboxEvent(ctx context.Context, arg FailOutboxEventParams) error
+	FinishScheduledExportRun(ctx context.Context, arg FinishScheduledExportRunParams) (ScheduledExportRun, error)
+	FinishWebhookDeliveryAttempt(ctx context.Context, arg FinishWebhookDeliveryAttemptParams) error
+	GetImportMappingProfileByID(ctx context.Context, id pgtype.UUID) (ImportMappingProfile, error)
+	GetImportMappingProfileByName(ctx context.Context, name string) (ImportMappingProfile, error)
+	GetScheduledExportByID(ctx context.Context, id pgtype.UUID) (ScheduledExport, error)
 	GetVoucherByCode(ctx context.Context, voucherCode string) (Voucher, error)
 	GetVoucherByID(ctx context.Context, id pgtype.UUID) (Voucher, error)
-	ListVouchers(ctx context.Context, arg ListVouchersParams) ([]Voucher, error)
+	GetVoucherImportByID(ctx context.Context, id pgtype.UUID) (VoucherImport, error)
+	GetWebhookDelivery(ctx context.Context, arg GetWebhookDeliveryParams) (WebhookDelivery, error)
+	GetWebhookSubscriptionByID(ctx context.Context, id pgtype.UUID) (WebhookSubscription, error)
+	ListEnabledScheduledExports(ctx context.Context) ([]ScheduledExport, error)
+	ListImportMappingProfiles(ctx context.Context, arg ListImportMappingProfilesParams) ([]ImportMappingProfile, error)
+	ListScheduledExportRuns(ctx context.Context, arg ListScheduledExportRunsParams) ([]ScheduledExportRun, error)
+	ListScheduledExports(ctx context.Context, arg ListScheduledExportsParams) ([]ScheduledExport, error)
+	ListVoucherImportFailedRows(ctx context.Context, importID pgtype.UUID) ([]VoucherImportFailedRow, error)
+	ListVouchersByIDs(ctx context.Context, ids []pgtype.UUID) ([]Voucher, error)
+	ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	ListWebhookDeliveryAttempts(ctx context.Context, deliveryID pgtype.UUID) ([]WebhookDeliveryAttempt, error)
+	ListWebhookSubscriptions(ctx context.Context, arg ListWebhookSubscriptionsParams) ([]WebhookSubscription, error)
+	ListWebhookSubscriptionsForEvent(ctx context.Context, eventType string) ([]WebhookSubscription, error)
+	MarkOutboxEventPublished(ctx context.Context, id int64) error
+	PatchVoucher(ctx context.Context, arg PatchVoucherParams) (Voucher, error)
+	RenewScheduledExportRunLease(ctx context.Context, arg RenewScheduledExportRunLeaseParams) error
+	UpdateImportMappingProfile(ctx context.Context, arg UpdateImportMappingProfileParams) (ImportMappingProfile, error)
+	UpdateScheduledExport(ctx context.Context, arg UpdateScheduledExportParams) (ScheduledExport, error)
 	UpdateVoucher(ctx context.Context, arg UpdateVoucherParams) (Voucher, error)
+	UpdateWebhookSubscription(ctx context.Context, arg UpdateWebhookSubscriptionParams) (WebhookSubscription, error)
 }
 
 var _ Querier = (*Queries)(nil)
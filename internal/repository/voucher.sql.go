@@ -11,16 +11,87 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const countVouchers = `-- name: CountVouchers :one
-SELECT COUNT(*) FROM vouchers
-WHERE ($1::text IS NULL OR voucher_code ILIKE '%' || $1 || '%')
+const autocompleteVoucherCodes = `-- name: AutocompleteVoucherCodes :many
+SELECT voucher_code FROM vouchers
+WHERE lower(voucher_code) LIKE $1
+ORDER BY lower(voucher_code) ASC
+LIMIT $2
 `
 
-func (q *Queries) CountVouchers(ctx context.Context, search pgtype.Text) (int64, error) {
-	row := q.db.QueryRow(ctx, countVouchers, search)
-	var count int64
-	err := row.Scan(&count)
-	return count, err
+type AutocompleteVoucherCodesParams struct {
+	Pattern    string `json:"pattern"`
+	MaxResults int32  `json:"max_results"`
+}
+
+func (q *Queries) AutocompleteVoucherCodes(ctx context.Context, arg AutocompleteVoucherCodesParams) ([]string, error) {
+	rows, err := q.db.Query(ctx, autocompleteVoucherCodes, arg.Pattern, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var voucher_code string
+		if err := rows.Scan(&voucher_code); err != nil {
+			return nil, err
+		}
+		items = append(items, voucher_code)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const claimExpiredVouchers = `-- name: ClaimExpiredVouchers :many
+WITH claimed AS (
+    INSERT INTO voucher_expiry_notifications (voucher_id, expiry_date)
+    SELECT v.id, v.expiry_date FROM vouchers v
+    LEFT JOIN voucher_expiry_notifications n ON n.voucher_id = v.id
+    WHERE v.expiry_date < NOW()
+      AND (n.voucher_id IS NULL OR n.expiry_date <> v.expiry_date)
+    ORDER BY v.expiry_date ASC
+    LIMIT $1
+    ON CONFLICT (voucher_id) DO UPDATE SET
+        expiry_date = EXCLUDED.expiry_date,
+        notified_at = NOW()
+    WHERE voucher_expiry_notifications.expiry_date <> EXCLUDED.expiry_date
+    RETURNING voucher_id
+)
+SELECT vouchers.id, vouchers.voucher_code, vouchers.discount_percent, vouchers.expiry_date, vouchers.created_at, vouchers.updated_at, vouchers.version FROM vouchers
+JOIN claimed ON claimed.voucher_id = vouchers.id
+ORDER BY vouchers.expiry_date ASC, vouchers.id ASC
+`
+
+// Records the vouchers that expired since they were last reported and
+// returns them. A voucher whose expiry date was moved is returned again
+// when it expires anew; vouchers another instance just claimed are skipped.
+func (q *Queries) ClaimExpiredVouchers(ctx context.Context, maxResults int32) ([]Voucher, error) {
+	rows, err := q.db.Query(ctx, claimExpiredVouchers, maxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Voucher{}
+	for rows.Next() {
+		var i Voucher
+		if err := rows.Scan(
+			&i.ID,
+			&i.VoucherCode,
+			&i.DiscountPercent,
+			&i.ExpiryDate,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const createVoucher = `-- name: CreateVoucher :one
@@ -30,7 +101,7 @@ INSERT INTO vouchers (
     expiry_date
 ) VALUES (
     $1, $2, $3
-) RETURNING id, voucher_code, discount_percent, expiry_date, created_at, updated_at
+) RETURNING id, voucher_code, discount_percent, expiry_date, created_at, updated_at, version
 `
 
 type CreateVoucherParams struct {
@@ -49,52 +120,63 @@ func (q *Queries) CreateVoucher(ctx context.Context, arg CreateVoucherParams) (V
 		&i.ExpiryDate,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Version,
 	)
 	return i, err
 }
 
-const deleteVoucher = `-- name: DeleteVoucher :exec
-DELETE FROM vouchers WHERE id = $1
+const deleteVoucher = `-- name: DeleteVoucher :execrows
+DELETE FROM vouchers
+WHERE id = $1
+  AND version = COALESCE($2, version)
 `
 
-func (q *Queries) DeleteVoucher(ctx context.Context, id pgtype.UUID) error {
-	_, err := q.db.Exec(ctx, deleteVoucher, id)
-	return err
+type DeleteVoucherParams struct {
+	ID              pgtype.UUID `json:"id"`
+	ExpectedVersion pgtype.Int4 `json:"expected_version"`
 }
 
-const getAllVouchersForExport = `-- name: GetAllVouchersForExport :many
-SELECT id, voucher_code, discount_percent, expiry_date, created_at, updated_at FROM vouchers ORDER BY created_at DESC
-`
-
-func (q *Queries) GetAllVouchersForExport(ctx context.Context) ([]Voucher, error) {
-	rows, err := q.db.Query(ctx, getAllVouchersForExport)
+func (q *Queries) DeleteVoucher(ctx context.Context, arg DeleteVoucherParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteVoucher, arg.ID, arg.ExpectedVersion)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
-	defer rows.Close()
-	items := []Voucher{}
-	for rows.Next() {
-		var i Voucher
-		if err := rows.Scan(
-			&i.ID,
-			&i.VoucherCode,
-			&i.DiscountPercent,
-			&i.ExpiryDate,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+	return result.RowsAffected(), nil
+}
+
+const extendVoucherExpiry = `-- name: ExtendVoucherExpiry :one
+UPDATE vouchers SET
+    expiry_date = expiry_date + make_interval(days => $1::int),
+    version = version + 1,
+    updated_at = NOW()
+WHERE id = $2
+  AND version = COALESCE($3, version)
+RETURNING id, voucher_code, discount_percent, expiry_date, created_at, updated_at, version
+`
+
+type ExtendVoucherExpiryParams struct {
+	Days            int32       `json:"days"`
+	ID              pgtype.UUID `json:"id"`
+	ExpectedVersion pgtype.Int4 `json:"expected_version"`
+}
+
+func (q *Queries) ExtendVoucherExpiry(ctx context.Context, arg ExtendVoucherExpiryParams) (Voucher, error) {
+	row := q.db.QueryRow(ctx, extendVoucherExpiry, arg.Days, arg.ID, arg.ExpectedVersion)
+	var i Voucher
+	err := row.Scan(
+		&i.ID,
+		&i.VoucherCode,
+		&i.DiscountPercent,
+		&i.ExpiryDate,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Version,
+	)
+	return i, err
 }
 
 const getVoucherByCode = `-- name: GetVoucherByCode :one
-SELECT id, voucher_code, discount_percent, expiry_date, created_at, updated_at FROM vouchers WHERE voucher_code = $1 LIMIT 1
+SELECT id, voucher_code, discount_percent, expiry_date, created_at, updated_at, version FROM vouchers WHERE voucher_code = $1 LIMIT 1
 `
 
 func (q *Queries) GetVoucherByCode(ctx context.Context, voucherCode string) (Voucher, error) {
@@ -107,12 +189,13 @@ func (q *Queries) GetVoucherByCode(ctx context.Context, voucherCode string) (Vou
 		&i.ExpiryDate,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Version,
 	)
 	return i, err
 }
 
 const getVoucherByID = `-- name: GetVoucherByID :one
-SELECT id, voucher_code, discount_percent, expiry_date, created_at, updated_at FROM vouchers WHERE id = $1 LIMIT 1
+SELECT id, voucher_code, discount_percent, expiry_date, created_at, updated_at, version FROM vouchers WHERE id = $1 LIMIT 1
 `
 
 func (q *Queries) GetVoucherByID(ctx context.Context, id pgtype.UUID) (Voucher, error) {
@@ -125,46 +208,19 @@ func (q *Queries) GetVoucherByID(ctx context.Context, id pgtype.UUID) (Voucher,
 		&i.ExpiryDate,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Version,
 	)
 	return i, err
 }
 
-const listVouchers = `-- name: ListVouchers :many
-SELECT id, voucher_code, discount_percent, expiry_date, created_at, updated_at FROM vouchers
-WHERE ($3::text IS NULL OR voucher_code ILIKE '%' || $3 || '%')
-ORDER BY 
-    -- 1. DESCENDING SORTS
-    CASE WHEN $4::text = 'desc' AND $5::text = 'expiry_date' THEN expiry_date END DESC,
-    CASE WHEN $4::text = 'desc' AND $5::text = 'discount_percent' THEN discount_percent END DESC,
-    CASE WHEN $4::text = 'desc' AND $5::text = 'created_at' THEN created_at END DESC,
-    CASE WHEN $4::text = 'desc' AND $5::text = 'updated_at' THEN updated_at END DESC,
-
-    -- 2. ASCENDING SORTS
-    CASE WHEN $4::text = 'asc' AND $5::text = 'expiry_date' THEN expiry_date END ASC,
-    CASE WHEN $4::text = 'asc' AND $5::text = 'discount_percent' THEN discount_percent END ASC,
-    CASE WHEN $4::text = 'asc' AND $5::text = 'created_at' THEN created_at END ASC,
-    CASE WHEN $4::text = 'asc' AND $5::text = 'updated_at' THEN updated_at END ASC,
-
-    id ASC
-LIMIT $1 OFFSET $2
+const listVouchersByIDs = `-- name: ListVouchersByIDs :many
+SELECT id, voucher_code, discount_percent, expiry_date, created_at, updated_at, version FROM vouchers
+WHERE id = ANY($1::uuid[])
+ORDER BY created_at ASC, id ASC
 `
 
-type ListVouchersParams struct {
-	Limit     int32       `json:"limit"`
-	Offset    int32       `json:"offset"`
-	Search    pgtype.Text `json:"search"`
-	SortOrder pgtype.Text `json:"sort_order"`
-	SortBy    pgtype.Text `json:"sort_by"`
-}
-
-func (q *Queries) ListVouchers(ctx context.Context, arg ListVouchersParams) ([]Voucher, error) {
-	rows, err := q.db.Query(ctx, listVouchers,
-		arg.Limit,
-		arg.Offset,
-		arg.Search,
-		arg.SortOrder,
-		arg.SortBy,
-	)
+func (q *Queries) ListVouchersByIDs(ctx context.Context, ids []pgtype.UUID) ([]Voucher, error) {
+	rows, err := q.db.Query(ctx, listVouchersByIDs, ids)
 	if err != nil {
 		return nil, err
 	}
@@ -179,6 +235,7 @@ func (q *Queries) ListVouchers(ctx context.Context, arg ListVouchersParams) ([]V
 			&i.ExpiryDate,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -190,29 +247,75 @@ func (q *Queries) ListVouchers(ctx context.Context, arg ListVouchersParams) ([]V
 	return items, nil
 }
 
+const patchVoucher = `-- name: PatchVoucher :one
+UPDATE vouchers SET
+    voucher_code = COALESCE($1, voucher_code),
+    discount_percent = COALESCE($2, discount_percent),
+    expiry_date = COALESCE($3, expiry_date),
+    version = version + 1,
+    updated_at = NOW()
+WHERE id = $4
+  AND version = COALESCE($5, version)
+RETURNING id, voucher_code, discount_percent, expiry_date, created_at, updated_at, version
+`
+
+type PatchVoucherParams struct {
+	VoucherCode     pgtype.Text        `json:"voucher_code"`
+	DiscountPercent pgtype.Int4        `json:"discount_percent"`
+	ExpiryDate      pgtype.Timestamptz `json:"expiry_date"`
+	ID              pgtype.UUID        `json:"id"`
+	ExpectedVersion pgtype.Int4        `json:"expected_version"`
+}
+
+func (q *Queries) PatchVoucher(ctx context.Context, arg PatchVoucherParams) (Voucher, error) {
+	row := q.db.QueryRow(ctx, patchVoucher,
+		arg.VoucherCode,
+		arg.DiscountPercent,
+		arg.ExpiryDate,
+		arg.ID,
+		arg.ExpectedVersion,
+	)
+	var i Voucher
+	err := row.Scan(
+		&i.ID,
+		&i.VoucherCode,
+		&i.DiscountPercent,
+		&i.ExpiryDate,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
 const updateVoucher = `-- name: UpdateVoucher :one
 UPDATE vouchers SET
-    voucher_code = $2,
-    discount_percent = $3,
-    expiry_date = $4,
+    voucher_code = $1,
+    discount_percent = $2,
+    expiry_date = $3,
+    version = version + 1,
     updated_at = NOW()
-WHERE id = $1
-RETURNING id, voucher_code, discount_percent, expiry_date, created_at, updated_at
+WHERE id = $4
+  AND version = COALESCE($5, version)
+RETURNING id, voucher_code, discount_percent, expiry_date, created_at, updated_at, version
 `
 
 type UpdateVoucherParams struct {
-	ID              pgtype.UUID        `json:"id"`
 	VoucherCode     string             `json:"voucher_code"`
 	DiscountPercent int32              `json:"discount_percent"`
 	ExpiryDate      pgtype.Timestamptz `json:"expiry_date"`
+	ID              pgtype.UUID        `json:"id"`
+	ExpectedVersion pgtype.Int4        `json:"expected_version"`
 }
 
+// expected_version, when set, makes the update apply only to that version.
 func (q *Queries) UpdateVoucher(ctx context.Context, arg UpdateVoucherParams) (Voucher, error) {
 	row := q.db.QueryRow(ctx, updateVoucher,
-		arg.ID,
 		arg.VoucherCode,
 		arg.DiscountPercent,
 		arg.ExpiryDate,
+		arg.ID,
+		arg.ExpectedVersion,
 	)
 	var i Voucher
 	err := row.Scan(
@@ -222,6 +325,7 @@ func (q *Queries) UpdateVoucher(ctx context.Context, arg UpdateVoucherParams) (V
 		&i.ExpiryDate,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Version,
 	)
 	return i, err
 }
@@ -0,0 +1,391 @@
+// Package voucherfilter builds the voucher queries whose filters and
+// ordering are chosen at runtime, which sqlc cannot express without a CASE
+// branch per combination. It is kept out of the sqlc generated repository
+// package but returns its models. Only whitelisted column names are ever
+// interpolated; every value is passed as a bind parameter.
+package voucherfilter
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alifdwt/techtest-indico-be/internal/repository"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Queries runs the filtered voucher queries, on a pool or in a
+// transaction, like repository.Queries.
+type Queries struct {
+	db repository.DBTX
+}
+
+// New creates the queries on a pool or connection.
+func New(db repository.DBTX) *Queries {
+	return &Queries{db: db}
+}
+
+// WithTx returns the queries run in tx.
+func (q *Queries) WithTx(tx pgx.Tx) *Queries {
+	return &Queries{db: tx}
+}
+
+const columns = "id, voucher_code, discount_percent, expiry_date, created_at, updated_at, version"
+
+var sortColumns = map[string]string{
+	"voucher_code":     "voucher_code",
+	"expiry_date":      "expiry_date",
+	"discount_percent": "discount_percent",
+	"created_at":       "created_at",
+	"updated_at":       "updated_at",
+}
+
+// Filter holds the filters shared by the voucher list, count and
+// export queries, so all of them select exactly the same rows.
+type Filter struct {
+	Search string
+	// SearchMode is how Search matches voucher codes: "contains" (the
+	// default), "prefix" or "fuzzy" (trigram similarity).
+	SearchMode  string
+	DiscountMin *int32
+	DiscountMax *int32
+	ExpiryFrom  *time.Time
+	ExpiryTo    *time.Time
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	// Status is "active" or "expired", derived from expiry_date.
+	Status             string
+	ExpiringWithinDays *int32
+
+	// Sort is an explicit multi-column ordering. When empty, SortBy and
+	// SortOrder give a single column.
+	Sort      []SortKey
+	SortBy    string
+	SortOrder string
+
+	// Keyset, when set, restricts the list query to the rows after (or
+	// before) a given row in sort order. It never affects counting.
+	Keyset *Keyset
+}
+
+const (
+	SearchModeContains = "contains"
+	SearchModePrefix   = "prefix"
+	SearchModeFuzzy    = "fuzzy"
+
+	// SortByRelevance ranks fuzzy search results by similarity to the
+	// search term. It has no sort keys, so it cannot be paged by keyset.
+	SortByRelevance = "relevance"
+)
+
+// EscapeLike escapes the LIKE wildcards in a user supplied term, so "10%"
+// matches a literal percent sign.
+func EscapeLike(term string) string {
+	return likeEscaper.Replace(term)
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// Ranked reports whether results are ordered by search relevance.
+func (f Filter) Ranked() bool {
+	return f.SortBy == SortByRelevance && f.SearchMode == SearchModeFuzzy && f.Search != ""
+}
+
+// Keyset is a position in the sorted voucher list: the sort values
+// and ID of a row. Values must be in the order of SortKeys and typed like
+// the columns they belong to.
+type Keyset struct {
+	Values   []any
+	ID       pgtype.UUID
+	Backward bool
+}
+
+// SortKey is one column of the ORDER BY clause.
+type SortKey struct {
+	Column string
+	Desc   bool
+}
+
+// ParseSort parses a sort specification such as
+// "-discount_percent,expiry_date": comma separated columns, ascending
+// unless prefixed with "-". Only whitelisted columns are accepted, each at
+// most once.
+func ParseSort(spec string) ([]SortKey, error) {
+	var keys []SortKey
+	seen := make(map[string]bool)
+	for _, field := range strings.Split(spec, ",") {
+		field = strings.TrimSpace(field)
+
+		key := SortKey{}
+		if strings.HasPrefix(field, "-") {
+			key.Desc = true
+			field = field[1:]
+		}
+
+		column, ok := sortColumns[field]
+		if !ok {
+			if field == "" {
+				return nil, fmt.Errorf("empty sort field")
+			}
+			return nil, fmt.Errorf("unknown sort field '%s', use one of: %s", field, strings.Join(SortFields(), ", "))
+		}
+		if seen[column] {
+			return nil, fmt.Errorf("sort field '%s' is given more than once", field)
+		}
+		seen[column] = true
+
+		key.Column = column
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// SortFields returns the fields vouchers can be sorted by.
+func SortFields() []string {
+	fields := make([]string, 0, len(sortColumns))
+	for field := range sortColumns {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	return fields
+}
+
+// SortKeys returns the effective ordering, without the id tiebreaker that
+// is always appended in ascending order.
+func (f Filter) SortKeys() []SortKey {
+	if f.Ranked() {
+		return nil
+	}
+	if len(f.Sort) > 0 {
+		return f.Sort
+	}
+
+	column, ok := sortColumns[f.SortBy]
+	if !ok {
+		column = "created_at"
+	}
+
+	return []SortKey{{Column: column, Desc: strings.EqualFold(f.SortOrder, "desc")}}
+}
+
+// SortValues returns the values of the sort columns of a voucher, suitable
+// for a Keyset.
+func (f Filter) SortValues(v repository.Voucher) []any {
+	keys := f.SortKeys()
+	values := make([]any, len(keys))
+	for i, key := range keys {
+		switch key.Column {
+		case "voucher_code":
+			values[i] = v.VoucherCode
+		case "expiry_date":
+			values[i] = v.ExpiryDate
+		case "discount_percent":
+			values[i] = v.DiscountPercent
+		case "created_at":
+			values[i] = v.CreatedAt
+		case "updated_at":
+			values[i] = v.UpdatedAt
+		}
+	}
+
+	return values
+}
+
+func (f Filter) whereClause(args []any, withKeyset bool) (string, []any) {
+	var conditions []string
+
+	condition := func(format string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(format, len(args)))
+	}
+
+	if f.Search != "" {
+		switch f.SearchMode {
+		case SearchModePrefix:
+			condition("lower(voucher_code) LIKE $%d", strings.ToLower(EscapeLike(f.Search))+"%")
+		case SearchModeFuzzy:
+			condition("voucher_code %% $%d", f.Search)
+		default:
+			condition("voucher_code ILIKE $%d", "%"+EscapeLike(f.Search)+"%")
+		}
+	}
+	if f.DiscountMin != nil {
+		condition("discount_percent >= $%d", *f.DiscountMin)
+	}
+	if f.DiscountMax != nil {
+		condition("discount_percent <= $%d", *f.DiscountMax)
+	}
+	if f.ExpiryFrom != nil {
+		condition("expiry_date >= $%d", *f.ExpiryFrom)
+	}
+	if f.ExpiryTo != nil {
+		condition("expiry_date <= $%d", *f.ExpiryTo)
+	}
+	// created_at has no time zone and holds UTC, so its bounds are passed
+	// as UTC wall clock times.
+	if f.CreatedFrom != nil {
+		condition("created_at >= $%d", pgtype.Timestamp{Time: f.CreatedFrom.UTC(), Valid: true})
+	}
+	if f.CreatedTo != nil {
+		condition("created_at <= $%d", pgtype.Timestamp{Time: f.CreatedTo.UTC(), Valid: true})
+	}
+	switch f.Status {
+	case "active":
+		conditions = append(conditions, "expiry_date >= NOW()")
+	case "expired":
+		conditions = append(conditions, "expiry_date < NOW()")
+	}
+	if f.ExpiringWithinDays != nil {
+		condition("expiry_date >= NOW() AND expiry_date < NOW() + make_interval(days => $%d)", *f.ExpiringWithinDays)
+	}
+
+	if withKeyset && f.Keyset != nil {
+		var condition string
+		condition, args = f.keysetCondition(args)
+		conditions = append(conditions, condition)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+
+	return "\nWHERE " + strings.Join(conditions, " AND "), args
+}
+
+// keysetCondition selects the rows that come after the keyset row in sort
+// order, or before it when paging backward. Because sort directions can be
+// mixed, the comparison is spelled out column by column:
+// (a > x) OR (a = x AND b > y) OR (a = x AND b = y AND id > z).
+func (f Filter) keysetCondition(args []any) (string, []any) {
+	keys := append(f.SortKeys(), SortKey{Column: "id"})
+	values := append(append([]any{}, f.Keyset.Values...), f.Keyset.ID)
+
+	placeholders := make([]string, len(keys))
+	for i := range keys {
+		args = append(args, values[i])
+		placeholders[i] = fmt.Sprintf("$%d", len(args))
+	}
+
+	var alternatives []string
+	for i, key := range keys {
+		var parts []string
+		for j := 0; j < i; j++ {
+			parts = append(parts, fmt.Sprintf("%s = %s", keys[j].Column, placeholders[j]))
+		}
+
+		operator := ">"
+		if key.Desc != f.Keyset.Backward {
+			operator = "<"
+		}
+		parts = append(parts, fmt.Sprintf("%s %s %s", key.Column, operator, placeholders[i]))
+
+		alternatives = append(alternatives, "("+strings.Join(parts, " AND ")+")")
+	}
+
+	return "(" + strings.Join(alternatives, " OR ") + ")", args
+}
+
+// orderByClause orders by the sort keys with id as tiebreaker. Paging
+// backward reverses every direction; callers reverse the rows again.
+// Ranked results come most similar first.
+func (f Filter) orderByClause(args []any) (string, []any) {
+	if f.Ranked() {
+		args = append(args, f.Search)
+		return fmt.Sprintf("\nORDER BY similarity(voucher_code, $%d) DESC, id ASC", len(args)), args
+	}
+
+	backward := f.Keyset != nil && f.Keyset.Backward
+
+	var parts []string
+	for _, key := range append(f.SortKeys(), SortKey{Column: "id"}) {
+		direction := "ASC"
+		if key.Desc != backward {
+			direction = "DESC"
+		}
+		parts = append(parts, key.Column+" "+direction)
+	}
+
+	return "\nORDER BY " + strings.Join(parts, ", "), args
+}
+
+func scanVoucher(row pgx.Row) (repository.Voucher, error) {
+	var i repository.Voucher
+	err := row.Scan(
+		&i.ID,
+		&i.VoucherCode,
+		&i.DiscountPercent,
+		&i.ExpiryDate,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+// ListVouchers returns one page of the vouchers matching the filter, in its
+// sort order.
+func (q *Queries) ListVouchers(ctx context.Context, filter Filter, limit, offset int32) ([]repository.Voucher, error) {
+	where, args := filter.whereClause(nil, true)
+	orderBy, args := filter.orderByClause(args)
+	args = append(args, limit, offset)
+	query := "SELECT " + columns + " FROM vouchers" + where + orderBy +
+		fmt.Sprintf("\nLIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := q.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []repository.Voucher{}
+	for rows.Next() {
+		i, err := scanVoucher(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// CountVouchers counts the vouchers matching the filter, ignoring the
+// keyset.
+func (q *Queries) CountVouchers(ctx context.Context, filter Filter) (int64, error) {
+	where, args := filter.whereClause(nil, false)
+	row := q.db.QueryRow(ctx, "SELECT COUNT(*) FROM vouchers"+where, args...)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+// StreamVouchers runs the filtered query and hands every row to fn as it is
+// read from the connection, without collecting the result set in memory.
+// Returning an error from fn stops the iteration.
+func (q *Queries) StreamVouchers(ctx context.Context, filter Filter, fn func(repository.Voucher) error) error {
+	where, args := filter.whereClause(nil, true)
+	orderBy, args := filter.orderByClause(args)
+	query := "SELECT " + columns + " FROM vouchers" + where + orderBy
+
+	rows, err := q.db.Query(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		i, err := scanVoucher(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(i); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,271 @@
+package voucherfilter
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// recordingDB records the last query and its arguments and returns no
+// rows.
+type recordingDB struct {
+	sql  string
+	args []any
+}
+
+func (db *recordingDB) Exec(context.Context, string, ...any) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, nil
+}
+
+func (db *recordingDB) Query(_ context.Context, sql string, args ...any) (pgx.Rows, error) {
+	db.sql, db.args = sql, args
+	return emptyRows{}, nil
+}
+
+func (db *recordingDB) QueryRow(_ context.Context, sql string, args ...any) pgx.Row {
+	db.sql, db.args = sql, args
+	return emptyRows{}
+}
+
+type emptyRows struct {
+	pgx.Rows
+}
+
+func (emptyRows) Next() bool { return false }
+
+func (emptyRows) Err() error { return nil }
+
+func (emptyRows) Close() {}
+
+func (emptyRows) Scan(dest ...any) error {
+	*dest[0].(*int64) = 0
+	return nil
+}
+
+func int32Ptr(v int32) *int32 { return &v }
+
+func timePtr(t time.Time) *time.Time { return &t }
+
+func TestListVouchersQuery(t *testing.T) {
+	wib := time.FixedZone("WIB", 7*60*60)
+	expiryFrom := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	expiryTo := time.Date(2030, 12, 31, 23, 59, 59, 0, time.UTC)
+	createdFrom := time.Date(2025, 6, 1, 7, 0, 0, 0, wib)
+	id := pgtype.UUID{Bytes: [16]byte{1}, Valid: true}
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "no filter",
+			filter:   Filter{},
+			wantSQL:  "\nORDER BY created_at ASC, id ASC\nLIMIT $1 OFFSET $2",
+			wantArgs: []any{},
+		},
+		{
+			name:     "contains escapes wildcards",
+			filter:   Filter{Search: `10%_off\`},
+			wantSQL:  "\nWHERE voucher_code ILIKE $1\nORDER BY created_at ASC, id ASC\nLIMIT $2 OFFSET $3",
+			wantArgs: []any{`%10\%\_off\\%`},
+		},
+		{
+			name:     "prefix is lower cased",
+			filter:   Filter{Search: "Save_", SearchMode: SearchModePrefix},
+			wantSQL:  "\nWHERE lower(voucher_code) LIKE $1\nORDER BY created_at ASC, id ASC\nLIMIT $2 OFFSET $3",
+			wantArgs: []any{`save\_%`},
+		},
+		{
+			name:     "fuzzy is not escaped",
+			filter:   Filter{Search: "sav%", SearchMode: SearchModeFuzzy},
+			wantSQL:  "\nWHERE voucher_code % $1\nORDER BY created_at ASC, id ASC\nLIMIT $2 OFFSET $3",
+			wantArgs: []any{"sav%"},
+		},
+		{
+			name:     "fuzzy ranked by relevance",
+			filter:   Filter{Search: "save", SearchMode: SearchModeFuzzy, SortBy: SortByRelevance},
+			wantSQL:  "\nWHERE voucher_code % $1\nORDER BY similarity(voucher_code, $2) DESC, id ASC\nLIMIT $3 OFFSET $4",
+			wantArgs: []any{"save", "save"},
+		},
+		{
+			name: "ranges",
+			filter: Filter{
+				DiscountMin: int32Ptr(10),
+				DiscountMax: int32Ptr(50),
+				ExpiryFrom:  &expiryFrom,
+				ExpiryTo:    &expiryTo,
+				CreatedFrom: &createdFrom,
+				CreatedTo:   timePtr(createdFrom.Add(time.Hour)),
+			},
+			wantSQL: "\nWHERE discount_percent >= $1 AND discount_percent <= $2" +
+				" AND expiry_date >= $3 AND expiry_date <= $4" +
+				" AND created_at >= $5 AND created_at <= $6" +
+				"\nORDER BY created_at ASC, id ASC\nLIMIT $7 OFFSET $8",
+			wantArgs: []any{
+				int32(10), int32(50), expiryFrom, expiryTo,
+				pgtype.Timestamp{Time: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+				pgtype.Timestamp{Time: time.Date(2025, 6, 1, 1, 0, 0, 0, time.UTC), Valid: true},
+			},
+		},
+		{
+			name:     "active",
+			filter:   Filter{Status: "active", DiscountMin: int32Ptr(20)},
+			wantSQL:  "\nWHERE discount_percent >= $1 AND expiry_date >= NOW()\nORDER BY created_at ASC, id ASC\nLIMIT $2 OFFSET $3",
+			wantArgs: []any{int32(20)},
+		},
+		{
+			name:     "expired",
+			filter:   Filter{Status: "expired"},
+			wantSQL:  "\nWHERE expiry_date < NOW()\nORDER BY created_at ASC, id ASC\nLIMIT $1 OFFSET $2",
+			wantArgs: []any{},
+		},
+		{
+			name:   "expiring within days",
+			filter: Filter{Search: "a", Status: "active", ExpiringWithinDays: int32Ptr(7)},
+			wantSQL: "\nWHERE voucher_code ILIKE $1 AND expiry_date >= NOW()" +
+				" AND expiry_date >= NOW() AND expiry_date < NOW() + make_interval(days => $2)" +
+				"\nORDER BY created_at ASC, id ASC\nLIMIT $3 OFFSET $4",
+			wantArgs: []any{"%a%", int32(7)},
+		},
+		{
+			name:     "sort by and order",
+			filter:   Filter{SortBy: "discount_percent", SortOrder: "DESC"},
+			wantSQL:  "\nORDER BY discount_percent DESC, id ASC\nLIMIT $1 OFFSET $2",
+			wantArgs: []any{},
+		},
+		{
+			name:     "unknown sort by falls back",
+			filter:   Filter{SortBy: "id; DROP TABLE vouchers", SortOrder: "desc"},
+			wantSQL:  "\nORDER BY created_at DESC, id ASC\nLIMIT $1 OFFSET $2",
+			wantArgs: []any{},
+		},
+		{
+			name: "keyset after filters",
+			filter: Filter{
+				DiscountMin: int32Ptr(5),
+				Sort:        []SortKey{{Column: "voucher_code"}, {Column: "discount_percent", Desc: true}},
+				Keyset:      &Keyset{Values: []any{"SAVE10", int32(10)}, ID: id},
+			},
+			wantSQL: "\nWHERE discount_percent >= $1 AND ((voucher_code > $2)" +
+				" OR (voucher_code = $2 AND discount_percent < $3)" +
+				" OR (voucher_code = $2 AND discount_percent = $3 AND id > $4))" +
+				"\nORDER BY voucher_code ASC, discount_percent DESC, id ASC\nLIMIT $5 OFFSET $6",
+			wantArgs: []any{int32(5), "SAVE10", int32(10), id},
+		},
+		{
+			name: "keyset backward",
+			filter: Filter{
+				Sort:   []SortKey{{Column: "voucher_code"}, {Column: "discount_percent", Desc: true}},
+				Keyset: &Keyset{Values: []any{"SAVE10", int32(10)}, ID: id, Backward: true},
+			},
+			wantSQL: "\nWHERE ((voucher_code < $1)" +
+				" OR (voucher_code = $1 AND discount_percent > $2)" +
+				" OR (voucher_code = $1 AND discount_percent = $2 AND id < $3))" +
+				"\nORDER BY voucher_code DESC, discount_percent ASC, id DESC\nLIMIT $4 OFFSET $5",
+			wantArgs: []any{"SAVE10", int32(10), id},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &recordingDB{}
+			if _, err := New(db).ListVouchers(context.Background(), tt.filter, 10, 20); err != nil {
+				t.Fatal(err)
+			}
+
+			if want := "SELECT " + columns + " FROM vouchers" + tt.wantSQL; db.sql != want {
+				t.Errorf("sql =\n%s\nwant\n%s", db.sql, want)
+			}
+			if want := append(tt.wantArgs, int32(10), int32(20)); !reflect.DeepEqual(db.args, want) {
+				t.Errorf("args = %#v, want %#v", db.args, want)
+			}
+		})
+	}
+}
+
+func TestCountVouchersIgnoresKeysetAndOrder(t *testing.T) {
+	db := &recordingDB{}
+	filter := Filter{
+		Status:      "active",
+		DiscountMax: int32Ptr(30),
+		Sort:        []SortKey{{Column: "voucher_code"}},
+		Keyset:      &Keyset{Values: []any{"SAVE10"}, ID: pgtype.UUID{Bytes: [16]byte{1}, Valid: true}},
+	}
+
+	if _, err := New(db).CountVouchers(context.Background(), filter); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "SELECT COUNT(*) FROM vouchers\nWHERE discount_percent <= $1 AND expiry_date >= NOW()"; db.sql != want {
+		t.Errorf("sql = %q, want %q", db.sql, want)
+	}
+	if want := []any{int32(30)}; !reflect.DeepEqual(db.args, want) {
+		t.Errorf("args = %#v, want %#v", db.args, want)
+	}
+}
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    []SortKey
+		wantErr string
+	}{
+		{spec: "voucher_code", want: []SortKey{{Column: "voucher_code"}}},
+		{
+			spec: "-discount_percent, expiry_date,-updated_at",
+			want: []SortKey{{Column: "discount_percent", Desc: true}, {Column: "expiry_date"}, {Column: "updated_at", Desc: true}},
+		},
+		{spec: "id", wantErr: "unknown sort field 'id', use one of: created_at, discount_percent, expiry_date, updated_at, voucher_code"},
+		{spec: "voucher_code; DROP TABLE vouchers", wantErr: "unknown sort field 'voucher_code; DROP TABLE vouchers', use one of: created_at, discount_percent, expiry_date, updated_at, voucher_code"},
+		{spec: "expiry_date,-expiry_date", wantErr: "sort field 'expiry_date' is given more than once"},
+		{spec: "voucher_code,", wantErr: "empty sort field"},
+		{spec: "-", wantErr: "empty sort field"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseSort(tt.spec)
+
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("ParseSort() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSort() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSort() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	tests := []struct {
+		term string
+		want string
+	}{
+		{"SAVE10", "SAVE10"},
+		{"10%", `10\%`},
+		{"a_b", `a\_b`},
+		{`back\slash`, `back\\slash`},
+		{`\%_`, `\\\%\_`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.term, func(t *testing.T) {
+			if got := EscapeLike(tt.term); got != tt.want {
+				t.Errorf("EscapeLike(%q) = %q, want %q", tt.term, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package routes
+
+import (
+	"github.com/alifdwt/techtest-indico-be/internal/config"
+	"github.com/alifdwt/techtest-indico-be/internal/handler"
+	"github.com/alifdwt/techtest-indico-be/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// V1Handlers are the handlers serving version 1 of the API.
+type V1Handlers struct {
+	Auth            *handler.AuthHandler
+	Voucher         *handler.VoucherHandler
+	ImportMapping   *handler.ImportMappingHandler
+	ScheduledExport *handler.ScheduledExportHandler
+	GraphQL         *handler.GraphQLHandler
+	Webhook         *handler.WebhookHandler
+}
+
+// SetupAPIRoutes mounts each version of the API under /api/<version>.
+// Versions register their routes separately, so a /api/v2 with its own
+// handlers and DTOs can sit next to v1 without changing it. The
+// unversioned paths of the first release remain as deprecated aliases of
+// v1 until they are disabled; endpoints added since, like /graphql and
+// /webhooks, exist only under /api/v1.
+func SetupAPIRoutes(router *gin.Engine, v1 V1Handlers, cfg config.APIConfig) {
+	v1Group := router.Group("/api/v1")
+	setupV1Routes(v1Group, v1)
+	SetupGraphQLRoutes(v1Group, v1.GraphQL)
+	SetupWebhookRoutes(v1Group, v1.Webhook)
+
+	if cfg.LegacyRoutesEnabled {
+		legacy := router.Group("", middleware.Deprecated(cfg.LegacyDeprecatedAt, cfg.LegacySunsetAt, "/api/v1"))
+		setupV1Routes(legacy, v1)
+	}
+}
+
+func setupV1Routes(router gin.IRouter, handlers V1Handlers) {
+	SetupAuthRoutes(router, handlers.Auth)
+	SetupVoucherRoutes(router, handlers.Voucher)
+	SetupImportMappingRoutes(router, handlers.ImportMapping)
+	SetupScheduledExportRoutes(router, handlers.ScheduledExport)
+}
@@ -6,7 +6,7 @@ import (
 )
 
 func SetupAuthRoutes(
-	router *gin.Engine,
+	router gin.IRouter,
 	authHandler *handler.AuthHandler,
 ) {
 	login := router.Group("/login")
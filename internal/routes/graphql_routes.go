@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"github.com/alifdwt/techtest-indico-be/internal/handler"
+	"github.com/alifdwt/techtest-indico-be/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupGraphQLRoutes(
+	router gin.IRouter,
+	graphQLHandler *handler.GraphQLHandler,
+) {
+	router.POST("/graphql", middleware.AuthMiddleware(), graphQLHandler.Query)
+}
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/alifdwt/techtest-indico-be/internal/handler"
+	"github.com/alifdwt/techtest-indico-be/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupImportMappingRoutes(
+	router gin.IRouter,
+	importMappingHandler *handler.ImportMappingHandler,
+) {
+	mappingGroup := router.Group("/import-mappings")
+	mappingGroup.Use(middleware.AuthMiddleware())
+	{
+		mappingGroup.POST("", importMappingHandler.CreateProfile)
+		mappingGroup.GET("", importMappingHandler.ListProfiles)
+		mappingGroup.GET("/:id", importMappingHandler.GetProfile)
+		mappingGroup.PUT("/:id", importMappingHandler.UpdateProfile)
+		mappingGroup.DELETE("/:id", importMappingHandler.DeleteProfile)
+	}
+}
@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"github.com/alifdwt/techtest-indico-be/internal/handler"
+	"github.com/alifdwt/techtest-indico-be/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupScheduledExportRoutes(
+	router gin.IRouter,
+	scheduledExportHandler *handler.ScheduledExportHandler,
+) {
+	exportGroup := router.Group("/scheduled-exports")
+	exportGroup.Use(middleware.AuthMiddleware())
+	{
+		exportGroup.POST("", scheduledExportHandler.CreateExport)
+		exportGroup.GET("", scheduledExportHandler.ListExports)
+		exportGroup.GET("/:id", scheduledExportHandler.GetExport)
+		exportGroup.PUT("/:id", scheduledExportHandler.UpdateExport)
+		exportGroup.DELETE("/:id", scheduledExportHandler.DeleteExport)
+		exportGroup.POST("/:id/run", scheduledExportHandler.RunExport)
+		exportGroup.GET("/:id/runs", scheduledExportHandler.ListRuns)
+	}
+}
@@ -7,7 +7,7 @@ import (
 )
 
 func SetupVoucherRoutes(
-	router *gin.Engine,
+	router gin.IRouter,
 	voucherHandler *handler.VoucherHandler,
 ) {
 	voucherGroup := router.Group("/vouchers")
@@ -15,11 +15,16 @@ func SetupVoucherRoutes(
 	{
 		voucherGroup.POST("", voucherHandler.CreateVoucher)
 		voucherGroup.GET("", voucherHandler.ListVouchers)
+		voucherGroup.GET("/autocomplete", voucherHandler.AutocompleteVouchers)
 		voucherGroup.GET("/:id", voucherHandler.GetVoucher)
 		voucherGroup.PUT("/:id", voucherHandler.UpdateVoucher)
+		voucherGroup.PATCH("/:id", voucherHandler.PatchVoucher)
 		voucherGroup.DELETE("/:id", voucherHandler.DeleteVoucher)
 
+		voucherGroup.POST("/bulk", voucherHandler.BulkVouchers)
 		voucherGroup.POST("/upload-csv", voucherHandler.UploadCSV)
-		voucherGroup.GET("/export", voucherHandler.ExportCSV)
+		voucherGroup.POST("/import-json", voucherHandler.ImportJSON)
+		voucherGroup.GET("/imports/:id/error-report", voucherHandler.ImportErrorReport)
+		voucherGroup.GET("/export", voucherHandler.ExportVouchers)
 	}
 }
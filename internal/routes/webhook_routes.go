@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/alifdwt/techtest-indico-be/internal/handler"
+	"github.com/alifdwt/techtest-indico-be/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupWebhookRoutes(
+	router gin.IRouter,
+	webhookHandler *handler.WebhookHandler,
+) {
+	webhookGroup := router.Group("/webhooks")
+	webhookGroup.Use(middleware.AuthMiddleware())
+	{
+		webhookGroup.POST("", webhookHandler.CreateWebhook)
+		webhookGroup.GET("", webhookHandler.ListWebhooks)
+		webhookGroup.GET("/:id", webhookHandler.GetWebhook)
+		webhookGroup.PUT("/:id", webhookHandler.UpdateWebhook)
+		webhookGroup.DELETE("/:id", webhookHandler.DeleteWebhook)
+		webhookGroup.GET("/:id/deliveries", webhookHandler.ListDeliveries)
+		webhookGroup.GET("/:id/deliveries/:delivery_id", webhookHandler.GetDelivery)
+		webhookGroup.POST("/:id/deliveries/:delivery_id/redeliver", webhookHandler.RedeliverDelivery)
+	}
+}
@@ -1,6 +1,14 @@
 package service
 
-import "github.com/alifdwt/techtest-indico-be/internal/dto"
+import (
+	"strings"
+
+	"github.com/alifdwt/techtest-indico-be/internal/apperror"
+	"github.com/alifdwt/techtest-indico-be/internal/dto"
+)
+
+// authToken is the token handed out by Login.
+const authToken = "iniadalahtokenbohongan"
 
 type AuthService struct {
 }
@@ -11,6 +19,20 @@ func NewAuthService() *AuthService {
 
 func (s *AuthService) Login(req *dto.LoginRequest) (*dto.LoginResponse, error) {
 	return &dto.LoginResponse{
-		Token: "iniadalahtokenbohongan",
+		Token: authToken,
 	}, nil
 }
+
+// Authenticate checks the credentials of a request, given as an HTTP
+// Authorization header or gRPC authorization metadata: "Bearer <token>".
+func Authenticate(authorization string) error {
+	if authorization == "" {
+		return apperror.Unauthorized("missing_token", "Authorization header is missing")
+	}
+
+	if strings.Replace(authorization, "Bearer ", "", 1) != authToken {
+		return apperror.Unauthorized("invalid_token", "Invalid token")
+	}
+
+	return nil
+}
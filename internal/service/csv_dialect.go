@@ -0,0 +1,226 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/alifdwt/techtest-indico-be/internal/dto"
+	"github.com/alifdwt/techtest-indico-be/internal/i18n"
+	"golang.org/x/text/encoding/charmap"
+)
+
+const (
+	importFormatCSV  = "csv"
+	importFormatXLSX = "xlsx"
+
+	encodingUTF8        = "utf-8"
+	encodingWindows1252 = "windows-1252"
+	encodingISO88591    = "iso-8859-1"
+
+	// sniffSize is how much of the file is inspected to detect the dialect.
+	sniffSize = 64 * 1024
+)
+
+var (
+	utf8BOM             = []byte{0xEF, 0xBB, 0xBF}
+	candidateDelimiters = []rune{',', ';', '\t', '|'}
+)
+
+// recordReader yields the rows of an uploaded file together with the line
+// (or sheet row) number each one came from.
+type recordReader interface {
+	Read() (record []string, line int, err error)
+}
+
+type csvRecordReader struct {
+	reader *csv.Reader
+	line   int
+}
+
+func (r *csvRecordReader) Read() ([]string, int, error) {
+	record, err := r.reader.Read()
+	r.line++
+
+	return record, r.line, err
+}
+
+// csvDialect describes how an uploaded file was written.
+type csvDialect struct {
+	Delimiter rune
+	Encoding  string
+	HasBOM    bool
+}
+
+// newCSVReader inspects the start of the file to work out its encoding and
+// delimiter, strips a UTF-8 BOM if present and returns a reader that yields
+// UTF-8 records. Explicit options always take precedence over detection.
+func newCSVReader(file io.Reader, opts *dto.CSVUploadOptions) (*csv.Reader, *csvDialect, error) {
+	if opts == nil {
+		opts = &dto.CSVUploadOptions{}
+	}
+
+	buffered := bufio.NewReaderSize(file, sniffSize)
+	sample, err := buffered.Peek(sniffSize)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, nil, importFileError("failed to read csv file: %s", err.Error()).Wrap(err)
+	}
+	if len(sample) == 0 {
+		return nil, nil, importFileError("csv file is empty")
+	}
+
+	dialect := &csvDialect{}
+
+	if bytes.HasPrefix(sample, utf8BOM) {
+		dialect.HasBOM = true
+		if _, err := buffered.Discard(len(utf8BOM)); err != nil {
+			return nil, nil, importFileError("failed to read csv file: %s", err.Error()).Wrap(err)
+		}
+		sample = sample[len(utf8BOM):]
+	}
+
+	dialect.Encoding, err = resolveEncoding(opts.Encoding, sample, dialect.HasBOM)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dialect.Delimiter, err = resolveDelimiter(opts.Delimiter, sample)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var decoded io.Reader = buffered
+	switch dialect.Encoding {
+	case encodingWindows1252:
+		decoded = charmap.Windows1252.NewDecoder().Reader(buffered)
+	case encodingISO88591:
+		decoded = charmap.ISO8859_1.NewDecoder().Reader(buffered)
+	}
+
+	reader := csv.NewReader(decoded)
+	reader.Comma = dialect.Delimiter
+	reader.TrimLeadingSpace = true
+
+	return reader, dialect, nil
+}
+
+func resolveEncoding(override string, sample []byte, hasBOM bool) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(override)) {
+	case "":
+	case "utf-8", "utf8":
+		return encodingUTF8, nil
+	case "windows-1252", "cp1252":
+		return encodingWindows1252, nil
+	case "iso-8859-1", "latin1":
+		return encodingISO88591, nil
+	default:
+		return "", dto.FieldRuleError("unsupported_encoding", "encoding", "oneof", "utf-8 windows-1252 iso-8859-1", i18n.MsgOneOf, "unsupported encoding '%s', use utf-8, windows-1252 or iso-8859-1", override)
+	}
+
+	if hasBOM || utf8.Valid(trimPartialRune(sample)) {
+		return encodingUTF8, nil
+	}
+
+	// Anything that is not valid UTF-8 is almost always an Excel export using
+	// the Windows code page.
+	return encodingWindows1252, nil
+}
+
+// trimPartialRune drops a multi-byte character cut in half at the end of
+// the sample, so it is not mistaken for invalid UTF-8.
+func trimPartialRune(sample []byte) []byte {
+	for i := 1; i < utf8.UTFMax && i <= len(sample); i++ {
+		if utf8.RuneStart(sample[len(sample)-i]) {
+			if !utf8.FullRune(sample[len(sample)-i:]) {
+				return sample[:len(sample)-i]
+			}
+			break
+		}
+	}
+
+	return sample
+}
+
+func resolveDelimiter(override string, sample []byte) (rune, error) {
+	switch override {
+	case "":
+	case "tab", `\t`:
+		return '\t', nil
+	default:
+		delimiter, size := utf8.DecodeRuneInString(override)
+		if size != len(override) || delimiter == '"' || delimiter == '\r' || delimiter == '\n' {
+			return 0, dto.FieldRuleError("invalid_delimiter", "delimiter", "single_character", "", i18n.MsgSingleCharacter, "invalid delimiter '%s', use a single character such as , ; | or tab", override)
+		}
+		return delimiter, nil
+	}
+
+	header := firstLine(sample)
+
+	var best rune
+	var bestCount int
+	for _, candidate := range candidateDelimiters {
+		if count := countOutsideQuotes(header, candidate); count > bestCount {
+			best, bestCount = candidate, count
+		}
+	}
+
+	if bestCount == 0 {
+		return 0, importFileError("could not detect the csv delimiter from the header row, set the delimiter field explicitly")
+	}
+
+	return best, nil
+}
+
+func describeDelimiter(delimiter rune) string {
+	if delimiter == '\t' {
+		return "tab"
+	}
+
+	return string(delimiter)
+}
+
+// firstLine returns the header row of the sample, taking quoted fields that
+// contain line breaks into account.
+func firstLine(sample []byte) string {
+	inQuotes := false
+	for i, b := range sample {
+		switch b {
+		case '"':
+			inQuotes = !inQuotes
+		case '\n', '\r':
+			if !inQuotes {
+				return string(sample[:i])
+			}
+		}
+	}
+
+	return string(sample)
+}
+
+func countOutsideQuotes(line string, delimiter rune) int {
+	count := 0
+	inQuotes := false
+	for _, r := range line {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+		case r == delimiter && !inQuotes:
+			count++
+		}
+	}
+
+	return count
+}
+
+// normalizeHeader turns a raw header cell into the lookup key used by the
+// importer, e.g. ` "Voucher_Code" ` becomes `voucher_code`.
+func normalizeHeader(header string) string {
+	header = strings.TrimPrefix(header, "\ufeff")
+	header = strings.TrimSpace(header)
+	header = strings.Trim(header, `"'`)
+
+	return strings.ToLower(strings.TrimSpace(header))
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/alifdwt/techtest-indico-be/internal/apperror"
+	"github.com/alifdwt/techtest-indico-be/internal/dto"
+)
+
+func TestNewCSVReader(t *testing.T) {
+	// A file whose sniffed sample ends halfway through a two byte "é".
+	cutRune := "voucher_code,note\n" + strings.Repeat("x", sniffSize-len("voucher_code,note\n")-1) + "é\n"
+
+	tests := []struct {
+		name string
+		file string
+		opts dto.CSVUploadOptions
+
+		want    csvDialect
+		records [][]string
+	}{
+		{
+			name:    "plain utf-8",
+			file:    "voucher_code,discount_percent\nSAVE10,10\n",
+			want:    csvDialect{Delimiter: ',', Encoding: encodingUTF8},
+			records: [][]string{{"voucher_code", "discount_percent"}, {"SAVE10", "10"}},
+		},
+		{
+			name:    "bom is stripped",
+			file:    "\xEF\xBB\xBFvoucher_code;discount_percent\nCAFÉ;10\n",
+			want:    csvDialect{Delimiter: ';', Encoding: encodingUTF8, HasBOM: true},
+			records: [][]string{{"voucher_code", "discount_percent"}, {"CAFÉ", "10"}},
+		},
+		{
+			name:    "windows-1252",
+			file:    "voucher_code;note\nCAF\xC9;\x8010 off\n",
+			want:    csvDialect{Delimiter: ';', Encoding: encodingWindows1252},
+			records: [][]string{{"voucher_code", "note"}, {"CAFÉ", "€10 off"}},
+		},
+		{
+			name:    "character cut at the end of the sample",
+			file:    cutRune,
+			want:    csvDialect{Delimiter: ',', Encoding: encodingUTF8},
+			records: [][]string{{"voucher_code", "note"}},
+		},
+		{
+			name:    "encoding option",
+			file:    "voucher_code,note\nA,\x80\n",
+			opts:    dto.CSVUploadOptions{Encoding: "Latin1"},
+			want:    csvDialect{Delimiter: ',', Encoding: encodingISO88591},
+			records: [][]string{{"voucher_code", "note"}, {"A", "\u0080"}},
+		},
+		{
+			name:    "tab",
+			file:    "voucher_code\tdiscount_percent\nSAVE10\t10\n",
+			want:    csvDialect{Delimiter: '\t', Encoding: encodingUTF8},
+			records: [][]string{{"voucher_code", "discount_percent"}, {"SAVE10", "10"}},
+		},
+		{
+			name:    "pipe",
+			file:    "voucher_code|discount_percent|expiry_date\n",
+			want:    csvDialect{Delimiter: '|', Encoding: encodingUTF8},
+			records: [][]string{{"voucher_code", "discount_percent", "expiry_date"}},
+		},
+		{
+			name:    "commas inside quotes",
+			file:    "\"code, as printed\";\"a,b,c\";discount\nSAVE10;x;10\n",
+			want:    csvDialect{Delimiter: ';', Encoding: encodingUTF8},
+			records: [][]string{{"code, as printed", "a,b,c", "discount"}, {"SAVE10", "x", "10"}},
+		},
+		{
+			name:    "line break inside a quoted header",
+			file:    "\"voucher\ncode\",\"discount;percent\",\"expiry;date\"\n",
+			want:    csvDialect{Delimiter: ',', Encoding: encodingUTF8},
+			records: [][]string{{"voucher\ncode", "discount;percent", "expiry;date"}},
+		},
+		{
+			name:    "delimiter option",
+			file:    "voucher_code;note\nSAVE10;a,b,c\n",
+			opts:    dto.CSVUploadOptions{Delimiter: ";"},
+			want:    csvDialect{Delimiter: ';', Encoding: encodingUTF8},
+			records: [][]string{{"voucher_code", "note"}, {"SAVE10", "a,b,c"}},
+		},
+		{
+			name:    "tab option",
+			file:    "voucher_code\n",
+			opts:    dto.CSVUploadOptions{Delimiter: "tab"},
+			want:    csvDialect{Delimiter: '\t', Encoding: encodingUTF8},
+			records: [][]string{{"voucher_code"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, dialect, err := newCSVReader(strings.NewReader(tt.file), &tt.opts)
+			if err != nil {
+				t.Fatalf("newCSVReader() = %v", err)
+			}
+
+			if *dialect != tt.want {
+				t.Errorf("dialect = %+v, want %+v", *dialect, tt.want)
+			}
+			for _, want := range tt.records {
+				record, err := reader.Read()
+				if err != nil {
+					t.Fatalf("Read() = %v", err)
+				}
+				if !reflect.DeepEqual(record, want) {
+					t.Errorf("record = %q, want %q", record, want)
+				}
+			}
+		})
+	}
+}
+
+func TestNewCSVReaderRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		opts dto.CSVUploadOptions
+		want string
+	}{
+		{name: "empty file", file: "", want: "invalid_import_file"},
+		{name: "single column", file: "voucher_code\nSAVE10\n", want: "invalid_import_file"},
+		{name: "delimiter only inside quotes", file: "\"a,b\"\n", want: "invalid_import_file"},
+		{name: "unknown encoding", file: "a,b\n", opts: dto.CSVUploadOptions{Encoding: "utf-16"}, want: "unsupported_encoding"},
+		{name: "long delimiter", file: "a,b\n", opts: dto.CSVUploadOptions{Delimiter: ";;"}, want: "invalid_delimiter"},
+		{name: "quote delimiter", file: "a,b\n", opts: dto.CSVUploadOptions{Delimiter: `"`}, want: "invalid_delimiter"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := newCSVReader(strings.NewReader(tt.file), &tt.opts)
+
+			var appErr *apperror.Error
+			if !errors.As(err, &appErr) || appErr.Code != tt.want {
+				t.Errorf("newCSVReader() = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
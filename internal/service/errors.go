@@ -0,0 +1,49 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/alifdwt/techtest-indico-be/internal/apperror"
+	"github.com/alifdwt/techtest-indico-be/internal/dto"
+	"github.com/alifdwt/techtest-indico-be/internal/i18n"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// isUniqueViolation reports whether err is a Postgres unique constraint
+// violation.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+func voucherCodeTaken(code string) error {
+	return apperror.Conflict("voucher_code_taken", "voucher with code %s already exists", code)
+}
+
+// voucherModified reports a write whose If-Match no longer matches the
+// voucher, because someone else changed or deleted it in the meantime.
+func voucherModified() error {
+	return apperror.PreconditionFailed("voucher_modified", "voucher has been modified since it was retrieved; fetch it again and retry")
+}
+
+func webhookNotFound() error {
+	return apperror.NotFound("webhook_not_found", "webhook not found")
+}
+
+func webhookNameTaken(name string) error {
+	return apperror.Conflict("webhook_name_taken", "webhook with name %s already exists", name)
+}
+
+func invalidCursor(message string) error {
+	return dto.FieldRuleError("invalid_cursor", "cursor", "cursor", "", i18n.MsgCursor, "%s", message)
+}
+
+func invalidExpiryDate(err error) error {
+	return dto.FieldRuleError("invalid_expiry_date", "expiry_date", "date", "", i18n.MsgDate, "%s", err.Error())
+}
+
+// importFileError reports an uploaded file that cannot be imported at all,
+// as opposed to individual rows that fail.
+func importFileError(format string, args ...any) *apperror.Error {
+	return apperror.BadRequest("invalid_import_file", format, args...)
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/alifdwt/techtest-indico-be/internal/apperror"
+	"github.com/alifdwt/techtest-indico-be/internal/dto"
+	"github.com/alifdwt/techtest-indico-be/internal/i18n"
+	"github.com/alifdwt/techtest-indico-be/internal/repository"
+	"github.com/alifdwt/techtest-indico-be/internal/util"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// voucherImportFields are the voucher fields an import column can be mapped
+// onto. All of them are required in every imported file.
+var voucherImportFields = []string{"voucher_code", "discount_percent", "expiry_date"}
+
+// voucherImportIDField is the optional column holding the ID of an existing
+// voucher, used to update it instead of creating a new one, and
+// voucherImportVersionField the optional column with the version the update
+// expects to find.
+const (
+	voucherImportIDField      = "id"
+	voucherImportVersionField = "version"
+)
+
+// voucherImportOptionalFields are the fields an import column can be mapped
+// onto besides voucherImportFields.
+var voucherImportOptionalFields = []string{voucherImportIDField, voucherImportVersionField}
+
+type ImportMappingService struct {
+	repo *repository.Queries
+}
+
+func NewImportMappingService(repo *repository.Queries) *ImportMappingService {
+	return &ImportMappingService{
+		repo: repo,
+	}
+}
+
+func (s *ImportMappingService) CreateProfile(ctx context.Context, req *dto.ImportMappingProfileRequest) (*dto.ImportMappingProfileResponse, error) {
+	mapping, err := encodeImportMapping(req.Mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := s.repo.CreateImportMappingProfile(ctx, repository.CreateImportMappingProfileParams{
+		Name:    req.Name,
+		Mapping: mapping,
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, apperror.Conflict("mapping_profile_name_taken", "mapping profile with name %s already exists", req.Name)
+		}
+		return nil, err
+	}
+
+	return toImportMappingProfileResponse(&profile)
+}
+
+func (s *ImportMappingService) ListProfiles(ctx context.Context, query *dto.PaginationQuery) ([]*dto.ImportMappingProfileResponse, util.Meta, error) {
+	profiles, err := s.repo.ListImportMappingProfiles(ctx, repository.ListImportMappingProfilesParams{
+		Limit:  int32(query.Limit),
+		Offset: int32((query.Page - 1) * query.Limit),
+	})
+	if err != nil {
+		return nil, util.Meta{}, err
+	}
+
+	total, err := s.repo.CountImportMappingProfiles(ctx)
+	if err != nil {
+		return nil, util.Meta{}, err
+	}
+
+	responses := []*dto.ImportMappingProfileResponse{}
+	for _, profile := range profiles {
+		response, err := toImportMappingProfileResponse(&profile)
+		if err != nil {
+			return nil, util.Meta{}, err
+		}
+		responses = append(responses, response)
+	}
+
+	return responses, util.NewMeta(total, query.Page, query.Limit), nil
+}
+
+func (s *ImportMappingService) GetProfileByID(ctx context.Context, id string) (*dto.ImportMappingProfileResponse, error) {
+	profileID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, apperror.InvalidID("invalid_mapping_profile_id", "invalid mapping profile id")
+	}
+
+	profile, err := s.repo.GetImportMappingProfileByID(ctx, pgtype.UUID{Bytes: profileID, Valid: true})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, apperror.NotFound("mapping_profile_not_found", "mapping profile not found")
+		}
+		return nil, err
+	}
+
+	return toImportMappingProfileResponse(&profile)
+}
+
+func (s *ImportMappingService) UpdateProfile(ctx context.Context, id string, req *dto.ImportMappingProfileRequest) (*dto.ImportMappingProfileResponse, error) {
+	profileID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, apperror.InvalidID("invalid_mapping_profile_id", "invalid mapping profile id")
+	}
+
+	mapping, err := encodeImportMapping(req.Mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := s.repo.UpdateImportMappingProfile(ctx, repository.UpdateImportMappingProfileParams{
+		ID:      pgtype.UUID{Bytes: profileID, Valid: true},
+		Name:    req.Name,
+		Mapping: mapping,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, apperror.NotFound("mapping_profile_not_found", "mapping profile not found")
+		}
+		if isUniqueViolation(err) {
+			return nil, apperror.Conflict("mapping_profile_name_taken", "mapping profile with name %s already exists", req.Name)
+		}
+		return nil, err
+	}
+
+	return toImportMappingProfileResponse(&profile)
+}
+
+func (s *ImportMappingService) DeleteProfile(ctx context.Context, id string) error {
+	profileID, err := uuid.Parse(id)
+	if err != nil {
+		return apperror.InvalidID("invalid_mapping_profile_id", "invalid mapping profile id")
+	}
+
+	uuidPg := pgtype.UUID{Bytes: profileID, Valid: true}
+
+	_, err = s.repo.GetImportMappingProfileByID(ctx, uuidPg)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return apperror.NotFound("mapping_profile_not_found", "mapping profile not found")
+		}
+		return err
+	}
+
+	return s.repo.DeleteImportMappingProfile(ctx, uuidPg)
+}
+
+// encodeImportMapping checks that every source header maps onto a known
+// voucher field, at most once, and serializes the mapping for storage.
+func encodeImportMapping(mapping map[string]string) ([]byte, error) {
+	mappedFrom := make(map[string]string)
+
+	for source, field := range mapping {
+		if normalizeHeader(source) == "" {
+			return nil, dto.FieldRuleError("invalid_mapping", "mapping", "empty_key", "", i18n.MsgEmptyKey, "mapping source header cannot be empty")
+		}
+
+		field = strings.TrimSpace(strings.ToLower(field))
+		if !isVoucherImportField(field) {
+			fields := slices.Concat(voucherImportFields, voucherImportOptionalFields)
+			return nil, dto.FieldRuleError("invalid_mapping", "mapping."+source, "oneof", strings.Join(fields, " "), i18n.MsgOneOf, "unknown voucher field '%s' for header '%s', use one of: %s", field, source, strings.Join(fields, ", "))
+		}
+
+		if other, ok := mappedFrom[field]; ok {
+			return nil, dto.FieldRuleError("invalid_mapping", "mapping."+source, "unique", field, i18n.MsgMappedTwice, "headers '%s' and '%s' are both mapped to '%s'", other, source, field)
+		}
+		mappedFrom[field] = source
+		mapping[source] = field
+	}
+
+	return json.Marshal(mapping)
+}
+
+func isVoucherImportField(field string) bool {
+	return slices.Contains(voucherImportFields, field) || slices.Contains(voucherImportOptionalFields, field)
+}
+
+func toImportMappingProfileResponse(profile *repository.ImportMappingProfile) (*dto.ImportMappingProfileResponse, error) {
+	var mapping map[string]string
+	if err := json.Unmarshal(profile.Mapping, &mapping); err != nil {
+		return nil, fmt.Errorf("failed to decode mapping profile: %w", err)
+	}
+
+	return &dto.ImportMappingProfileResponse{
+		ID:        profile.ID,
+		Name:      profile.Name,
+		Mapping:   mapping,
+		CreatedAt: profile.CreatedAt.Time,
+		UpdatedAt: profile.UpdatedAt.Time,
+	}, nil
+}
+
+// loadImportMapping fetches a saved mapping profile by ID or by name.
+func loadImportMapping(ctx context.Context, repo *repository.Queries, ref string) (map[string]string, error) {
+	var profile repository.ImportMappingProfile
+	var err error
+
+	if profileID, parseErr := uuid.Parse(ref); parseErr == nil {
+		profile, err = repo.GetImportMappingProfileByID(ctx, pgtype.UUID{Bytes: profileID, Valid: true})
+	} else {
+		profile, err = repo.GetImportMappingProfileByName(ctx, ref)
+	}
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, dto.FieldRuleError("mapping_profile_not_found", "mapping_profile", "exists", "", i18n.MsgNotFound, "mapping profile '%s' not found", ref)
+		}
+		return nil, err
+	}
+
+	var mapping map[string]string
+	if err := json.Unmarshal(profile.Mapping, &mapping); err != nil {
+		return nil, fmt.Errorf("failed to decode mapping profile: %w", err)
+	}
+
+	return mapping, nil
+}
+
+// resolveImportColumns finds the column index of every voucher field in the
+// header row. Headers named in the mapping take priority; fields without a
+// matching mapped header fall back to their canonical header name. The id
+// column is optional and only resolved when present. Other columns, such as
+// created_at and updated_at in an exported file, are ignored.
+func resolveImportColumns(headers []string, mapping map[string]string) (map[string]int, error) {
+	headerIndex := make(map[string]int)
+	for i, header := range headers {
+		headerIndex[normalizeHeader(header)] = i
+	}
+
+	mappedFrom := make(map[string]string)
+	columns := make(map[string]int)
+	for source, field := range mapping {
+		mappedFrom[field] = source
+		if i, ok := headerIndex[normalizeHeader(source)]; ok {
+			columns[field] = i
+		}
+	}
+
+	for _, field := range voucherImportFields {
+		if _, ok := columns[field]; ok {
+			continue
+		}
+
+		i, ok := headerIndex[field]
+		if !ok {
+			if source, mapped := mappedFrom[field]; mapped {
+				return nil, fmt.Errorf("header '%s' (mapped to '%s') not found in the csv header", source, field)
+			}
+			return nil, fmt.Errorf("header '%s' not found in the csv header", field)
+		}
+		columns[field] = i
+	}
+
+	for _, field := range voucherImportOptionalFields {
+		if _, ok := columns[field]; ok {
+			continue
+		}
+		if i, ok := headerIndex[field]; ok {
+			columns[field] = i
+		}
+	}
+
+	return columns, nil
+}
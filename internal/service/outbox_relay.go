@@ -0,0 +1,309 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/alifdwt/techtest-indico-be/internal/config"
+	"github.com/alifdwt/techtest-indico-be/internal/dto"
+	"github.com/alifdwt/techtest-indico-be/internal/repository"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// outboxBatchSize is the number of events claimed, and published in
+	// parallel, at a time. They all belong to different vouchers.
+	outboxBatchSize = 100
+	// outboxMaxRetryDelay caps the backoff of failing events; later events
+	// of the same voucher wait while they are retried.
+	outboxMaxRetryDelay = time.Minute
+	// outboxCleanupInterval is how often published events past the
+	// retention are deleted.
+	outboxCleanupInterval = time.Hour
+	// expirySweepBatchSize is the number of expired vouchers recorded per
+	// transaction.
+	expirySweepBatchSize = 100
+)
+
+// outboxEventStore is the part of the outbox the relay publishes from.
+type outboxEventStore interface {
+	ClaimOutboxEvents(ctx context.Context, arg repository.ClaimOutboxEventsParams) ([]repository.Outbox, error)
+	DeadLetterOutboxEvent(ctx context.Context, arg repository.DeadLetterOutboxEventParams) error
+	FailOutboxEvent(ctx context.Context, arg repository.FailOutboxEventParams) error
+	MarkOutboxEventPublished(ctx context.Context, id int64) error
+}
+
+// OutboxRelay publishes the events recorded in the outbox to the event
+// sinks, at least once: an event is marked as published only after every
+// sink took it, and is retried with exponential backoff when one fails,
+// possibly reaching the other sinks again. Events of a voucher are
+// published in the order they were recorded. The relay gives up on an
+// event after the configured number of attempts, or at once when its
+// payload cannot be read, so that it no longer holds back the events after
+// it.
+//
+// The relay also records a voucher.expired event for every voucher whose
+// expiry date passes.
+type OutboxRelay struct {
+	pool      *pgxpool.Pool
+	repo      *repository.Queries
+	events    outboxEventStore
+	cfg       config.OutboxConfig
+	sinks     map[string]VoucherEventPublisher
+	sinkNames []string
+
+	// stop ends the relay loop; runCtx is cancelled when shutdown gives up
+	// waiting for events being published.
+	stop        chan struct{}
+	runCtx      context.Context
+	cancelRuns  context.CancelFunc
+	running     sync.WaitGroup
+	lastCleanup time.Time
+}
+
+// NewOutboxRelay creates the relay for the given sinks, keyed by name.
+func NewOutboxRelay(pool *pgxpool.Pool, repo *repository.Queries, cfg config.OutboxConfig, sinks map[string]VoucherEventPublisher) *OutboxRelay {
+	if cfg.MaxAttempts < 1 {
+		cfg.MaxAttempts = 1
+	}
+
+	runCtx, cancelRuns := context.WithCancel(context.Background())
+
+	sinkNames := make([]string, 0, len(sinks))
+	for name := range sinks {
+		sinkNames = append(sinkNames, name)
+	}
+	slices.Sort(sinkNames)
+
+	return &OutboxRelay{
+		pool:       pool,
+		repo:       repo,
+		events:     repo,
+		cfg:        cfg,
+		sinks:      sinks,
+		sinkNames:  sinkNames,
+		stop:       make(chan struct{}),
+		runCtx:     runCtx,
+		cancelRuns: cancelRuns,
+	}
+}
+
+// Start runs the relay in the background: every poll interval it records
+// newly expired vouchers and publishes the events that are due.
+func (r *OutboxRelay) Start() {
+	r.running.Add(1)
+	go func() {
+		defer r.running.Done()
+
+		ticker := time.NewTicker(r.cfg.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			r.recordExpiredVouchers(r.runCtx)
+			r.relayDue(r.runCtx)
+			r.deletePublished(r.runCtx)
+
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops the relay and waits for the events being published. When ctx
+// expires first they are cancelled; as they were never marked as
+// published, they are published again once their lease runs out.
+func (r *OutboxRelay) Stop(ctx context.Context) error {
+	close(r.stop)
+
+	done := make(chan struct{})
+	go func() {
+		r.running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		r.cancelRuns()
+		return ctx.Err()
+	}
+}
+
+// recordExpiredVouchers records a voucher.expired event for each voucher
+// that expired since the last sweep. A voucher is marked as reported in
+// the same transaction that records its event, so none is lost or
+// reported twice.
+func (r *OutboxRelay) recordExpiredVouchers(ctx context.Context) {
+	for {
+		count, err := r.recordExpiredBatch(ctx)
+		if err != nil {
+			log.Printf("outbox: failed to record expired vouchers: %v", err)
+			return
+		}
+		if count < expirySweepBatchSize {
+			return
+		}
+	}
+}
+
+func (r *OutboxRelay) recordExpiredBatch(ctx context.Context) (int, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	repo := r.repo.WithTx(tx)
+
+	vouchers, err := repo.ClaimExpiredVouchers(ctx, expirySweepBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, voucher := range vouchers {
+		if err := recordVoucherEvent(ctx, repo, EventVoucherExpired, &voucher); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(vouchers), tx.Commit(ctx)
+}
+
+// relayDue publishes the events that are due, a batch at a time, until none
+// is left or the relay stops. Only the oldest unpublished event of each
+// voucher is claimed, so the next one is published in a later batch.
+func (r *OutboxRelay) relayDue(ctx context.Context) {
+	// Long enough for every sink to time out before other instances may
+	// claim the events again.
+	lease := r.cfg.PublishTimeout*time.Duration(len(r.sinks)) + time.Minute
+
+	for {
+		events, err := r.events.ClaimOutboxEvents(ctx, repository.ClaimOutboxEventsParams{
+			Lease:      pgtype.Interval{Microseconds: lease.Microseconds(), Valid: true},
+			MaxResults: outboxBatchSize,
+		})
+		if err != nil {
+			log.Printf("outbox: failed to claim events: %v", err)
+			return
+		}
+		if len(events) == 0 {
+			return
+		}
+
+		var publishing sync.WaitGroup
+		for _, event := range events {
+			publishing.Add(1)
+			go func() {
+				defer publishing.Done()
+				r.publish(ctx, &event)
+			}()
+		}
+		publishing.Wait()
+
+		select {
+		case <-r.stop:
+			return
+		default:
+		}
+	}
+}
+
+// publish hands an event to every sink and marks it as published, or as
+// due for a retry when a sink failed. It gives up on the event when the
+// payload cannot be read or the last attempt failed.
+func (r *OutboxRelay) publish(ctx context.Context, row *repository.Outbox) {
+	var event dto.VoucherEvent
+	if err := json.Unmarshal(row.Payload, &event); err != nil {
+		r.deadLetter(ctx, row, fmt.Errorf("invalid payload: %w", err))
+		return
+	}
+
+	if err := r.publishToSinks(ctx, &event); err != nil {
+		attempt := int(row.Attempts) + 1
+		if attempt >= r.cfg.MaxAttempts {
+			r.deadLetter(ctx, row, err)
+			return
+		}
+		log.Printf("outbox: failed to publish %s event %d (attempt %d): %v", row.EventType, row.ID, attempt, err)
+
+		nextAttemptAt := time.Now().Add(retryDelay(r.cfg.RetryBaseDelay, attempt, outboxMaxRetryDelay))
+		err = r.events.FailOutboxEvent(ctx, repository.FailOutboxEventParams{
+			ID:            row.ID,
+			NextAttemptAt: pgtype.Timestamptz{Time: nextAttemptAt, Valid: true},
+			LastError:     err.Error(),
+		})
+		if err != nil {
+			log.Printf("outbox: failed to record attempt of event %d: %v", row.ID, err)
+		}
+		return
+	}
+
+	if err := r.events.MarkOutboxEventPublished(ctx, row.ID); err != nil {
+		// It is published again when its lease runs out.
+		log.Printf("outbox: failed to mark event %d as published: %v", row.ID, err)
+	}
+}
+
+// deadLetter gives up on an event, which stays in the outbox with its
+// error.
+func (r *OutboxRelay) deadLetter(ctx context.Context, row *repository.Outbox, cause error) {
+	log.Printf("outbox: giving up on %s event %d after %d attempts: %v", row.EventType, row.ID, row.Attempts+1, cause)
+
+	err := r.events.DeadLetterOutboxEvent(ctx, repository.DeadLetterOutboxEventParams{
+		ID:        row.ID,
+		LastError: cause.Error(),
+	})
+	if err != nil {
+		// It is tried again when its lease runs out.
+		log.Printf("outbox: failed to record giving up on event %d: %v", row.ID, err)
+	}
+}
+
+func (r *OutboxRelay) publishToSinks(ctx context.Context, event *dto.VoucherEvent) error {
+	for _, name := range r.sinkNames {
+		sinkCtx, cancel := context.WithTimeout(ctx, r.cfg.PublishTimeout)
+		err := r.sinks[name].PublishVoucherEvent(sinkCtx, event)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("%s sink: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// deletePublished removes the events published longer than the retention
+// ago, at most once per cleanup interval.
+func (r *OutboxRelay) deletePublished(ctx context.Context) {
+	if time.Since(r.lastCleanup) < outboxCleanupInterval {
+		return
+	}
+	r.lastCleanup = time.Now()
+
+	retention := pgtype.Interval{Microseconds: r.cfg.Retention.Microseconds(), Valid: true}
+	if _, err := r.repo.DeletePublishedOutboxEvents(ctx, retention); err != nil {
+		log.Printf("outbox: failed to delete published events: %v", err)
+	}
+}
+
+// retryDelay is the wait before retrying after the given failed attempt:
+// the base delay, doubled for every attempt after the first, at most
+// maxDelay.
+func retryDelay(base time.Duration, attempt int, maxDelay time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempt && delay < maxDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, maxDelay)
+}
@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alifdwt/techtest-indico-be/internal/config"
+	"github.com/alifdwt/techtest-indico-be/internal/dto"
+	"github.com/alifdwt/techtest-indico-be/internal/repository"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		name    string
+		base    time.Duration
+		attempt int
+		max     time.Duration
+		want    time.Duration
+	}{
+		{"first attempt", time.Second, 1, time.Minute, time.Second},
+		{"second attempt", time.Second, 2, time.Minute, 2 * time.Second},
+		{"fifth attempt", time.Second, 5, time.Minute, 16 * time.Second},
+		{"capped", time.Second, 7, time.Minute, time.Minute},
+		{"many attempts", time.Second, 1000, time.Minute, time.Minute},
+		{"base above max", 2 * time.Minute, 1, time.Minute, time.Minute},
+		{"zero attempt", time.Second, 0, time.Minute, time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryDelay(tt.base, tt.attempt, tt.max); got != tt.want {
+				t.Errorf("retryDelay(%v, %d, %v) = %v, want %v", tt.base, tt.attempt, tt.max, got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeOutbox keeps outbox events in memory and claims them like
+// ClaimOutboxEvents: the oldest unpublished and unfailed event of each
+// aggregate. It ignores next attempt times, so a failed event is due again
+// at once.
+type fakeOutbox struct {
+	mu     sync.Mutex
+	events []repository.Outbox
+}
+
+func (o *fakeOutbox) record(t *testing.T, aggregate byte, eventID string) {
+	t.Helper()
+
+	payload, err := json.Marshal(&dto.VoucherEvent{
+		ID:   eventID,
+		Type: EventVoucherUpdated,
+		Data: &dto.VoucherResponse{ID: pgtype.UUID{Bytes: [16]byte{aggregate}, Valid: true}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	o.events = append(o.events, repository.Outbox{
+		ID:            int64(len(o.events) + 1),
+		AggregateType: outboxAggregateVoucher,
+		AggregateID:   pgtype.UUID{Bytes: [16]byte{aggregate}, Valid: true},
+		EventType:     EventVoucherUpdated,
+		Payload:       payload,
+	})
+}
+
+func (o *fakeOutbox) ClaimOutboxEvents(_ context.Context, arg repository.ClaimOutboxEventsParams) ([]repository.Outbox, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var claimed []repository.Outbox
+	seen := map[pgtype.UUID]bool{}
+	for _, event := range o.events {
+		if event.PublishedAt.Valid || event.FailedAt.Valid || seen[event.AggregateID] {
+			continue
+		}
+		seen[event.AggregateID] = true
+		if len(claimed) < int(arg.MaxResults) {
+			claimed = append(claimed, event)
+		}
+	}
+
+	return claimed, nil
+}
+
+func (o *fakeOutbox) DeadLetterOutboxEvent(_ context.Context, arg repository.DeadLetterOutboxEventParams) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	event := &o.events[arg.ID-1]
+	event.Attempts++
+	event.LastError = arg.LastError
+	event.FailedAt = pgtype.Timestamp{Time: time.Now(), Valid: true}
+	return nil
+}
+
+func (o *fakeOutbox) FailOutboxEvent(_ context.Context, arg repository.FailOutboxEventParams) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	event := &o.events[arg.ID-1]
+	event.Attempts++
+	event.LastError = arg.LastError
+	return nil
+}
+
+func (o *fakeOutbox) MarkOutboxEventPublished(_ context.Context, id int64) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.events[id-1].PublishedAt = pgtype.Timestamp{Time: time.Now(), Valid: true}
+	return nil
+}
+
+// fakeSink records the events it takes per voucher, and refuses the first
+// attempts at the events in failures.
+type fakeSink struct {
+	mu       sync.Mutex
+	failures map[string]int
+	received map[[16]byte][]string
+}
+
+func (s *fakeSink) PublishVoucherEvent(_ context.Context, event *dto.VoucherEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failures[event.ID] > 0 {
+		s.failures[event.ID]--
+		return errors.New("sink unavailable")
+	}
+
+	if s.received == nil {
+		s.received = map[[16]byte][]string{}
+	}
+	s.received[event.Data.ID.Bytes] = append(s.received[event.Data.ID.Bytes], event.ID)
+	return nil
+}
+
+func TestOutboxRelayPublishesInOrderAndRetries(t *testing.T) {
+	outbox := &fakeOutbox{}
+	outbox.record(t, 1, "a1")
+	outbox.record(t, 2, "b1")
+	outbox.record(t, 1, "a2")
+	outbox.record(t, 2, "b2")
+	outbox.record(t, 1, "a3")
+
+	// The log sink runs first and takes a1 again on every retry.
+	logSink := &fakeSink{}
+	webhookSink := &fakeSink{failures: map[string]int{"a1": 2, "b2": 1}}
+
+	relay := &OutboxRelay{
+		events:    outbox,
+		cfg:       config.OutboxConfig{RetryBaseDelay: time.Second, MaxAttempts: 5, PublishTimeout: time.Second},
+		sinks:     map[string]VoucherEventPublisher{"log": logSink, "webhook": webhookSink},
+		sinkNames: []string{"log", "webhook"},
+		stop:      make(chan struct{}),
+	}
+	relay.relayDue(context.Background())
+
+	for _, event := range outbox.events {
+		if !event.PublishedAt.Valid {
+			t.Errorf("event %d was not published", event.ID)
+		}
+	}
+	if got := outbox.events[0].Attempts; got != 2 {
+		t.Errorf("event a1 failed %d times, want 2", got)
+	}
+
+	want := map[[16]byte][]string{
+		{1}: {"a1", "a2", "a3"},
+		{2}: {"b1", "b2"},
+	}
+	if !equalReceived(webhookSink.received, want) {
+		t.Errorf("webhook sink received %v, want %v", webhookSink.received, want)
+	}
+
+	wantLog := map[[16]byte][]string{
+		{1}: {"a1", "a1", "a1", "a2", "a3"},
+		{2}: {"b1", "b2", "b2"},
+	}
+	if !equalReceived(logSink.received, wantLog) {
+		t.Errorf("log sink received %v, want %v", logSink.received, wantLog)
+	}
+}
+
+func TestOutboxRelayGivesUpOnEvents(t *testing.T) {
+	outbox := &fakeOutbox{}
+	outbox.record(t, 1, "a1")
+	outbox.record(t, 1, "a2")
+	outbox.record(t, 2, "b1")
+	outbox.record(t, 2, "b2")
+	outbox.events[2].Payload = []byte(`{"id":`)
+
+	sink := &fakeSink{failures: map[string]int{"a1": 100}}
+
+	relay := &OutboxRelay{
+		events:    outbox,
+		cfg:       config.OutboxConfig{RetryBaseDelay: time.Second, MaxAttempts: 3, PublishTimeout: time.Second},
+		sinks:     map[string]VoucherEventPublisher{"webhook": sink},
+		sinkNames: []string{"webhook"},
+		stop:      make(chan struct{}),
+	}
+	relay.relayDue(context.Background())
+
+	tests := []struct {
+		event     string
+		attempts  int32
+		published bool
+		failed    bool
+	}{
+		{"a1", 3, false, true},
+		{"a2", 0, true, false},
+		{"b1", 1, false, true},
+		{"b2", 0, true, false},
+	}
+	for i, tt := range tests {
+		event := outbox.events[i]
+		if event.Attempts != tt.attempts || event.PublishedAt.Valid != tt.published || event.FailedAt.Valid != tt.failed {
+			t.Errorf("event %s: attempts %d, published %v, failed %v; want %d, %v, %v",
+				tt.event, event.Attempts, event.PublishedAt.Valid, event.FailedAt.Valid, tt.attempts, tt.published, tt.failed)
+		}
+	}
+
+	want := map[[16]byte][]string{
+		{1}: {"a2"},
+		{2}: {"b2"},
+	}
+	if !equalReceived(sink.received, want) {
+		t.Errorf("sink received %v, want %v", sink.received, want)
+	}
+}
+
+func equalReceived(got, want map[[16]byte][]string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for voucher, events := range want {
+		if !slices.Equal(got[voucher], events) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,727 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alifdwt/techtest-indico-be/internal/apperror"
+	"github.com/alifdwt/techtest-indico-be/internal/config"
+	"github.com/alifdwt/techtest-indico-be/internal/dto"
+	"github.com/alifdwt/techtest-indico-be/internal/i18n"
+	"github.com/alifdwt/techtest-indico-be/internal/repository"
+	"github.com/alifdwt/techtest-indico-be/internal/storage"
+	"github.com/alifdwt/techtest-indico-be/internal/util"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	exportRunSucceeded = "succeeded"
+	exportRunFailed    = "failed"
+
+	exportTriggerSchedule = "schedule"
+	exportTriggerManual   = "manual"
+
+	// exportRunLease is how long a run stays owned by the instance running
+	// it without a heartbeat, which renews it every exportRunHeartbeat.
+	exportRunLease     = 2 * time.Minute
+	exportRunHeartbeat = 30 * time.Second
+)
+
+// ScheduledExportService manages scheduled export definitions and runs them
+// with an in-process cron scheduler. Every run is recorded with its outcome,
+// so failures can be inspected through the API.
+type ScheduledExportService struct {
+	repo           *repository.Queries
+	voucherService *VoucherService
+	storages       map[string]storage.Storage
+	location       *time.Location
+	cfg            config.ExportConfig
+	scheduler      *cron.Cron
+
+	mu      sync.Mutex
+	entries map[pgtype.UUID]scheduledEntry
+
+	// stop ends the reload loop; runCtx is cancelled when shutdown gives up
+	// waiting for running exports.
+	stop       chan struct{}
+	runCtx     context.Context
+	cancelRuns context.CancelFunc
+	runs       sync.WaitGroup
+}
+
+// scheduledEntry is an export registered with the scheduler, as of the
+// definition last updated at updatedAt.
+type scheduledEntry struct {
+	id        cron.EntryID
+	updatedAt time.Time
+}
+
+func NewScheduledExportService(repo *repository.Queries, voucherService *VoucherService, storages map[string]storage.Storage, location *time.Location, cfg config.ExportConfig) *ScheduledExportService {
+	runCtx, cancelRuns := context.WithCancel(context.Background())
+
+	return &ScheduledExportService{
+		repo:           repo,
+		voucherService: voucherService,
+		storages:       storages,
+		location:       location,
+		cfg:            cfg,
+		// A run that is still going when its next turn comes is skipped
+		// rather than started twice.
+		scheduler: cron.New(
+			cron.WithLocation(location),
+			cron.WithChain(cron.SkipIfStillRunning(cron.PrintfLogger(log.Default()))),
+		),
+		entries:    make(map[pgtype.UUID]scheduledEntry),
+		stop:       make(chan struct{}),
+		runCtx:     runCtx,
+		cancelRuns: cancelRuns,
+	}
+}
+
+// Start schedules every enabled export and starts the scheduler. The
+// definitions are then reloaded every reload interval, so changes made
+// through other instances are picked up.
+func (s *ScheduledExportService) Start(ctx context.Context) error {
+	if err := s.reload(ctx); err != nil {
+		return err
+	}
+
+	s.scheduler.Start()
+
+	s.runs.Add(1)
+	go func() {
+		defer s.runs.Done()
+
+		ticker := time.NewTicker(s.cfg.ReloadInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				if err := s.reload(s.runCtx); err != nil {
+					log.Printf("scheduled exports: failed to reload definitions: %v", err)
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Stop stops scheduling new runs and waits for running exports to finish.
+// When ctx expires first, running exports are cancelled.
+func (s *ScheduledExportService) Stop(ctx context.Context) error {
+	close(s.stop)
+	stopped := s.scheduler.Stop()
+
+	done := make(chan struct{})
+	go func() {
+		<-stopped.Done()
+		s.runs.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.cancelRuns()
+		return ctx.Err()
+	}
+}
+
+func (s *ScheduledExportService) CreateExport(ctx context.Context, req *dto.ScheduledExportRequest) (*dto.ScheduledExportResponse, error) {
+	params, err := s.scheduledExportParams(req)
+	if err != nil {
+		return nil, err
+	}
+
+	export, err := s.repo.CreateScheduledExport(ctx, repository.CreateScheduledExportParams{
+		Name:           params.Name,
+		CronExpression: params.CronExpression,
+		Format:         params.Format,
+		Profile:        params.Profile,
+		Filter:         params.Filter,
+		Sort:           params.Sort,
+		SortBy:         params.SortBy,
+		SortOrder:      params.SortOrder,
+		Columns:        params.Columns,
+		Storage:        params.Storage,
+		PathPrefix:     params.PathPrefix,
+		Enabled:        params.Enabled,
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, apperror.Conflict("scheduled_export_name_taken", "scheduled export with name %s already exists", req.Name)
+		}
+		return nil, err
+	}
+
+	if err := s.schedule(&export); err != nil {
+		return nil, err
+	}
+
+	return s.toScheduledExportResponse(&export), nil
+}
+
+func (s *ScheduledExportService) ListExports(ctx context.Context, query *dto.PaginationQuery) ([]*dto.ScheduledExportResponse, util.Meta, error) {
+	exports, err := s.repo.ListScheduledExports(ctx, repository.ListScheduledExportsParams{
+		Limit:  int32(query.Limit),
+		Offset: int32((query.Page - 1) * query.Limit),
+	})
+	if err != nil {
+		return nil, util.Meta{}, err
+	}
+
+	total, err := s.repo.CountScheduledExports(ctx)
+	if err != nil {
+		return nil, util.Meta{}, err
+	}
+
+	responses := []*dto.ScheduledExportResponse{}
+	for _, export := range exports {
+		responses = append(responses, s.toScheduledExportResponse(&export))
+	}
+
+	return responses, util.NewMeta(total, query.Page, query.Limit), nil
+}
+
+func (s *ScheduledExportService) GetExportByID(ctx context.Context, id string) (*dto.ScheduledExportResponse, error) {
+	export, err := s.getExport(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toScheduledExportResponse(export), nil
+}
+
+func (s *ScheduledExportService) UpdateExport(ctx context.Context, id string, req *dto.ScheduledExportRequest) (*dto.ScheduledExportResponse, error) {
+	exportID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, apperror.InvalidID("invalid_scheduled_export_id", "invalid scheduled export id")
+	}
+
+	params, err := s.scheduledExportParams(req)
+	if err != nil {
+		return nil, err
+	}
+	params.ID = pgtype.UUID{Bytes: exportID, Valid: true}
+
+	export, err := s.repo.UpdateScheduledExport(ctx, *params)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, apperror.NotFound("scheduled_export_not_found", "scheduled export not found")
+		}
+		if isUniqueViolation(err) {
+			return nil, apperror.Conflict("scheduled_export_name_taken", "scheduled export with name %s already exists", req.Name)
+		}
+		return nil, err
+	}
+
+	if err := s.schedule(&export); err != nil {
+		return nil, err
+	}
+
+	return s.toScheduledExportResponse(&export), nil
+}
+
+func (s *ScheduledExportService) DeleteExport(ctx context.Context, id string) error {
+	export, err := s.getExport(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteScheduledExport(ctx, export.ID); err != nil {
+		return err
+	}
+
+	s.unschedule(export.ID)
+	return nil
+}
+
+// RunExport starts a run of the export right away, outside its schedule.
+// The run happens in the background; its outcome shows up in the run
+// history.
+func (s *ScheduledExportService) RunExport(ctx context.Context, id string) (*dto.ScheduledExportRunResponse, error) {
+	export, err := s.getExport(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	run, err := s.repo.CreateScheduledExportRun(ctx, repository.CreateScheduledExportRunParams{
+		ScheduledExportID: export.ID,
+		TriggeredBy:       exportTriggerManual,
+		Lease:             exportRunLeaseInterval(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.runs.Add(1)
+	go func() {
+		defer s.runs.Done()
+		s.execute(s.runCtx, export, &run)
+	}()
+
+	return toScheduledExportRunResponse(&run), nil
+}
+
+func (s *ScheduledExportService) ListRuns(ctx context.Context, id string, query *dto.PaginationQuery) ([]*dto.ScheduledExportRunResponse, util.Meta, error) {
+	export, err := s.getExport(ctx, id)
+	if err != nil {
+		return nil, util.Meta{}, err
+	}
+
+	runs, err := s.repo.ListScheduledExportRuns(ctx, repository.ListScheduledExportRunsParams{
+		ScheduledExportID: export.ID,
+		Limit:             int32(query.Limit),
+		Offset:            int32((query.Page - 1) * query.Limit),
+	})
+	if err != nil {
+		return nil, util.Meta{}, err
+	}
+
+	total, err := s.repo.CountScheduledExportRuns(ctx, export.ID)
+	if err != nil {
+		return nil, util.Meta{}, err
+	}
+
+	responses := []*dto.ScheduledExportRunResponse{}
+	for _, run := range runs {
+		responses = append(responses, toScheduledExportRunResponse(&run))
+	}
+
+	return responses, util.NewMeta(total, query.Page, query.Limit), nil
+}
+
+func (s *ScheduledExportService) getExport(ctx context.Context, id string) (*repository.ScheduledExport, error) {
+	exportID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, apperror.InvalidID("invalid_scheduled_export_id", "invalid scheduled export id")
+	}
+
+	export, err := s.repo.GetScheduledExportByID(ctx, pgtype.UUID{Bytes: exportID, Valid: true})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, apperror.NotFound("scheduled_export_not_found", "scheduled export not found")
+		}
+		return nil, err
+	}
+
+	return &export, nil
+}
+
+// scheduledExportParams validates a definition and fills in its defaults.
+func (s *ScheduledExportService) scheduledExportParams(req *dto.ScheduledExportRequest) (*repository.UpdateScheduledExportParams, error) {
+	if _, err := cron.ParseStandard(req.CronExpression); err != nil {
+		return nil, dto.FieldRuleError("invalid_cron_expression", "cron_expression", "cron", "", i18n.MsgCronExpression, "invalid cron_expression: %s", err.Error())
+	}
+
+	if _, err := selectExportColumns(req.Columns); err != nil {
+		return nil, err
+	}
+
+	var filter dto.VoucherFilterQuery
+	if req.Filter != nil {
+		filter = *req.Filter
+	}
+
+	// Checked the way GET /vouchers/export checks them, so that runs do not
+	// fail on them later.
+	_, err := s.voucherService.exportFilter(&dto.VoucherExportQuery{
+		VoucherFilterQuery: filter,
+		Sort:               req.Sort,
+		SortBy:             req.SortBy,
+		SortOrder:          req.SortOrder,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	filterJSON, err := json.Marshal(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	params := &repository.UpdateScheduledExportParams{
+		Name:           strings.TrimSpace(req.Name),
+		CronExpression: strings.TrimSpace(req.CronExpression),
+		Format:         req.Format,
+		Profile:        req.Profile,
+		Filter:         filterJSON,
+		Sort:           req.Sort,
+		SortBy:         req.SortBy,
+		SortOrder:      req.SortOrder,
+		Columns:        req.Columns,
+		Storage:        req.Storage,
+		Enabled:        req.Enabled == nil || *req.Enabled,
+	}
+	if params.Format == "" {
+		params.Format = "csv"
+	}
+	if params.Profile == "" {
+		params.Profile = exportProfileDisplay
+	}
+	if params.Storage == "" {
+		params.Storage = storage.BackendLocal
+	}
+	if _, ok := s.storages[params.Storage]; !ok {
+		configured := slices.Sorted(maps.Keys(s.storages))
+		return nil, dto.FieldRuleError("storage_not_configured", "storage", "oneof", strings.Join(configured, " "), i18n.MsgOneOf, "storage backend '%s' is not configured", params.Storage)
+	}
+
+	prefix := strings.Trim(req.PathPrefix, "/")
+	if prefix != "" {
+		prefix = path.Clean(prefix)
+		if prefix == ".." || strings.HasPrefix(prefix, "../") {
+			return nil, dto.FieldRuleError("invalid_path_prefix", "path_prefix", "inside_storage", "", i18n.MsgInsideStorage, "path_prefix must stay inside the storage backend")
+		}
+	}
+	params.PathPrefix = prefix
+
+	return params, nil
+}
+
+// reload fails the runs whose instance stopped before finishing them, and
+// brings the scheduler in line with the stored definitions: exports that
+// are new or were updated since they were scheduled are (re)scheduled, and
+// those deleted or disabled are removed.
+func (s *ScheduledExportService) reload(ctx context.Context) error {
+	err := s.repo.FailExpiredScheduledExportRuns(ctx, "Interrupted: the server running the export stopped before it finished.")
+	if err != nil {
+		return err
+	}
+
+	exports, err := s.repo.ListEnabledScheduledExports(ctx)
+	if err != nil {
+		return err
+	}
+
+	enabled := make(map[pgtype.UUID]bool, len(exports))
+	for _, export := range exports {
+		enabled[export.ID] = true
+
+		s.mu.Lock()
+		entry, ok := s.entries[export.ID]
+		s.mu.Unlock()
+		if ok && entry.updatedAt.Equal(export.UpdatedAt.Time) {
+			continue
+		}
+
+		if err := s.schedule(&export); err != nil {
+			log.Printf("scheduled export %s: %v", export.Name, err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, entry := range s.entries {
+		if !enabled[id] {
+			s.scheduler.Remove(entry.id)
+			delete(s.entries, id)
+		}
+	}
+
+	return nil
+}
+
+// schedule (re)registers the export with the scheduler, or removes it when
+// the export is disabled.
+func (s *ScheduledExportService) schedule(export *repository.ScheduledExport) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.unscheduleLocked(export.ID)
+	if !export.Enabled {
+		return nil
+	}
+
+	exportID := export.ID
+	entryID, err := s.scheduler.AddFunc(export.CronExpression, func() {
+		s.runs.Add(1)
+		defer s.runs.Done()
+		s.runScheduled(exportID)
+	})
+	if err != nil {
+		return fmt.Errorf("invalid cron_expression: %w", err)
+	}
+
+	s.entries[export.ID] = scheduledEntry{id: entryID, updatedAt: export.UpdatedAt.Time}
+	return nil
+}
+
+func (s *ScheduledExportService) unschedule(id pgtype.UUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.unscheduleLocked(id)
+}
+
+func (s *ScheduledExportService) unscheduleLocked(id pgtype.UUID) {
+	if entry, ok := s.entries[id]; ok {
+		s.scheduler.Remove(entry.id)
+		delete(s.entries, id)
+	}
+}
+
+func (s *ScheduledExportService) nextRunAt(id pgtype.UUID) *time.Time {
+	s.mu.Lock()
+	entry, ok := s.entries[id]
+	s.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	next := s.scheduler.Entry(entry.id).Next
+	if next.IsZero() {
+		return nil
+	}
+
+	return &next
+}
+
+// runScheduled reloads the definition, so a run always uses its latest
+// filters, and executes it. Every instance fires the schedule, but only the
+// one that records the run for the slot executes it.
+func (s *ScheduledExportService) runScheduled(id pgtype.UUID) {
+	// Schedules have minute resolution, so the minute the job fired in
+	// identifies the slot on every instance.
+	slot := time.Now().Truncate(time.Minute)
+
+	export, err := s.repo.GetScheduledExportByID(s.runCtx, id)
+	if err != nil {
+		log.Printf("scheduled export %s: failed to load definition: %v", id.String(), err)
+		return
+	}
+	if !export.Enabled {
+		return
+	}
+
+	run, err := s.repo.CreateScheduledExportRun(s.runCtx, repository.CreateScheduledExportRunParams{
+		ScheduledExportID: export.ID,
+		TriggeredBy:       exportTriggerSchedule,
+		ScheduledFor:      pgtype.Timestamptz{Time: slot, Valid: true},
+		Lease:             exportRunLeaseInterval(),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Another instance runs it.
+		return
+	}
+	if err != nil {
+		log.Printf("scheduled export %s: failed to record run: %v", export.Name, err)
+		return
+	}
+
+	s.execute(s.runCtx, &export, &run)
+}
+
+// execute writes the export to its storage backend and records the outcome
+// of the run. The run's lease is renewed meanwhile, so that it is not taken
+// for one whose instance went away.
+func (s *ScheduledExportService) execute(ctx context.Context, export *repository.ScheduledExport, run *repository.ScheduledExportRun) {
+	params := repository.FinishScheduledExportRunParams{
+		ID:     run.ID,
+		Status: exportRunSucceeded,
+	}
+
+	heartbeatCtx, stopHeartbeat := context.WithCancel(ctx)
+	go s.heartbeat(heartbeatCtx, run.ID)
+
+	location, size, err := s.writeExport(ctx, export, time.Now())
+	stopHeartbeat()
+	params.Location = location
+	params.SizeBytes = size
+	if err != nil {
+		params.Status = exportRunFailed
+		params.Error = err.Error()
+		log.Printf("scheduled export %s failed: %v", export.Name, err)
+	}
+
+	// The run must be recorded even when the export was cancelled.
+	if _, err := s.repo.FinishScheduledExportRun(context.WithoutCancel(ctx), params); err != nil {
+		log.Printf("scheduled export %s: failed to record run outcome: %v", export.Name, err)
+	}
+}
+
+// heartbeat renews the lease of a run every exportRunHeartbeat until ctx
+// is done.
+func (s *ScheduledExportService) heartbeat(ctx context.Context, runID pgtype.UUID) {
+	ticker := time.NewTicker(exportRunHeartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := s.repo.RenewScheduledExportRunLease(ctx, repository.RenewScheduledExportRunLeaseParams{
+				Lease: exportRunLeaseInterval(),
+				ID:    runID,
+			})
+			if err != nil && ctx.Err() == nil {
+				log.Printf("scheduled export run %s: failed to renew lease: %v", runID.String(), err)
+			}
+		}
+	}
+}
+
+func exportRunLeaseInterval() pgtype.Interval {
+	return pgtype.Interval{Microseconds: exportRunLease.Microseconds(), Valid: true}
+}
+
+// writeExport streams the export straight into the storage backend through
+// a pipe, so the file is never held in memory as a whole.
+func (s *ScheduledExportService) writeExport(ctx context.Context, export *repository.ScheduledExport, startedAt time.Time) (string, int64, error) {
+	store, ok := s.storages[export.Storage]
+	if !ok {
+		return "", 0, fmt.Errorf("storage backend '%s' is not configured", export.Storage)
+	}
+
+	filter, err := decodeExportFilter(export.Filter)
+	if err != nil {
+		return "", 0, err
+	}
+
+	query := &dto.VoucherExportQuery{
+		VoucherFilterQuery: filter,
+		Sort:               export.Sort,
+		SortBy:             export.SortBy,
+		SortOrder:          export.SortOrder,
+		Format:             export.Format,
+		Profile:            export.Profile,
+		Columns:            export.Columns,
+	}
+
+	reader, writer := io.Pipe()
+	counter := &countingWriter{w: writer}
+	exportErr := make(chan error, 1)
+	go func() {
+		err := s.voucherService.Export(ctx, query, counter)
+		writer.CloseWithError(err)
+		exportErr <- err
+	}()
+
+	location, err := store.Put(ctx, s.objectKey(export, startedAt), exportContentTypes[export.Format], reader)
+	// Unblock the export if the upload stopped reading early.
+	reader.CloseWithError(err)
+	if writeErr := <-exportErr; writeErr != nil {
+		return "", counter.n, writeErr
+	}
+	if err != nil {
+		return "", counter.n, err
+	}
+
+	return location, counter.n, nil
+}
+
+func decodeExportFilter(data []byte) (dto.VoucherFilterQuery, error) {
+	var filter dto.VoucherFilterQuery
+	if err := json.Unmarshal(data, &filter); err != nil {
+		return filter, fmt.Errorf("invalid filter: %w", err)
+	}
+
+	return filter, nil
+}
+
+// objectKey names a run's file after the export and its start time, e.g.
+// finance/nightly-snapshot/20250101-000000.csv.
+func (s *ScheduledExportService) objectKey(export *repository.ScheduledExport, startedAt time.Time) string {
+	fileName := startedAt.In(s.location).Format("20060102-150405") + "." + export.Format
+
+	return path.Join(export.PathPrefix, slugify(export.Name), fileName)
+}
+
+func slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+
+	slug := strings.TrimSuffix(b.String(), "-")
+	if slug == "" {
+		return "export"
+	}
+
+	return slug
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func (s *ScheduledExportService) toScheduledExportResponse(export *repository.ScheduledExport) *dto.ScheduledExportResponse {
+	// The filter was encoded from a validated request.
+	filter, _ := decodeExportFilter(export.Filter)
+
+	return &dto.ScheduledExportResponse{
+		ID:             export.ID,
+		Name:           export.Name,
+		CronExpression: export.CronExpression,
+		Format:         export.Format,
+		Profile:        export.Profile,
+		Filter:         filter,
+		Sort:           export.Sort,
+		SortBy:         export.SortBy,
+		SortOrder:      export.SortOrder,
+		Columns:        export.Columns,
+		Storage:        export.Storage,
+		PathPrefix:     export.PathPrefix,
+		Enabled:        export.Enabled,
+		NextRunAt:      s.nextRunAt(export.ID),
+		CreatedAt:      export.CreatedAt.Time,
+		UpdatedAt:      export.UpdatedAt.Time,
+	}
+}
+
+func toScheduledExportRunResponse(run *repository.ScheduledExportRun) *dto.ScheduledExportRunResponse {
+	response := &dto.ScheduledExportRunResponse{
+		ID:                run.ID,
+		ScheduledExportID: run.ScheduledExportID,
+		TriggeredBy:       run.TriggeredBy,
+		Status:            run.Status,
+		Location:          run.Location,
+		SizeBytes:         run.SizeBytes,
+		Error:             run.Error,
+		StartedAt:         run.StartedAt.Time,
+	}
+	if run.ScheduledFor.Valid {
+		response.ScheduledFor = &run.ScheduledFor.Time
+	}
+	if run.FinishedAt.Valid {
+		response.FinishedAt = &run.FinishedAt.Time
+	}
+
+	return response
+}
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/alifdwt/techtest-indico-be/internal/dto"
+	"github.com/alifdwt/techtest-indico-be/internal/i18n"
+	"github.com/alifdwt/techtest-indico-be/internal/repository"
+	"github.com/alifdwt/techtest-indico-be/internal/repository/voucherfilter"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// bulkMaxAffected is the most vouchers one bulk operation may touch, and
+// the default of its max_affected guard.
+const bulkMaxAffected = 1000
+
+// Bulk result statuses.
+const (
+	bulkUpdated  = "updated"
+	bulkDeleted  = "deleted"
+	bulkSkipped  = "skipped"
+	bulkNotFound = "not_found"
+	bulkConflict = "conflict"
+)
+
+// BulkVouchers runs one operation over the vouchers chosen by ID or by
+// filter, in a single transaction: when anything fails, no voucher is
+// changed. A voucher changed or deleted by someone else after it was
+// loaded is not an error; it is left alone and reported as a conflict,
+// while the others are changed. A dry run does the same work and rolls it
+// back, so its report shows exactly what the real run would do.
+func (s *VoucherService) BulkVouchers(ctx context.Context, req *dto.BulkVoucherRequest) (*dto.BulkVoucherResponse, error) {
+	if err := validateBulkRequest(req); err != nil {
+		return nil, err
+	}
+
+	maxAffected := req.MaxAffected
+	if maxAffected == 0 {
+		maxAffected = bulkMaxAffected
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	repo := s.repo.WithTx(tx)
+
+	vouchers, results, err := s.bulkTargets(ctx, repo, s.filters.WithTx(tx), req, maxAffected)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.BulkVoucherResponse{
+		Operation: req.Operation,
+		DryRun:    req.DryRun,
+		Matched:   len(vouchers),
+	}
+
+	now := time.Now()
+	for _, voucher := range vouchers {
+		result, updated, err := s.bulkApply(ctx, repo, req, &voucher, now)
+		if err != nil {
+			return nil, err
+		}
+		switch result.Status {
+		case bulkUpdated:
+			res.Affected++
+			err = recordVoucherEvent(ctx, repo, EventVoucherUpdated, updated)
+		case bulkDeleted:
+			res.Affected++
+			err = recordVoucherEvent(ctx, repo, EventVoucherDeleted, &voucher)
+		}
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	res.Results = results
+
+	if req.DryRun {
+		return res, nil
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func validateBulkRequest(req *dto.BulkVoucherRequest) error {
+	if (len(req.IDs) > 0) == (req.Filter != nil) {
+		return dto.FieldRuleError("invalid_bulk_target", "ids", "exactly_one_of", "ids filter", i18n.MsgExactlyOneOf, "exactly one of ids or filter is required")
+	}
+
+	switch req.Operation {
+	case "extend_expiry":
+		if req.ExtendByDays == nil {
+			return dto.FieldRuleError("invalid_bulk_operation", "extend_by_days", "required", "", i18n.MsgRequired, "extend_by_days is required for extend_expiry")
+		}
+	case "set_discount":
+		if req.DiscountPercent == nil {
+			return dto.FieldRuleError("invalid_bulk_operation", "discount_percent", "required", "", i18n.MsgRequired, "discount_percent is required for set_discount")
+		}
+	}
+
+	return nil
+}
+
+// bulkTargets loads the vouchers a bulk operation applies to, refusing to
+// go on when there are more than maxAffected. IDs that match no voucher
+// are reported as not_found results.
+func (s *VoucherService) bulkTargets(ctx context.Context, repo *repository.Queries, filters *voucherfilter.Queries, req *dto.BulkVoucherRequest, maxAffected int) ([]repository.Voucher, []dto.BulkVoucherResult, error) {
+	results := []dto.BulkVoucherResult{}
+
+	if req.Filter != nil {
+		filter, err := s.voucherFilter(req.Filter, "", "created_at", "asc")
+		if err != nil {
+			return nil, nil, err
+		}
+
+		total, err := filters.CountVouchers(ctx, filter)
+		if err != nil {
+			return nil, nil, err
+		}
+		if total > int64(maxAffected) {
+			return nil, nil, bulkLimitExceeded(total, maxAffected)
+		}
+
+		vouchers, err := filters.ListVouchers(ctx, filter, int32(total), 0)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return vouchers, results, nil
+	}
+
+	ids := make([]pgtype.UUID, 0, len(req.IDs))
+	seen := make(map[uuid.UUID]bool, len(req.IDs))
+	for i, id := range req.IDs {
+		voucherID, err := uuid.Parse(id)
+		if err != nil {
+			return nil, nil, dto.FieldRuleError("invalid_voucher_id", fmt.Sprintf("ids[%d]", i), "uuid", "", i18n.MsgUUID, "invalid voucher id %s", id)
+		}
+		if !seen[voucherID] {
+			seen[voucherID] = true
+			ids = append(ids, pgtype.UUID{Bytes: voucherID, Valid: true})
+		}
+	}
+
+	vouchers, err := repo.ListVouchersByIDs(ctx, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(vouchers) > maxAffected {
+		return nil, nil, bulkLimitExceeded(int64(len(vouchers)), maxAffected)
+	}
+
+	found := make(map[uuid.UUID]bool, len(vouchers))
+	for _, voucher := range vouchers {
+		found[voucher.ID.Bytes] = true
+	}
+	for _, id := range ids {
+		if !found[id.Bytes] {
+			results = append(results, dto.BulkVoucherResult{
+				ID:      uuid.UUID(id.Bytes).String(),
+				Status:  bulkNotFound,
+				Message: "voucher not found",
+			})
+		}
+	}
+
+	return vouchers, results, nil
+}
+
+// bulkApply applies the bulk operation to one voucher. It also returns the
+// voucher as updated, when it was. Every write expects the version the
+// voucher was loaded with, so a concurrent edit is never overwritten; the
+// voucher is reported as a conflict instead.
+func (s *VoucherService) bulkApply(ctx context.Context, repo *repository.Queries, req *dto.BulkVoucherRequest, voucher *repository.Voucher, now time.Time) (dto.BulkVoucherResult, *repository.Voucher, error) {
+	result := dto.BulkVoucherResult{ID: uuid.UUID(voucher.ID.Bytes).String()}
+	expectedVersion := pgtype.Int4{Int32: voucher.Version, Valid: true}
+
+	switch req.Operation {
+	case "delete":
+		deleted, err := repo.DeleteVoucher(ctx, repository.DeleteVoucherParams{
+			ID:              voucher.ID,
+			ExpectedVersion: expectedVersion,
+		})
+		if err != nil {
+			return result, nil, err
+		}
+		if deleted == 0 {
+			return bulkConflictResult(result), nil, nil
+		}
+
+		result.Status = bulkDeleted
+		return result, nil, nil
+	case "extend_expiry":
+		// Computed by the database from the stored date.
+		updated, err := repo.ExtendVoucherExpiry(ctx, repository.ExtendVoucherExpiryParams{
+			Days:            int32(*req.ExtendByDays),
+			ID:              voucher.ID,
+			ExpectedVersion: expectedVersion,
+		})
+		return bulkUpdatedResult(result, &updated, err)
+	}
+
+	obj := repository.PatchVoucherParams{ID: voucher.ID, ExpectedVersion: expectedVersion}
+	switch req.Operation {
+	case "set_discount":
+		discountPercent := int32(*req.DiscountPercent)
+		if voucher.DiscountPercent == discountPercent {
+			result.Status, result.Message = bulkSkipped, fmt.Sprintf("discount is already %d", discountPercent)
+			return result, nil, nil
+		}
+		obj.DiscountPercent = pgtype.Int4{Int32: discountPercent, Valid: true}
+	case "expire":
+		if !voucher.ExpiryDate.Time.After(now) {
+			result.Status, result.Message = bulkSkipped, "voucher has already expired"
+			return result, nil, nil
+		}
+		obj.ExpiryDate = pgtype.Timestamptz{Time: now, Valid: true}
+	}
+
+	updated, err := repo.PatchVoucher(ctx, obj)
+	return bulkUpdatedResult(result, &updated, err)
+}
+
+// bulkUpdatedResult reports the outcome of updating a voucher, where no
+// row means it changed or was deleted since it was loaded.
+func bulkUpdatedResult(result dto.BulkVoucherResult, updated *repository.Voucher, err error) (dto.BulkVoucherResult, *repository.Voucher, error) {
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return bulkConflictResult(result), nil, nil
+		}
+		return result, nil, err
+	}
+
+	result.Status = bulkUpdated
+	result.Voucher = toVoucherResponse(updated)
+	return result, updated, nil
+}
+
+func bulkConflictResult(result dto.BulkVoucherResult) dto.BulkVoucherResult {
+	result.Status = bulkConflict
+	result.Message = "voucher was changed or deleted while the operation ran; run it again"
+	return result
+}
+
+func bulkLimitExceeded(matched int64, maxAffected int) error {
+	return dto.FieldRuleError("bulk_limit_exceeded", "max_affected", "max", strconv.Itoa(maxAffected), i18n.MsgBulkLimit, "operation would affect %d vouchers, more than the limit of %d; narrow the selection or raise max_affected", matched, maxAffected)
+}
@@ -0,0 +1,311 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alifdwt/techtest-indico-be/internal/apperror"
+	"github.com/alifdwt/techtest-indico-be/internal/dto"
+	"github.com/alifdwt/techtest-indico-be/internal/repository"
+	"github.com/alifdwt/techtest-indico-be/internal/repository/voucherfilter"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// fakeVoucherTx answers the queries of a bulk operation from a fixed set
+// of vouchers. Vouchers in changed were modified by someone else after
+// being loaded, so writes expecting their version find no row.
+type fakeVoucherTx struct {
+	pgx.Tx
+
+	vouchers []repository.Voucher
+	changed  map[pgtype.UUID]bool
+
+	writes     int
+	events     int
+	committed  bool
+	rolledBack bool
+}
+
+func (tx *fakeVoucherTx) Commit(context.Context) error {
+	tx.committed = true
+	return nil
+}
+
+func (tx *fakeVoucherTx) Rollback(context.Context) error {
+	if !tx.committed {
+		tx.rolledBack = true
+	}
+	return nil
+}
+
+func (tx *fakeVoucherTx) Exec(_ context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	switch {
+	case strings.HasPrefix(sql, "-- name: CreateOutboxEvent"):
+		tx.events++
+		return pgconn.NewCommandTag("INSERT 0 1"), nil
+	case strings.HasPrefix(sql, "-- name: DeleteVoucher"):
+		if tx.changed[args[0].(pgtype.UUID)] {
+			return pgconn.NewCommandTag("DELETE 0"), nil
+		}
+		tx.writes++
+		return pgconn.NewCommandTag("DELETE 1"), nil
+	}
+
+	return pgconn.CommandTag{}, errors.New("unexpected exec: " + sql)
+}
+
+func (tx *fakeVoucherTx) Query(_ context.Context, sql string, args ...any) (pgx.Rows, error) {
+	if !strings.HasPrefix(sql, "-- name: ListVouchersByIDs") {
+		return nil, errors.New("unexpected query: " + sql)
+	}
+
+	rows := &fakeRows{}
+	for _, id := range args[0].([]pgtype.UUID) {
+		if voucher, ok := tx.find(id); ok {
+			rows.values = append(rows.values, voucherValues(voucher))
+		}
+	}
+
+	return rows, nil
+}
+
+func (tx *fakeVoucherTx) QueryRow(_ context.Context, sql string, args ...any) pgx.Row {
+	switch {
+	case strings.HasPrefix(sql, "SELECT COUNT(*) FROM vouchers"):
+		return &fakeRows{values: [][]any{{int64(len(tx.vouchers))}}}
+	case strings.HasPrefix(sql, "-- name: PatchVoucher"):
+		id := args[3].(pgtype.UUID)
+		voucher, ok := tx.find(id)
+		if !ok || tx.changed[id] {
+			return &fakeRows{}
+		}
+		if discount := args[1].(pgtype.Int4); discount.Valid {
+			voucher.DiscountPercent = discount.Int32
+		}
+		if expiry := args[2].(pgtype.Timestamptz); expiry.Valid {
+			voucher.ExpiryDate = expiry
+		}
+		voucher.Version++
+		tx.writes++
+		return &fakeRows{values: [][]any{voucherValues(voucher)}}
+	}
+
+	return &fakeRows{err: errors.New("unexpected query: " + sql)}
+}
+
+func (tx *fakeVoucherTx) find(id pgtype.UUID) (repository.Voucher, bool) {
+	for _, voucher := range tx.vouchers {
+		if voucher.ID == id {
+			return voucher, true
+		}
+	}
+
+	return repository.Voucher{}, false
+}
+
+// voucherValues lists the columns of a voucher in the order they are
+// selected and scanned.
+func voucherValues(voucher repository.Voucher) []any {
+	v := reflect.ValueOf(voucher)
+	values := make([]any, v.NumField())
+	for i := range values {
+		values[i] = v.Field(i).Interface()
+	}
+
+	return values
+}
+
+// fakeRows serves rows of column values as pgx.Rows and, for its first
+// row, as pgx.Row.
+type fakeRows struct {
+	pgx.Rows
+
+	values  [][]any
+	current int
+	err     error
+}
+
+func (r *fakeRows) Next() bool {
+	if r.current >= len(r.values) {
+		return false
+	}
+	r.current++
+	return true
+}
+
+func (r *fakeRows) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	if r.current == 0 && !r.Next() {
+		return pgx.ErrNoRows
+	}
+
+	for i, value := range r.values[r.current-1] {
+		reflect.ValueOf(dest[i]).Elem().Set(reflect.ValueOf(value))
+	}
+	return nil
+}
+
+func (r *fakeRows) Err() error { return r.err }
+
+func (r *fakeRows) Close() {}
+
+type fakeTxBeginner struct {
+	tx pgx.Tx
+}
+
+func (b fakeTxBeginner) Begin(context.Context) (pgx.Tx, error) {
+	return b.tx, nil
+}
+
+func newBulkTestService(tx *fakeVoucherTx) *VoucherService {
+	return &VoucherService{
+		pool:    fakeTxBeginner{tx},
+		repo:    repository.New(nil),
+		filters: voucherfilter.New(nil),
+	}
+}
+
+func bulkTestVouchers(n int) []repository.Voucher {
+	vouchers := make([]repository.Voucher, n)
+	for i := range vouchers {
+		vouchers[i] = repository.Voucher{
+			ID:              pgtype.UUID{Bytes: [16]byte{byte(i + 1)}, Valid: true},
+			VoucherCode:     "CODE" + string(rune('A'+i)),
+			DiscountPercent: 10,
+			ExpiryDate:      pgtype.Timestamptz{Time: time.Now().Add(24 * time.Hour), Valid: true},
+			Version:         1,
+		}
+	}
+
+	return vouchers
+}
+
+func voucherIDs(vouchers []repository.Voucher) []string {
+	ids := make([]string, len(vouchers))
+	for i, voucher := range vouchers {
+		ids[i] = uuid.UUID(voucher.ID.Bytes).String()
+	}
+
+	return ids
+}
+
+func TestBulkVouchersDryRunRollsBack(t *testing.T) {
+	discount := 25.0
+	tx := &fakeVoucherTx{vouchers: bulkTestVouchers(2)}
+
+	res, err := newBulkTestService(tx).BulkVouchers(context.Background(), &dto.BulkVoucherRequest{
+		Operation:       "set_discount",
+		IDs:             voucherIDs(tx.vouchers),
+		DiscountPercent: &discount,
+		DryRun:          true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !res.DryRun || res.Matched != 2 || res.Affected != 2 {
+		t.Errorf("response = %+v, want a dry run with 2 matched and 2 affected", res)
+	}
+	for _, result := range res.Results {
+		if result.Status != bulkUpdated || result.Voucher == nil || result.Voucher.DiscountPercent != 25 {
+			t.Errorf("result = %+v, want updated to 25%%", result)
+		}
+	}
+	if tx.committed || !tx.rolledBack {
+		t.Errorf("committed %v, rolled back %v; want the dry run rolled back", tx.committed, tx.rolledBack)
+	}
+	if tx.events != 2 {
+		t.Errorf("recorded %d events, want 2 (rolled back with the rest)", tx.events)
+	}
+}
+
+func TestBulkVouchersMaxAffected(t *testing.T) {
+	tests := []struct {
+		name string
+		req  dto.BulkVoucherRequest
+	}{
+		{"ids", dto.BulkVoucherRequest{Operation: "delete", MaxAffected: 2}},
+		{"filter", dto.BulkVoucherRequest{Operation: "delete", MaxAffected: 2, Filter: &dto.VoucherFilterQuery{}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := &fakeVoucherTx{vouchers: bulkTestVouchers(3)}
+			req := tt.req
+			if req.Filter == nil {
+				req.IDs = voucherIDs(tx.vouchers)
+			}
+
+			_, err := newBulkTestService(tx).BulkVouchers(context.Background(), &req)
+
+			var appErr *apperror.Error
+			if !errors.As(err, &appErr) || appErr.Code != "bulk_limit_exceeded" {
+				t.Fatalf("BulkVouchers() = %v, want bulk_limit_exceeded", err)
+			}
+			if len(appErr.Fields) != 1 || appErr.Fields[0].Field != "max_affected" || appErr.Fields[0].Param != "2" {
+				t.Errorf("fields = %+v, want max_affected with param 2", appErr.Fields)
+			}
+			if tx.writes != 0 || tx.committed {
+				t.Errorf("writes %d, committed %v; want nothing written", tx.writes, tx.committed)
+			}
+		})
+	}
+}
+
+func TestBulkVouchersReportsConflicts(t *testing.T) {
+	tests := []struct {
+		operation string
+		status    string
+	}{
+		{"delete", bulkDeleted},
+		{"expire", bulkUpdated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.operation, func(t *testing.T) {
+			vouchers := bulkTestVouchers(3)
+			tx := &fakeVoucherTx{
+				vouchers: vouchers,
+				changed:  map[pgtype.UUID]bool{vouchers[1].ID: true},
+			}
+			ids := append(voucherIDs(vouchers), uuid.NewString())
+
+			res, err := newBulkTestService(tx).BulkVouchers(context.Background(), &dto.BulkVoucherRequest{
+				Operation: tt.operation,
+				IDs:       ids,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			statuses := map[string]string{}
+			for _, result := range res.Results {
+				statuses[result.ID] = result.Status
+			}
+			want := map[string]string{
+				ids[0]: tt.status,
+				ids[1]: bulkConflict,
+				ids[2]: tt.status,
+				ids[3]: bulkNotFound,
+			}
+			if !reflect.DeepEqual(statuses, want) {
+				t.Errorf("statuses = %v, want %v", statuses, want)
+			}
+			if res.Matched != 3 || res.Affected != 2 {
+				t.Errorf("matched %d, affected %d; want 3 and 2", res.Matched, res.Affected)
+			}
+			if !tx.committed || tx.writes != 2 || tx.events != 2 {
+				t.Errorf("committed %v, writes %d, events %d; want the other 2 vouchers committed", tx.committed, tx.writes, tx.events)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/alifdwt/techtest-indico-be/internal/repository"
+	"github.com/alifdwt/techtest-indico-be/internal/repository/voucherfilter"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// voucherCursor is the decoded form of an opaque list cursor: the sort it
+// was issued for and the sort values and ID of the row it points at.
+type voucherCursor struct {
+	Sort     []string          `json:"s"`
+	Values   []json.RawMessage `json:"v"`
+	ID       string            `json:"id"`
+	Backward bool              `json:"b,omitempty"`
+}
+
+// sortSignature identifies an ordering, so a cursor cannot be reused with a
+// different sort where its position would be meaningless.
+func sortSignature(keys []voucherfilter.SortKey) []string {
+	signature := make([]string, len(keys))
+	for i, key := range keys {
+		direction := "asc"
+		if key.Desc {
+			direction = "desc"
+		}
+		signature[i] = key.Column + ":" + direction
+	}
+
+	return signature
+}
+
+// encodeVoucherCursor returns a cursor pointing at the voucher. A backward
+// cursor pages to the rows before it.
+func encodeVoucherCursor(filter voucherfilter.Filter, voucher *repository.Voucher, backward bool) (string, error) {
+	cursor := voucherCursor{
+		Sort:     sortSignature(filter.SortKeys()),
+		ID:       voucher.ID.String(),
+		Backward: backward,
+	}
+
+	for _, value := range filter.SortValues(*voucher) {
+		var raw any
+		switch v := value.(type) {
+		case pgtype.Timestamptz:
+			raw = v.Time.UTC().Format(time.RFC3339Nano)
+		case pgtype.Timestamp:
+			raw = v.Time.UTC().Format(time.RFC3339Nano)
+		default:
+			raw = v
+		}
+
+		encoded, err := json.Marshal(raw)
+		if err != nil {
+			return "", err
+		}
+		cursor.Values = append(cursor.Values, encoded)
+	}
+
+	payload, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(payload), nil
+}
+
+// decodeVoucherCursor turns a cursor back into a keyset for the filter,
+// checking that it was issued for the same sort.
+func decodeVoucherCursor(filter voucherfilter.Filter, encoded string) (*voucherfilter.Keyset, error) {
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, invalidCursor("invalid cursor")
+	}
+
+	var cursor voucherCursor
+	if err := json.Unmarshal(payload, &cursor); err != nil {
+		return nil, invalidCursor("invalid cursor")
+	}
+
+	keys := filter.SortKeys()
+	signature := sortSignature(keys)
+	if len(cursor.Sort) != len(signature) || len(cursor.Values) != len(keys) {
+		return nil, invalidCursor("invalid cursor: it was issued for a different sort order")
+	}
+	for i := range signature {
+		if cursor.Sort[i] != signature[i] {
+			return nil, invalidCursor("invalid cursor: it was issued for a different sort order")
+		}
+	}
+
+	id, err := uuid.Parse(cursor.ID)
+	if err != nil {
+		return nil, invalidCursor("invalid cursor")
+	}
+
+	keyset := &voucherfilter.Keyset{
+		ID:       pgtype.UUID{Bytes: id, Valid: true},
+		Backward: cursor.Backward,
+	}
+	for i, key := range keys {
+		value, err := decodeSortValue(key.Column, cursor.Values[i])
+		if err != nil {
+			return nil, invalidCursor("invalid cursor")
+		}
+		keyset.Values = append(keyset.Values, value)
+	}
+
+	return keyset, nil
+}
+
+// decodeSortValue restores a sort value with the type of its column.
+func decodeSortValue(column string, raw json.RawMessage) (any, error) {
+	switch column {
+	case "voucher_code":
+		var value string
+		err := json.Unmarshal(raw, &value)
+		return value, err
+	case "discount_percent":
+		var value int32
+		err := json.Unmarshal(raw, &value)
+		return value, err
+	case "expiry_date", "created_at", "updated_at":
+		var text string
+		if err := json.Unmarshal(raw, &text); err != nil {
+			return nil, err
+		}
+		value, err := time.Parse(time.RFC3339Nano, text)
+		if err != nil {
+			return nil, err
+		}
+		if column == "expiry_date" {
+			return pgtype.Timestamptz{Time: value, Valid: true}, nil
+		}
+		return pgtype.Timestamp{Time: value, Valid: true}, nil
+	default:
+		return nil, fmt.Errorf("unknown sort column %s", column)
+	}
+}
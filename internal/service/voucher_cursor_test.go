@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/alifdwt/techtest-indico-be/internal/apperror"
+	"github.com/alifdwt/techtest-indico-be/internal/dto"
+	"github.com/alifdwt/techtest-indico-be/internal/repository"
+	"github.com/alifdwt/techtest-indico-be/internal/repository/voucherfilter"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func cursorTestVoucher() repository.Voucher {
+	return repository.Voucher{
+		ID:              pgtype.UUID{Bytes: [16]byte{1, 2, 3}, Valid: true},
+		VoucherCode:     "SAVE10",
+		DiscountPercent: 10,
+		ExpiryDate:      pgtype.Timestamptz{Time: time.Date(2030, 1, 31, 23, 59, 59, 0, time.UTC), Valid: true},
+		CreatedAt:       pgtype.Timestamp{Time: time.Date(2025, 6, 1, 8, 30, 0, 123456000, time.UTC), Valid: true},
+		UpdatedAt:       pgtype.Timestamp{Time: time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC), Valid: true},
+		Version:         1,
+	}
+}
+
+func sortFilter(t *testing.T, spec string) voucherfilter.Filter {
+	t.Helper()
+
+	keys, err := voucherfilter.ParseSort(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return voucherfilter.Filter{Sort: keys}
+}
+
+func TestVoucherCursorRoundTrip(t *testing.T) {
+	voucher := cursorTestVoucher()
+
+	tests := []struct {
+		name     string
+		sort     string
+		backward bool
+	}{
+		{"code", "voucher_code", false},
+		{"discount descending", "-discount_percent", false},
+		{"expiry backward", "expiry_date", true},
+		{"created and updated", "created_at,-updated_at", false},
+		{"every column", "-discount_percent,expiry_date,voucher_code,created_at,updated_at", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := sortFilter(t, tt.sort)
+
+			cursor, err := encodeVoucherCursor(filter, &voucher, tt.backward)
+			if err != nil {
+				t.Fatal(err)
+			}
+			keyset, err := decodeVoucherCursor(filter, cursor)
+			if err != nil {
+				t.Fatalf("decodeVoucherCursor() = %v", err)
+			}
+
+			if keyset.ID != voucher.ID || keyset.Backward != tt.backward {
+				t.Errorf("keyset ID %v, backward %v; want %v, %v", keyset.ID, keyset.Backward, voucher.ID, tt.backward)
+			}
+			if want := filter.SortValues(voucher); !reflect.DeepEqual(keyset.Values, want) {
+				t.Errorf("keyset values = %#v, want %#v", keyset.Values, want)
+			}
+		})
+	}
+}
+
+func TestDecodeVoucherCursorRejects(t *testing.T) {
+	voucher := cursorTestVoucher()
+	issued, err := encodeVoucherCursor(sortFilter(t, "voucher_code"), &voucher, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encode := func(payload string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(payload))
+	}
+	const id = "01020300-0000-0000-0000-000000000000"
+
+	tests := []struct {
+		name    string
+		sort    string
+		cursor  string
+		message string
+	}{
+		{"other direction", "-voucher_code", issued, "invalid cursor: it was issued for a different sort order"},
+		{"other column", "discount_percent", issued, "invalid cursor: it was issued for a different sort order"},
+		{"extra column", "voucher_code,created_at", issued, "invalid cursor: it was issued for a different sort order"},
+		{"not base64", "voucher_code", "not base64!", "invalid cursor"},
+		{"padded base64", "voucher_code", base64.URLEncoding.EncodeToString([]byte(`{"s":["voucher_code:asc"]}`)), "invalid cursor"},
+		{"truncated", "voucher_code", issued[:len(issued)-5], "invalid cursor"},
+		{"not json", "voucher_code", encode("voucher_code:asc"), "invalid cursor"},
+		{"bad id", "voucher_code", encode(`{"s":["voucher_code:asc"],"v":["SAVE10"],"id":"nope"}`), "invalid cursor"},
+		{"value count", "voucher_code", encode(`{"s":["voucher_code:asc"],"v":[],"id":"` + id + `"}`), "invalid cursor: it was issued for a different sort order"},
+		{"wrong value type", "discount_percent", encode(`{"s":["discount_percent:asc"],"v":["ten"],"id":"` + id + `"}`), "invalid cursor"},
+		{"bad time", "created_at", encode(`{"s":["created_at:asc"],"v":["yesterday"],"id":"` + id + `"}`), "invalid cursor"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeVoucherCursor(sortFilter(t, tt.sort), tt.cursor)
+
+			var appErr *apperror.Error
+			if !errors.As(err, &appErr) || appErr.Code != "invalid_cursor" {
+				t.Fatalf("decodeVoucherCursor() = %v, want invalid_cursor", err)
+			}
+			if appErr.Message != tt.message {
+				t.Errorf("message = %q, want %q", appErr.Message, tt.message)
+			}
+		})
+	}
+}
+
+// fakeListDB returns rows as the filtered list query would, limit and
+// keyset already applied, and total as their count.
+type fakeListDB struct {
+	repository.DBTX
+
+	rows  []repository.Voucher
+	total int64
+}
+
+func (db *fakeListDB) Query(context.Context, string, ...any) (pgx.Rows, error) {
+	rows := &fakeRows{}
+	for _, voucher := range db.rows {
+		rows.values = append(rows.values, voucherValues(voucher))
+	}
+
+	return rows, nil
+}
+
+func (db *fakeListDB) QueryRow(context.Context, string, ...any) pgx.Row {
+	return &fakeRows{values: [][]any{{db.total}}}
+}
+
+func TestListVouchersKeysetBoundaries(t *testing.T) {
+	vouchers := bulkTestVouchers(4)
+	a, b, c, d := vouchers[0], vouchers[1], vouchers[2], vouchers[3]
+	filter := sortFilter(t, "voucher_code")
+	cursor := func(voucher repository.Voucher, backward bool) string {
+		encoded, err := encodeVoucherCursor(filter, &voucher, backward)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return encoded
+	}
+
+	tests := []struct {
+		name   string
+		cursor string
+		// rows are returned by the query, at most limit+1 of them and in
+		// reverse order when paging backward.
+		rows []repository.Voucher
+
+		want     []repository.Voucher
+		hasNext  bool
+		hasPrev  bool
+		nextFrom *repository.Voucher
+		prevFrom *repository.Voucher
+	}{
+		{name: "first page", rows: []repository.Voucher{a, b, c}, want: []repository.Voucher{a, b}, hasNext: true, nextFrom: &b},
+		{name: "only page", rows: []repository.Voucher{a, b}, want: []repository.Voucher{a, b}},
+		{
+			name: "middle page", cursor: cursor(a, false), rows: []repository.Voucher{b, c, d},
+			want: []repository.Voucher{b, c}, hasNext: true, hasPrev: true, nextFrom: &c, prevFrom: &b,
+		},
+		{
+			name: "last page", cursor: cursor(b, false), rows: []repository.Voucher{c, d},
+			want: []repository.Voucher{c, d}, hasPrev: true, prevFrom: &c,
+		},
+		{name: "past the end", cursor: cursor(d, false), want: nil, hasPrev: true},
+		{
+			name: "backward middle page", cursor: cursor(d, true), rows: []repository.Voucher{c, b, a},
+			want: []repository.Voucher{b, c}, hasNext: true, hasPrev: true, nextFrom: &c, prevFrom: &b,
+		},
+		{
+			name: "backward to the start", cursor: cursor(c, true), rows: []repository.Voucher{b, a},
+			want: []repository.Voucher{a, b}, hasNext: true, nextFrom: &b,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &VoucherService{filters: voucherfilter.New(&fakeListDB{rows: tt.rows, total: 4})}
+
+			res, meta, err := s.ListVouchers(context.Background(), &dto.VoucherListQuery{
+				Sort:   "voucher_code",
+				Page:   1,
+				Limit:  2,
+				Cursor: tt.cursor,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var codes, wantCodes []string
+			for _, voucher := range res {
+				codes = append(codes, voucher.VoucherCode)
+			}
+			for _, voucher := range tt.want {
+				wantCodes = append(wantCodes, voucher.VoucherCode)
+			}
+			if !slices.Equal(codes, wantCodes) {
+				t.Errorf("vouchers = %v, want %v", codes, wantCodes)
+			}
+			if meta.HasNext != tt.hasNext || meta.HasPrev != tt.hasPrev {
+				t.Errorf("has next %v, has prev %v; want %v, %v", meta.HasNext, meta.HasPrev, tt.hasNext, tt.hasPrev)
+			}
+			checkCursor(t, filter, "next", meta.NextCursor, tt.nextFrom, false)
+			checkCursor(t, filter, "prev", meta.PrevCursor, tt.prevFrom, true)
+		})
+	}
+}
+
+// checkCursor checks that a returned cursor points at the voucher in the
+// given direction, or that there is none when voucher is nil.
+func checkCursor(t *testing.T, filter voucherfilter.Filter, name, cursor string, voucher *repository.Voucher, backward bool) {
+	t.Helper()
+
+	if voucher == nil {
+		if cursor != "" {
+			t.Errorf("%s cursor = %q, want none", name, cursor)
+		}
+		return
+	}
+
+	keyset, err := decodeVoucherCursor(filter, cursor)
+	if err != nil {
+		t.Fatalf("%s cursor: %v", name, err)
+	}
+	if keyset.ID != voucher.ID || keyset.Backward != backward {
+		t.Errorf("%s cursor points at %v, backward %v; want %v, %v", name, keyset.ID, keyset.Backward, voucher.ID, backward)
+	}
+}
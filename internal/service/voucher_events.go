@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/alifdwt/techtest-indico-be/internal/dto"
+	"github.com/alifdwt/techtest-indico-be/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Voucher event types.
+const (
+	EventVoucherCreated = "voucher.created"
+	EventVoucherUpdated = "voucher.updated"
+	EventVoucherDeleted = "voucher.deleted"
+	EventVoucherExpired = "voucher.expired"
+)
+
+// outboxAggregateVoucher is the aggregate type of voucher events in the
+// outbox.
+const outboxAggregateVoucher = "voucher"
+
+// VoucherEventPublisher is a sink the outbox relay publishes voucher events
+// to. An event may be published more than once, e.g. when the relay stops
+// before recording that it was; consumers can tell by its ID.
+type VoucherEventPublisher interface {
+	PublishVoucherEvent(ctx context.Context, event *dto.VoucherEvent) error
+}
+
+func newVoucherEvent(eventType string, voucher *repository.Voucher) *dto.VoucherEvent {
+	return &dto.VoucherEvent{
+		ID:         uuid.NewString(),
+		Type:       eventType,
+		OccurredAt: time.Now().UTC(),
+		Data:       toVoucherResponse(voucher),
+	}
+}
+
+// recordVoucherEvent writes a voucher event to the outbox. repo must be the
+// transaction that makes the change, so the event is saved if and only if
+// the change is.
+func recordVoucherEvent(ctx context.Context, repo *repository.Queries, eventType string, voucher *repository.Voucher) error {
+	event := newVoucherEvent(eventType, voucher)
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return repo.CreateOutboxEvent(ctx, repository.CreateOutboxEventParams{
+		EventID:       pgtype.UUID{Bytes: uuid.MustParse(event.ID), Valid: true},
+		AggregateType: outboxAggregateVoucher,
+		AggregateID:   voucher.ID,
+		EventType:     eventType,
+		Payload:       payload,
+	})
+}
+
+// txBeginner starts transactions; it is the connection pool.
+type txBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// inTx runs fn in a transaction, which is committed when fn succeeds.
+func (s *VoucherService) inTx(ctx context.Context, fn func(repo *repository.Queries) error) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(s.repo.WithTx(tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
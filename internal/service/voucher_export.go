@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alifdwt/techtest-indico-be/internal/dto"
+	"github.com/alifdwt/techtest-indico-be/internal/i18n"
+	"github.com/alifdwt/techtest-indico-be/internal/repository"
+	"github.com/alifdwt/techtest-indico-be/internal/repository/voucherfilter"
+)
+
+// exportFlushEvery is how many rows are written between flushes of a
+// streamed export.
+const exportFlushEvery = 500
+
+const (
+	exportProfileDisplay = "display"
+	exportProfileImport  = "import"
+
+	exportTimeLayout   = "2006-01-02 15:04:05"
+	xlsxDateTimeFormat = "yyyy-mm-dd hh:mm:ss"
+)
+
+// timeFormatter renders a timestamp for a text export.
+type timeFormatter func(time.Time) string
+
+// exportColumn is a column that can be selected for CSV and XLSX exports.
+// The key doubles as the header of the import profile, which matches the
+// field names the importer expects.
+type exportColumn struct {
+	key    string
+	header string
+	width  float64
+	// format is the XLSX number format of the cells, if any.
+	format string
+	// text renders the value for CSV, cell returns a typed XLSX value.
+	text func(v *repository.Voucher, formatTime timeFormatter) string
+	cell func(v *repository.Voucher, location *time.Location) any
+}
+
+var exportColumns = []exportColumn{
+	{
+		key: "id", header: "ID", width: 38,
+		text: func(v *repository.Voucher, _ timeFormatter) string { return v.ID.String() },
+		cell: func(v *repository.Voucher, _ *time.Location) any { return v.ID.String() },
+	},
+	{
+		key: "voucher_code", header: "Voucher Code", width: 24,
+		text: func(v *repository.Voucher, _ timeFormatter) string { return v.VoucherCode },
+		cell: func(v *repository.Voucher, _ *time.Location) any { return v.VoucherCode },
+	},
+	{
+		key: "discount_percent", header: "Discount Percent", width: 18, format: `0"%"`,
+		text: func(v *repository.Voucher, _ timeFormatter) string { return strconv.Itoa(int(v.DiscountPercent)) },
+		cell: func(v *repository.Voucher, _ *time.Location) any { return int(v.DiscountPercent) },
+	},
+	{
+		key: "expiry_date", header: "Expiry Date", width: 21, format: xlsxDateTimeFormat,
+		text: func(v *repository.Voucher, formatTime timeFormatter) string { return formatTime(v.ExpiryDate.Time) },
+		cell: func(v *repository.Voucher, location *time.Location) any { return v.ExpiryDate.Time.In(location) },
+	},
+	{
+		key: "created_at", header: "Created At", width: 21, format: xlsxDateTimeFormat,
+		text: func(v *repository.Voucher, formatTime timeFormatter) string { return formatTime(v.CreatedAt.Time) },
+		cell: func(v *repository.Voucher, location *time.Location) any { return v.CreatedAt.Time.In(location) },
+	},
+	{
+		key: "updated_at", header: "Updated At", width: 21, format: xlsxDateTimeFormat,
+		text: func(v *repository.Voucher, formatTime timeFormatter) string { return formatTime(v.UpdatedAt.Time) },
+		cell: func(v *repository.Voucher, location *time.Location) any { return v.UpdatedAt.Time.In(location) },
+	},
+	{
+		key: "version", header: "Version", width: 10,
+		text: func(v *repository.Voucher, _ timeFormatter) string { return strconv.Itoa(int(v.Version)) },
+		cell: func(v *repository.Voucher, _ *time.Location) any { return int(v.Version) },
+	},
+}
+
+// exportHeader is the header of a column in the requested export profile.
+func exportHeader(column exportColumn, profile string) string {
+	if profile == exportProfileImport {
+		return column.key
+	}
+
+	return column.header
+}
+
+// exportTimeFormatter renders timestamps for a CSV export. The import
+// profile uses an accepted import date format, so the file can be uploaded
+// again without edits.
+func (s *VoucherService) exportTimeFormatter(profile string) timeFormatter {
+	if profile == exportProfileImport {
+		return s.dateParser.Format
+	}
+
+	location := s.dateParser.Location()
+	return func(t time.Time) string {
+		return t.In(location).Format(exportTimeLayout)
+	}
+}
+
+// selectExportColumns resolves a comma separated column list, keeping the
+// requested order. An empty list selects every column.
+func selectExportColumns(spec string) ([]exportColumn, error) {
+	if strings.TrimSpace(spec) == "" {
+		return exportColumns, nil
+	}
+
+	var selected []exportColumn
+	seen := make(map[string]bool)
+	for _, key := range strings.Split(spec, ",") {
+		key = strings.TrimSpace(strings.ToLower(key))
+		if key == "" || seen[key] {
+			continue
+		}
+
+		column, ok := findExportColumn(key)
+		if !ok {
+			return nil, dto.FieldRuleError("unknown_export_column", "columns", "oneof", strings.Join(exportColumnKeys(), " "), i18n.MsgOneOf, "unknown export column '%s', use any of: %s", key, strings.Join(exportColumnKeys(), ", "))
+		}
+		selected = append(selected, column)
+		seen[key] = true
+	}
+
+	if len(selected) == 0 {
+		return exportColumns, nil
+	}
+
+	return selected, nil
+}
+
+func findExportColumn(key string) (exportColumn, bool) {
+	for _, column := range exportColumns {
+		if column.key == key {
+			return column, true
+		}
+	}
+
+	return exportColumn{}, false
+}
+
+func exportColumnKeys() []string {
+	keys := make([]string, 0, len(exportColumns))
+	for _, column := range exportColumns {
+		keys = append(keys, column.key)
+	}
+
+	return keys
+}
+
+// exportFilter turns export query parameters into the list filter. Exports
+// default to the newest vouchers first.
+func (s *VoucherService) exportFilter(query *dto.VoucherExportQuery) (voucherfilter.Filter, error) {
+	sortBy, sortOrder := query.SortBy, query.SortOrder
+	if sortBy == "" && query.Sort == "" {
+		sortBy = "created_at"
+		if sortOrder == "" {
+			sortOrder = "desc"
+		}
+	}
+
+	return s.voucherFilter(&query.VoucherFilterQuery, query.Sort, sortBy, sortOrder)
+}
+
+// ExportCSV streams the vouchers matching the query to w as CSV, row by
+// row from the database, so memory use does not grow with the table.
+func (s *VoucherService) ExportCSV(ctx context.Context, query *dto.VoucherExportQuery, w io.Writer) error {
+	filter, err := s.exportFilter(query)
+	if err != nil {
+		return err
+	}
+
+	columns, err := selectExportColumns(query.Columns)
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	flusher, _ := w.(http.Flusher)
+
+	headers := make([]string, len(columns))
+	for i, column := range columns {
+		headers[i] = exportHeader(column, query.Profile)
+	}
+	if err := writer.Write(headers); err != nil {
+		return err
+	}
+
+	formatTime := s.exportTimeFormatter(query.Profile)
+	record := make([]string, len(columns))
+	written := 0
+
+	err = s.filters.StreamVouchers(ctx, filter, func(voucher repository.Voucher) error {
+		for i, column := range columns {
+			record[i] = column.text(&voucher, formatTime)
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+
+		written++
+		if written%exportFlushEvery == 0 {
+			writer.Flush()
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+
+		return writer.Error()
+	})
+	if err != nil {
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// exportContentTypes are the MIME types of the supported export formats.
+var exportContentTypes = map[string]string{
+	"csv":    "text/csv",
+	"xlsx":   "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	"ndjson": "application/x-ndjson",
+}
+
+// Export writes the vouchers matching the query to w in the query's format.
+func (s *VoucherService) Export(ctx context.Context, query *dto.VoucherExportQuery, w io.Writer) error {
+	switch query.Format {
+	case "xlsx":
+		return s.ExportXLSX(ctx, query, w)
+	case "ndjson":
+		return s.ExportNDJSON(ctx, query, w)
+	default:
+		return s.ExportCSV(ctx, query, w)
+	}
+}
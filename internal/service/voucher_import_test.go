@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alifdwt/techtest-indico-be/internal/config"
+)
+
+func TestUpdateImportedVoucherRejectsVersions(t *testing.T) {
+	const voucherID = "0b6b2c3e-1f0a-4a57-9d43-5a1c1f1c9e01"
+
+	tests := []struct {
+		name           string
+		version        string
+		requireIfMatch bool
+		want           string
+	}{
+		{"not a number", "three", false, "version must be a positive whole number."},
+		{"zero", "0", false, "version must be a positive whole number."},
+		{"negative", "-2", false, "version must be a positive whole number."},
+		{"too large", "4294967296", false, "version must be a positive whole number."},
+		{"missing when required", "", true, "version is required to update a voucher."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &VoucherService{concurrencyCfg: config.ConcurrencyConfig{RequireIfMatch: tt.requireIfMatch}}
+			run := &importRun{}
+			row := importRow{lineNumber: 2, id: voucherID, version: tt.version, voucherCode: "NEW10"}
+
+			s.updateImportedVoucher(context.Background(), run, row, 10, time.Now())
+
+			if len(run.failedRows) != 1 || run.failedRows[0].Reason != tt.want {
+				t.Fatalf("failed rows = %+v, want one with reason %q", run.failedRows, tt.want)
+			}
+			if run.successCount != 0 {
+				t.Errorf("successCount = %d, want 0", run.successCount)
+			}
+		})
+	}
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/alifdwt/techtest-indico-be/internal/dto"
+	"github.com/alifdwt/techtest-indico-be/internal/repository"
+)
+
+const (
+	importFormatJSON   = "json"
+	importFormatNDJSON = "ndjson"
+)
+
+// ImportJSON imports vouchers from a JSON array or from newline-delimited
+// JSON objects shaped like CreateVoucherRequest. Items are decoded and saved
+// one at a time, so the body is never held in memory as a whole. Rows are
+// validated exactly like CSV rows; row numbers are the item position for an
+// array and the line number for NDJSON.
+func (s *VoucherService) ImportJSON(ctx context.Context, body io.Reader, opts *dto.JSONImportOptions) (*dto.CSVUploadResponse, error) {
+	if opts == nil {
+		opts = &dto.JSONImportOptions{}
+	}
+
+	dateParser, err := s.importDateParser(opts.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := bufio.NewReader(body)
+	first, err := firstNonSpaceByte(reader)
+	if err != nil {
+		if err == io.EOF {
+			return nil, importFileError("request body is empty")
+		}
+		return nil, err
+	}
+
+	source := importSource{format: importFormatNDJSON}
+	if first == '[' {
+		source.format = importFormatJSON
+	}
+
+	// Exported objects carry id, created_at, updated_at and version; the
+	// timestamps are always ignored, and the id and version only count with
+	// match_by_id.
+	headers := voucherImportFields
+	if opts.MatchByID {
+		headers = slices.Concat([]string{voucherImportIDField, voucherImportVersionField}, voucherImportFields)
+	}
+
+	run := &importRun{}
+	firstSeen := make(map[string]int)
+	handleItem := func(lineNumber int, item map[string]any) {
+		row := importRow{
+			lineNumber:      lineNumber,
+			voucherCode:     jsonFieldValue(item["voucher_code"]),
+			discountPercent: jsonDiscountValue(item["discount_percent"]),
+			expiryDate:      jsonFieldValue(item["expiry_date"]),
+		}
+		row.record = []string{row.voucherCode, row.discountPercent, row.expiryDate}
+		if opts.MatchByID {
+			row.id = jsonFieldValue(item[voucherImportIDField])
+			row.version = jsonFieldValue(item[voucherImportVersionField])
+			row.record = append([]string{row.id, row.version}, row.record...)
+		}
+
+		duplicateOf := 0
+		if row.voucherCode != "" {
+			key := s.duplicateKey(row.voucherCode)
+			if firstLine, ok := firstSeen[key]; ok {
+				duplicateOf = firstLine
+			} else {
+				firstSeen[key] = lineNumber
+			}
+		}
+
+		s.importRow(ctx, run, row, duplicateOf, dateParser, source)
+	}
+
+	if source.format == importFormatJSON {
+		err = decodeJSONArray(reader, run, handleItem)
+	} else {
+		err = decodeNDJSON(reader, run, handleItem)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.finishImport(ctx, "bulk."+source.format, headers, run, dateParser, source)
+}
+
+func firstNonSpaceByte(reader *bufio.Reader) (byte, error) {
+	for {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+
+		switch b {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+
+		return b, reader.UnreadByte()
+	}
+}
+
+// decodeJSONArray walks a JSON array item by item. An item that is not an
+// object is reported and skipped; malformed JSON stops the import because
+// the decoder cannot find the start of the next item.
+func decodeJSONArray(reader io.Reader, run *importRun, handleItem func(int, map[string]any)) error {
+	decoder := json.NewDecoder(reader)
+	decoder.UseNumber()
+
+	if _, err := decoder.Token(); err != nil {
+		return importFileError("invalid json array: %s", err.Error()).Wrap(err)
+	}
+
+	index := 0
+	for decoder.More() {
+		index++
+
+		var item map[string]any
+		if err := decoder.Decode(&item); err != nil {
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &typeErr) {
+				run.fail(importRow{lineNumber: index}, "Item must be a JSON object.")
+				continue
+			}
+
+			run.fail(importRow{lineNumber: index}, fmt.Sprintf("Invalid JSON, import stopped at this item: %s", err.Error()))
+			return nil
+		}
+
+		handleItem(index, item)
+	}
+
+	return nil
+}
+
+func decodeNDJSON(reader *bufio.Reader, run *importRun, handleItem func(int, map[string]any)) error {
+	lineNumber := 0
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			lineNumber++
+		}
+
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			decoder := json.NewDecoder(bytes.NewReader(line))
+			decoder.UseNumber()
+
+			var item map[string]any
+			if decodeErr := decoder.Decode(&item); decodeErr != nil {
+				run.fail(importRow{lineNumber: lineNumber}, "Line is not a valid JSON object.")
+			} else {
+				handleItem(lineNumber, item)
+			}
+		}
+
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return importFileError("failed to read request body: %s", err.Error()).Wrap(err)
+		}
+	}
+}
+
+// jsonFieldValue renders a decoded JSON value the way it would appear in a
+// CSV cell, so both paths share the same validation.
+func jsonFieldValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(v)
+	case json.Number:
+		return v.String()
+	default:
+		encoded, _ := json.Marshal(v)
+		return string(encoded)
+	}
+}
+
+// jsonDiscountValue is jsonFieldValue for discount_percent. JSON does not
+// tell 10 from 10.0 or 1e1, so any number with a whole value is passed on
+// as an integer; fractions are passed on as written and rejected by the
+// row validation like any other non-integer.
+func jsonDiscountValue(value any) string {
+	if number, ok := value.(json.Number); ok {
+		f, err := strconv.ParseFloat(number.String(), 64)
+		if err == nil && f == math.Trunc(f) && math.Abs(f) <= math.MaxInt32 {
+			return strconv.FormatInt(int64(f), 10)
+		}
+	}
+
+	return jsonFieldValue(value)
+}
+
+// ExportNDJSON streams the vouchers matching the query as one JSON object
+// per line, flushing as it goes so clients can start consuming before the
+// export finishes. Column selection does not apply; objects are always
+// complete vouchers.
+func (s *VoucherService) ExportNDJSON(ctx context.Context, query *dto.VoucherExportQuery, w io.Writer) error {
+	filter, err := s.exportFilter(query)
+	if err != nil {
+		return err
+	}
+
+	flusher, _ := w.(http.Flusher)
+	encoder := json.NewEncoder(w)
+	written := 0
+
+	return s.filters.StreamVouchers(ctx, filter, func(voucher repository.Voucher) error {
+		if err := encoder.Encode(toVoucherResponse(&voucher)); err != nil {
+			return err
+		}
+
+		written++
+		if flusher != nil && written%exportFlushEvery == 0 {
+			flusher.Flush()
+		}
+
+		return nil
+	})
+}
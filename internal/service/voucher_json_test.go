@@ -0,0 +1,30 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestJSONDiscountValue(t *testing.T) {
+	tests := []struct {
+		value any
+		want  string
+	}{
+		{json.Number("10"), "10"},
+		{json.Number("10.0"), "10"},
+		{json.Number("1e1"), "10"},
+		{json.Number("0.0"), "0"},
+		{json.Number("-5"), "-5"},
+		{json.Number("10.5"), "10.5"},
+		{json.Number("1e400"), "1e400"},
+		{" 20 ", "20"},
+		{nil, ""},
+		{true, "true"},
+	}
+
+	for _, tt := range tests {
+		if got := jsonDiscountValue(tt.value); got != tt.want {
+			t.Errorf("jsonDiscountValue(%#v) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
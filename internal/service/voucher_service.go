@@ -2,29 +2,47 @@ package service
 
 import (
 	"context"
-	"encoding/csv"
-	"errors"
 	"fmt"
 	"io"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/alifdwt/techtest-indico-be/internal/apperror"
+	"github.com/alifdwt/techtest-indico-be/internal/config"
 	"github.com/alifdwt/techtest-indico-be/internal/dto"
+	"github.com/alifdwt/techtest-indico-be/internal/i18n"
 	"github.com/alifdwt/techtest-indico-be/internal/repository"
+	"github.com/alifdwt/techtest-indico-be/internal/repository/voucherfilter"
+	"github.com/alifdwt/techtest-indico-be/internal/util"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type VoucherService struct {
-	repo *repository.Queries
+	pool           txBeginner
+	repo           *repository.Queries
+	filters        *voucherfilter.Queries
+	importCfg      config.ImportConfig
+	concurrencyCfg config.ConcurrencyConfig
+	dateParser     *util.DateParser
 }
 
-func NewVoucherService(repo *repository.Queries) *VoucherService {
+// NewVoucherService creates the voucher service. Every voucher that is
+// created, updated or deleted is recorded as an event in the outbox, in the
+// same transaction as the change.
+func NewVoucherService(pool *pgxpool.Pool, repo *repository.Queries, importCfg config.ImportConfig, concurrencyCfg config.ConcurrencyConfig, dateParser *util.DateParser) *VoucherService {
 	return &VoucherService{
-		repo: repo,
+		pool:           pool,
+		repo:           repo,
+		filters:        voucherfilter.New(pool),
+		importCfg:      importCfg,
+		concurrencyCfg: concurrencyCfg,
+		dateParser:     dateParser,
 	}
 }
 
@@ -35,12 +53,12 @@ func (s *VoucherService) CreateVoucher(ctx context.Context, req *dto.CreateVouch
 	}
 
 	if existingVoucher.ID.Valid {
-		return nil, fmt.Errorf("voucher with code %s already exists", req.VoucherCode)
+		return nil, voucherCodeTaken(req.VoucherCode)
 	}
 
-	expiryDateTime, err := time.Parse("2006-01-02", req.ExpiryDate)
+	expiryDateTime, err := s.dateParser.ParseExpiry(req.ExpiryDate)
 	if err != nil {
-		return nil, err
+		return nil, invalidExpiryDate(err)
 	}
 
 	obj := repository.CreateVoucherParams{
@@ -48,15 +66,26 @@ func (s *VoucherService) CreateVoucher(ctx context.Context, req *dto.CreateVouch
 		DiscountPercent: int32(req.DiscountPercent),
 		ExpiryDate:      pgtype.Timestamptz{Time: expiryDateTime, Valid: true},
 	}
-	voucher, err := s.repo.CreateVoucher(ctx, obj)
+	var voucher repository.Voucher
+	err = s.inTx(ctx, func(repo *repository.Queries) error {
+		voucher, err = repo.CreateVoucher(ctx, obj)
+		if err != nil {
+			return err
+		}
+		return recordVoucherEvent(ctx, repo, EventVoucherCreated, &voucher)
+	})
 	if err != nil {
+		// Another request may have taken the code since the check above.
+		if isUniqueViolation(err) {
+			return nil, voucherCodeTaken(req.VoucherCode)
+		}
 		return nil, err
 	}
 
-	return s.toVoucherResponse(&voucher), nil
+	return toVoucherResponse(&voucher), nil
 }
 
-func (s *VoucherService) toVoucherResponse(voucher *repository.Voucher) *dto.VoucherResponse {
+func toVoucherResponse(voucher *repository.Voucher) *dto.VoucherResponse {
 	return &dto.VoucherResponse{
 		ID:              voucher.ID,
 		VoucherCode:     voucher.VoucherCode,
@@ -64,57 +93,261 @@ func (s *VoucherService) toVoucherResponse(voucher *repository.Voucher) *dto.Vou
 		ExpiryDate:      voucher.ExpiryDate.Time,
 		CreatedAt:       voucher.CreatedAt.Time,
 		UpdatedAt:       voucher.UpdatedAt.Time,
+		Version:         int(voucher.Version),
 	}
 }
 
-func (s *VoucherService) ListVouchers(ctx context.Context, query *dto.VoucherListQuery) ([]*dto.VoucherResponse, int64, error) {
-	sortOrder := query.SortOrder
-	if sortOrder != "asc" && sortOrder != "desc" {
-		sortOrder = "asc"
+// expectedVersion checks the If-Match header of a write against the
+// voucher's ETag. It returns the version the write must still find in the
+// database, which is unset when the request gave no If-Match.
+func (s *VoucherService) expectedVersion(voucher *repository.Voucher, ifMatch string) (pgtype.Int4, error) {
+	if ifMatch == "" {
+		if s.concurrencyCfg.RequireIfMatch {
+			return pgtype.Int4{}, apperror.PreconditionRequired("if_match_required", "If-Match header with the voucher's ETag is required")
+		}
+		return pgtype.Int4{}, nil
+	}
+
+	if !util.MatchETag(ifMatch, util.ETag(int(voucher.Version)), false) {
+		return pgtype.Int4{}, voucherModified()
 	}
 
-	var searchSQL pgtype.Text
-	if query.Search != "" {
-		searchSQL = pgtype.Text{String: query.Search, Valid: true}
-	} else {
-		searchSQL = pgtype.Text{Valid: false}
+	return pgtype.Int4{Int32: voucher.Version, Valid: true}, nil
+}
+
+// ListVouchers returns one page of vouchers. With a cursor the page is
+// located by keyset, which stays stable while vouchers are added; otherwise
+// page and limit are used as an offset. Both modes return cursors for the
+// neighbouring pages; cursor pages have no page number in their metadata.
+func (s *VoucherService) ListVouchers(ctx context.Context, query *dto.VoucherListQuery) ([]*dto.VoucherResponse, util.Meta, error) {
+	sortBy := query.SortBy
+	if sortBy == "" && query.Sort == "" {
+		sortBy = "expiry_date"
+	}
+
+	filter, err := s.voucherFilter(&query.VoucherFilterQuery, query.Sort, sortBy, query.SortOrder)
+	if err != nil {
+		return nil, util.Meta{}, err
 	}
 
 	offset := (query.Page - 1) * query.Limit
+	if query.Cursor != "" {
+		if filter.Ranked() {
+			return nil, util.Meta{}, invalidCursor("invalid cursor: cursor pagination is not available when sorting by relevance")
+		}
+
+		keyset, err := decodeVoucherCursor(filter, query.Cursor)
+		if err != nil {
+			return nil, util.Meta{}, err
+		}
+		filter.Keyset = keyset
+		offset = 0
+	}
 
-	obj := repository.ListVouchersParams{
-		Search:    searchSQL,
-		SortBy:    pgtype.Text{String: query.SortBy, Valid: true},
-		SortOrder: pgtype.Text{String: sortOrder, Valid: true},
-		Limit:     int32(query.Limit),
-		Offset:    int32(offset),
+	// One extra row tells whether there is a page beyond this one.
+	vouchers, err := s.filters.ListVouchers(ctx, filter, int32(query.Limit+1), int32(offset))
+	if err != nil {
+		return nil, util.Meta{}, err
 	}
-	vouchers, err := s.repo.ListVouchers(ctx, obj)
+
+	hasMore := len(vouchers) > query.Limit
+	if hasMore {
+		vouchers = vouchers[:query.Limit]
+	}
+
+	backward := filter.Keyset != nil && filter.Keyset.Backward
+	if backward {
+		slices.Reverse(vouchers)
+	}
+
+	total, err := s.filters.CountVouchers(ctx, filter)
 	if err != nil {
-		return nil, 0, err
+		return nil, util.Meta{}, err
+	}
+
+	responses := []*dto.VoucherResponse{}
+	for _, voucher := range vouchers {
+		responses = append(responses, toVoucherResponse(&voucher))
+	}
+
+	meta := util.NewMeta(total, query.Page, query.Limit)
+	if filter.Keyset != nil {
+		meta.Page = 0
+	}
+
+	// Going forward there are earlier rows whenever we did not start at the
+	// top; going backward there are later rows, the ones we came from.
+	meta.HasNext = hasMore
+	meta.HasPrev = filter.Keyset != nil || offset > 0
+	if backward {
+		meta.HasNext, meta.HasPrev = true, hasMore
+	}
+
+	if len(vouchers) == 0 || filter.Ranked() {
+		return responses, meta, nil
+	}
+
+	filter.Keyset = nil
+	if meta.HasNext {
+		if meta.NextCursor, err = encodeVoucherCursor(filter, &vouchers[len(vouchers)-1], false); err != nil {
+			return nil, util.Meta{}, err
+		}
+	}
+	if meta.HasPrev {
+		if meta.PrevCursor, err = encodeVoucherCursor(filter, &vouchers[0], true); err != nil {
+			return nil, util.Meta{}, err
+		}
+	}
+
+	return responses, meta, nil
+}
+
+// GetVouchersByID looks up several vouchers with one query. The result is
+// keyed by ID; IDs without a voucher are left out.
+func (s *VoucherService) GetVouchersByID(ctx context.Context, ids []string) (map[string]*dto.VoucherResponse, error) {
+	voucherIDs := make([]pgtype.UUID, 0, len(ids))
+	for _, id := range ids {
+		voucherID, err := uuid.Parse(id)
+		if err != nil {
+			return nil, apperror.InvalidID("invalid_voucher_id", "invalid voucher id %s", id)
+		}
+		voucherIDs = append(voucherIDs, pgtype.UUID{Bytes: voucherID, Valid: true})
 	}
 
-	total, err := s.repo.CountVouchers(ctx, obj.Search)
+	vouchers, err := s.repo.ListVouchersByIDs(ctx, voucherIDs)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
-	var responses []*dto.VoucherResponse
+	responses := make(map[string]*dto.VoucherResponse, len(vouchers))
 	for _, voucher := range vouchers {
-		responses = append(responses, s.toVoucherResponse(&voucher))
+		responses[uuid.UUID(voucher.ID.Bytes).String()] = toVoucherResponse(&voucher)
+	}
+
+	return responses, nil
+}
+
+// CountVouchers counts the vouchers matching the list filters.
+func (s *VoucherService) CountVouchers(ctx context.Context, query *dto.VoucherFilterQuery) (int64, error) {
+	filter, err := s.voucherFilter(query, "", "created_at", "asc")
+	if err != nil {
+		return 0, err
+	}
+
+	return s.filters.CountVouchers(ctx, filter)
+}
+
+// AutocompleteCodes returns up to limit voucher codes starting with the
+// given text, case-insensitively, in alphabetical order.
+func (s *VoucherService) AutocompleteCodes(ctx context.Context, query *dto.VoucherAutocompleteQuery) ([]string, error) {
+	prefix := strings.TrimSpace(query.Q)
+	if prefix == "" {
+		return []string{}, nil
+	}
+
+	return s.repo.AutocompleteVoucherCodes(ctx, repository.AutocompleteVoucherCodesParams{
+		Pattern:    strings.ToLower(voucherfilter.EscapeLike(prefix)) + "%",
+		MaxResults: int32(query.Limit),
+	})
+}
+
+// voucherFilter builds the repository filter shared by listing, counting
+// and export, parsing dates in the configured timezone. Errors start with
+// "invalid filter".
+func (s *VoucherService) voucherFilter(query *dto.VoucherFilterQuery, sortSpec, sortBy, sortOrder string) (voucherfilter.Filter, error) {
+	var sortKeys []voucherfilter.SortKey
+	if sortSpec != "" {
+		if sortBy != "" || sortOrder != "" {
+			return voucherfilter.Filter{}, filterError("sort", "excluded_with", "sort_by sort_order", i18n.MsgExcludedWith, "sort cannot be combined with sort_by or sort_order")
+		}
+
+		var err error
+		if sortKeys, err = voucherfilter.ParseSort(sortSpec); err != nil {
+			return voucherfilter.Filter{}, filterError("sort", "sort", strings.Join(voucherfilter.SortFields(), " "), i18n.MsgSortSpec, "%s", err.Error())
+		}
+	}
+
+	if sortOrder != "asc" && sortOrder != "desc" {
+		sortOrder = "asc"
+	}
+
+	filter := voucherfilter.Filter{
+		Search:             strings.TrimSpace(query.Search),
+		SearchMode:         query.SearchMode,
+		DiscountMin:        toInt32Ptr(query.DiscountMin),
+		DiscountMax:        toInt32Ptr(query.DiscountMax),
+		Status:             query.Status,
+		ExpiringWithinDays: toInt32Ptr(query.ExpiringWithinDays),
+		Sort:               sortKeys,
+		SortBy:             sortBy,
+		SortOrder:          sortOrder,
+	}
+
+	if sortBy == voucherfilter.SortByRelevance && !filter.Ranked() {
+		return filter, filterError("sort_by", "relevance", "", i18n.MsgRelevance, "sort_by=relevance requires search_mode=fuzzy and a search term")
+	}
+
+	if filter.DiscountMin != nil && filter.DiscountMax != nil && *filter.DiscountMin > *filter.DiscountMax {
+		return filter, filterError("discount_min", "ltefield", "discount_max", i18n.MsgNotGreaterThan, "discount_min cannot be greater than discount_max")
 	}
 
-	if responses == nil {
-		responses = []*dto.VoucherResponse{}
+	var err error
+	if filter.ExpiryFrom, filter.ExpiryTo, err = s.parseDateRange("expiry", query.ExpiryFrom, query.ExpiryTo); err != nil {
+		return filter, err
 	}
+	if filter.CreatedFrom, filter.CreatedTo, err = s.parseDateRange("created", query.CreatedFrom, query.CreatedTo); err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
+
+// filterError reports an invalid list or export filter.
+func filterError(field, rule, param, messageID, format string, args ...any) error {
+	return dto.FieldRuleError("invalid_filter", field, rule, param, messageID, "invalid filter: "+format, args...)
+}
+
+// parseDateRange parses the <name>_from and <name>_to filters, either of
+// which may be empty.
+func (s *VoucherService) parseDateRange(name, fromValue, toValue string) (*time.Time, *time.Time, error) {
+	var from, to *time.Time
+
+	if fromValue != "" {
+		parsed, err := s.dateParser.ParseRangeBound(fromValue, false)
+		if err != nil {
+			return nil, nil, filterError(name+"_from", "date", "", i18n.MsgDate, "%s_from %s", name, err.Error())
+		}
+		from = &parsed
+	}
+
+	if toValue != "" {
+		parsed, err := s.dateParser.ParseRangeBound(toValue, true)
+		if err != nil {
+			return nil, nil, filterError(name+"_to", "date", "", i18n.MsgDate, "%s_to %s", name, err.Error())
+		}
+		to = &parsed
+	}
+
+	if from != nil && to != nil && from.After(*to) {
+		return nil, nil, filterError(name+"_from", "ltefield", name+"_to", i18n.MsgNotAfter, "%s_from cannot be after %s_to", name, name)
+	}
+
+	return from, to, nil
+}
 
-	return responses, total, nil
+func toInt32Ptr(value *int) *int32 {
+	if value == nil {
+		return nil
+	}
+
+	converted := int32(*value)
+	return &converted
 }
 
 func (s *VoucherService) GetVoucherByID(ctx context.Context, id string) (*dto.VoucherResponse, error) {
 	voucherID, err := uuid.Parse(id)
 	if err != nil {
-		return nil, fmt.Errorf("invalid voucher id")
+		return nil, apperror.InvalidID("invalid_voucher_id", "invalid voucher id")
 	}
 
 	uuidPg := pgtype.UUID{Bytes: voucherID, Valid: true}
@@ -122,103 +355,272 @@ func (s *VoucherService) GetVoucherByID(ctx context.Context, id string) (*dto.Vo
 	voucher, err := s.repo.GetVoucherByID(ctx, uuidPg)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("voucher not found")
+			return nil, apperror.NotFound("voucher_not_found", "voucher not found")
 		}
 		return nil, err
 	}
 
-	return s.toVoucherResponse(&voucher), nil
+	return toVoucherResponse(&voucher), nil
 }
 
-func (s *VoucherService) UpdateVoucher(ctx context.Context, id string, req *dto.UpdateVoucherRequest) (*dto.VoucherResponse, error) {
+// UpdateVoucher replaces a voucher. ifMatch is the request's If-Match
+// header; when it is set the voucher must still have that ETag.
+func (s *VoucherService) UpdateVoucher(ctx context.Context, id string, req *dto.UpdateVoucherRequest, ifMatch string) (*dto.VoucherResponse, error) {
 	voucherID, err := uuid.Parse(id)
 	if err != nil {
-		return nil, fmt.Errorf("invalid voucher id")
+		return nil, apperror.InvalidID("invalid_voucher_id", "invalid voucher id")
 	}
 
 	uuidPg := pgtype.UUID{Bytes: voucherID, Valid: true}
 
-	_, err = s.repo.GetVoucherByID(ctx, uuidPg)
+	voucher, err := s.repo.GetVoucherByID(ctx, uuidPg)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("voucher not found")
+			return nil, apperror.NotFound("voucher_not_found", "voucher not found")
 		}
 		return nil, err
 	}
 
-	expiryDateTime, err := time.Parse("2006-01-02", req.ExpiryDate)
+	expectedVersion, err := s.expectedVersion(&voucher, ifMatch)
 	if err != nil {
 		return nil, err
 	}
 
+	expiryDateTime, err := s.dateParser.ParseExpiry(req.ExpiryDate)
+	if err != nil {
+		return nil, invalidExpiryDate(err)
+	}
+
 	obj := repository.UpdateVoucherParams{
 		ID:              uuidPg,
 		VoucherCode:     req.VoucherCode,
 		DiscountPercent: int32(req.DiscountPercent),
 		ExpiryDate:      pgtype.Timestamptz{Time: expiryDateTime, Valid: true},
+		ExpectedVersion: expectedVersion,
+	}
+	err = s.inTx(ctx, func(repo *repository.Queries) error {
+		voucher, err = repo.UpdateVoucher(ctx, obj)
+		if err != nil {
+			return err
+		}
+		return recordVoucherEvent(ctx, repo, EventVoucherUpdated, &voucher)
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, writeMissed(expectedVersion)
+		}
+		if isUniqueViolation(err) {
+			return nil, voucherCodeTaken(req.VoucherCode)
+		}
+		return nil, err
+	}
+
+	return toVoucherResponse(&voucher), nil
+}
+
+// PatchVoucher applies a merge patch to a voucher, leaving the fields the
+// patch does not mention untouched. An empty patch changes nothing.
+// ifMatch is handled as in UpdateVoucher.
+func (s *VoucherService) PatchVoucher(ctx context.Context, id string, req *dto.PatchVoucherRequest, ifMatch string) (*dto.VoucherResponse, error) {
+	voucherID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, apperror.InvalidID("invalid_voucher_id", "invalid voucher id")
+	}
+
+	uuidPg := pgtype.UUID{Bytes: voucherID, Valid: true}
+
+	voucher, err := s.repo.GetVoucherByID(ctx, uuidPg)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, apperror.NotFound("voucher_not_found", "voucher not found")
+		}
+		return nil, err
+	}
+
+	expectedVersion, err := s.expectedVersion(&voucher, ifMatch)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.IsEmpty() {
+		return toVoucherResponse(&voucher), nil
+	}
+
+	obj := repository.PatchVoucherParams{ID: uuidPg, ExpectedVersion: expectedVersion}
+	if req.VoucherCode != nil {
+		obj.VoucherCode = pgtype.Text{String: *req.VoucherCode, Valid: true}
+	}
+	if req.DiscountPercent != nil {
+		obj.DiscountPercent = pgtype.Int4{Int32: int32(*req.DiscountPercent), Valid: true}
+	}
+	if req.ExpiryDate != nil {
+		expiryDateTime, err := s.dateParser.ParseExpiry(*req.ExpiryDate)
+		if err != nil {
+			return nil, invalidExpiryDate(err)
+		}
+		obj.ExpiryDate = pgtype.Timestamptz{Time: expiryDateTime, Valid: true}
 	}
-	voucher, err := s.repo.UpdateVoucher(ctx, obj)
+
+	err = s.inTx(ctx, func(repo *repository.Queries) error {
+		voucher, err = repo.PatchVoucher(ctx, obj)
+		if err != nil {
+			return err
+		}
+		return recordVoucherEvent(ctx, repo, EventVoucherUpdated, &voucher)
+	})
 	if err != nil {
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
-			return nil, fmt.Errorf("voucher with code %s already exists", req.VoucherCode)
+		if err == pgx.ErrNoRows {
+			return nil, writeMissed(expectedVersion)
+		}
+		if isUniqueViolation(err) {
+			return nil, voucherCodeTaken(*req.VoucherCode)
 		}
 		return nil, err
 	}
 
-	return s.toVoucherResponse(&voucher), nil
+	return toVoucherResponse(&voucher), nil
 }
 
-func (s *VoucherService) DeleteVoucher(ctx context.Context, id string) error {
+// DeleteVoucher deletes a voucher; ifMatch is handled as in UpdateVoucher.
+func (s *VoucherService) DeleteVoucher(ctx context.Context, id string, ifMatch string) error {
 	voucherID, err := uuid.Parse(id)
 	if err != nil {
-		return fmt.Errorf("invalid voucher id")
+		return apperror.InvalidID("invalid_voucher_id", "invalid voucher id")
 	}
 
 	uuidPg := pgtype.UUID{Bytes: voucherID, Valid: true}
 
-	_, err = s.repo.GetVoucherByID(ctx, uuidPg)
+	voucher, err := s.repo.GetVoucherByID(ctx, uuidPg)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return fmt.Errorf("voucher not found")
+			return apperror.NotFound("voucher_not_found", "voucher not found")
 		}
 		return err
 	}
 
-	return s.repo.DeleteVoucher(ctx, uuidPg)
+	expectedVersion, err := s.expectedVersion(&voucher, ifMatch)
+	if err != nil {
+		return err
+	}
+
+	return s.inTx(ctx, func(repo *repository.Queries) error {
+		deleted, err := repo.DeleteVoucher(ctx, repository.DeleteVoucherParams{ID: uuidPg, ExpectedVersion: expectedVersion})
+		if err != nil {
+			return err
+		}
+		if deleted == 0 {
+			return writeMissed(expectedVersion)
+		}
+		return recordVoucherEvent(ctx, repo, EventVoucherDeleted, &voucher)
+	})
 }
 
-func (s *VoucherService) UploadCSV(ctx context.Context, file io.Reader) (*dto.CSVUploadResponse, error) {
-	requiredHeaders := []string{"voucher_code", "discount_percent", "expiry_date"}
-	reader := csv.NewReader(file)
-	var successCount int
+// writeMissed reports an update or delete that found no row although the
+// voucher existed a moment before: with If-Match it was changed or deleted
+// concurrently, otherwise it was deleted.
+func writeMissed(expectedVersion pgtype.Int4) error {
+	if expectedVersion.Valid {
+		return voucherModified()
+	}
+
+	return apperror.NotFound("voucher_not_found", "voucher not found")
+}
+
+// importRow is a single data row of an uploaded file, kept together with
+// its line number so failures can be reported against the original file.
+type importRow struct {
+	lineNumber      int
+	record          []string
+	id              string
+	version         string
+	voucherCode     string
+	discountPercent string
+	expiryDate      string
+}
+
+// importSource describes where the records of an import come from.
+type importSource struct {
+	format    string
+	delimiter string
+	encoding  string
+	// date1904 is set for spreadsheets using the 1904 date system; it is
+	// only meaningful when excelDates is true.
+	excelDates bool
+	date1904   bool
+}
+
+func (src importSource) describe() string {
+	if src.format == importFormatXLSX {
+		return "xlsx file"
+	}
+
+	return fmt.Sprintf("detected delimiter %s, encoding %s", src.delimiter, src.encoding)
+}
+
+// UploadCSV imports vouchers from an uploaded CSV or, when the file is a
+// workbook, from the selected sheet of an XLSX file.
+func (s *VoucherService) UploadCSV(ctx context.Context, fileName string, file io.Reader, opts *dto.CSVUploadOptions) (*dto.CSVUploadResponse, error) {
+	if opts == nil {
+		opts = &dto.CSVUploadOptions{}
+	}
+
+	if isXLSXFile(fileName) {
+		return s.uploadXLSX(ctx, fileName, file, opts)
+	}
+
+	reader, dialect, err := newCSVReader(file, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.importRecords(ctx, fileName, &csvRecordReader{reader: reader}, opts, importSource{
+		format:    importFormatCSV,
+		delimiter: describeDelimiter(dialect.Delimiter),
+		encoding:  dialect.Encoding,
+	})
+}
+
+func (s *VoucherService) importRecords(ctx context.Context, fileName string, reader recordReader, opts *dto.CSVUploadOptions, source importSource) (*dto.CSVUploadResponse, error) {
 	var failedRows []dto.FailedRow
 
-	headers, err := reader.Read()
+	dateParser, err := s.importDateParser(opts.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	var mapping map[string]string
+	if opts.MappingProfile != "" {
+		mapping, err = loadImportMapping(ctx, s.repo, opts.MappingProfile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	headers, _, err := reader.Read()
 	if err != nil {
 		if err == io.EOF {
-			return nil, fmt.Errorf("csv file is empty")
+			return nil, importFileError("%s file is empty", source.format)
 		}
-		return nil, fmt.Errorf("failed to read csv headers: %w", err)
+		return nil, importFileError("failed to read %s headers: %s", source.format, err.Error()).Wrap(err)
 	}
 
-	headerMap := make(map[string]int)
-	for i, header := range headers {
-		normalizedHeader := strings.TrimSpace(strings.ToLower(header))
-		headerMap[normalizedHeader] = i
+	columns, err := resolveImportColumns(headers, mapping)
+	if err != nil {
+		return nil, importFileError("%s (%s)", err.Error(), source.describe())
 	}
 
-	for _, required := range requiredHeaders {
-		if _, ok := headerMap[required]; !ok {
-			return nil, fmt.Errorf("header '%s' not found in the csv header", required)
-		}
+	idColumn, hasIDColumn := columns[voucherImportIDField]
+	versionColumn, hasVersionColumn := columns[voucherImportVersionField]
+	if opts.MatchByID && !hasIDColumn {
+		return nil, importFileError("header '%s' not found in the csv header, it is required with match_by_id (%s)", voucherImportIDField, source.describe())
 	}
 
-	lineNumber := 1
+	// Read the whole file first so duplicate codes can be detected before
+	// anything is written to the database.
+	var rows []importRow
 
 	for {
-		record, err := reader.Read()
-		lineNumber++
+		record, lineNumber, err := reader.Read()
 
 		if err == io.EOF {
 			break
@@ -227,93 +629,333 @@ func (s *VoucherService) UploadCSV(ctx context.Context, file io.Reader) (*dto.CS
 			failedRows = append(failedRows, dto.FailedRow{
 				RowNumber: lineNumber,
 				Reason:    "csv row format is not vlaid",
+				Record:    record,
 			})
 			continue
 		}
 
-		voucherCode := strings.TrimSpace(record[headerMap["voucher_code"]])
-		discountPercentStr := strings.TrimSpace(record[headerMap["discount_percent"]])
-		expiryDateStr := strings.TrimSpace(record[headerMap["expiry_date"]])
-
-		recordFailed := func(reason string) {
-			failedRows = append(failedRows, dto.FailedRow{
-				RowNumber:   lineNumber,
-				VoucherCode: voucherCode,
-				Reason:      reason,
-			})
+		row := importRow{
+			lineNumber:      lineNumber,
+			record:          record,
+			voucherCode:     recordValue(record, columns["voucher_code"]),
+			discountPercent: recordValue(record, columns["discount_percent"]),
+			expiryDate:      recordValue(record, columns["expiry_date"]),
 		}
-
-		if voucherCode == "" || discountPercentStr == "" || expiryDateStr == "" {
-			recordFailed("voucher_code, discount_percent, or expiry_date are empty.")
-			continue
+		if opts.MatchByID {
+			row.id = recordValue(record, idColumn)
+			if hasVersionColumn {
+				row.version = recordValue(record, versionColumn)
+			}
 		}
+		rows = append(rows, row)
+	}
+
+	duplicateOf := s.findDuplicateCodes(rows)
+
+	run := &importRun{failedRows: failedRows}
+	for _, row := range rows {
+		s.importRow(ctx, run, row, duplicateOf[row.lineNumber], dateParser, source)
+	}
+
+	return s.finishImport(ctx, fileName, headers, run, dateParser, source)
+}
+
+func (s *VoucherService) importDateParser(timezone string) (*util.DateParser, error) {
+	if timezone == "" {
+		return s.dateParser, nil
+	}
+
+	parser, err := s.dateParser.WithTimezone(timezone)
+	if err != nil {
+		return nil, dto.FieldRuleError("invalid_timezone", "timezone", "timezone", "", i18n.MsgTimezone, "%s", err.Error())
+	}
+
+	return parser, nil
+}
+
+// importRun accumulates the outcome of a single import. updatedCount is the
+// part of successCount that updated an existing voucher.
+type importRun struct {
+	successCount int
+	updatedCount int
+	failedRows   []dto.FailedRow
+}
+
+func (run *importRun) fail(row importRow, reason string) {
+	run.failedRows = append(run.failedRows, dto.FailedRow{
+		RowNumber:   row.lineNumber,
+		VoucherCode: row.voucherCode,
+		Reason:      reason,
+		Record:      row.record,
+	})
+}
+
+// importRow validates a single row and creates its voucher, or updates the
+// voucher with the row's ID when the import matches by ID. duplicateOf is
+// the line where the same code first appeared in the file, or 0.
+func (s *VoucherService) importRow(ctx context.Context, run *importRun, row importRow, duplicateOf int, dateParser *util.DateParser, source importSource) {
+	if row.voucherCode == "" || row.discountPercent == "" || row.expiryDate == "" {
+		run.fail(row, "voucher_code, discount_percent, or expiry_date are empty.")
+		return
+	}
+
+	if duplicateOf > 0 {
+		run.failedRows = append(run.failedRows, dto.FailedRow{
+			RowNumber:      row.lineNumber,
+			VoucherCode:    row.voucherCode,
+			Reason:         fmt.Sprintf("Duplicate voucher_code in file, first seen on line %d.", duplicateOf),
+			DuplicateOfRow: duplicateOf,
+			Record:         row.record,
+		})
+		return
+	}
+
+	discountPercent, err := strconv.Atoi(row.discountPercent)
+	if err != nil {
+		run.fail(row, fmt.Sprintf("Discount percent must be a number: %s", err.Error()))
+		return
+	}
+	if discountPercent < 0 || discountPercent > 100 {
+		run.fail(row, "Discount percent must be between 0 and 100.")
+		return
+	}
 
-		var discountPercent int
-		discountPercent, err = strconv.Atoi(discountPercentStr)
+	expiryDate, err := dateParser.ParseExpiry(row.expiryDate)
+	if err != nil && source.excelDates {
+		expiryDate, err = parseExcelDate(dateParser, row.expiryDate, source.date1904)
+	}
+	if err != nil {
+		run.fail(row, err.Error())
+		return
+	}
+
+	if row.id != "" {
+		s.updateImportedVoucher(ctx, run, row, int32(discountPercent), expiryDate)
+		return
+	}
+
+	obj := repository.CreateVoucherParams{
+		VoucherCode:     row.voucherCode,
+		DiscountPercent: int32(discountPercent),
+		ExpiryDate:      pgtype.Timestamptz{Time: expiryDate, Valid: true},
+	}
+	err = s.inTx(ctx, func(repo *repository.Queries) error {
+		voucher, err := repo.CreateVoucher(ctx, obj)
 		if err != nil {
-			recordFailed(fmt.Sprintf("Discount percent must be a number: %s", err.Error()))
-			continue
-		}
-		if discountPercent < 0 || discountPercent > 100 {
-			recordFailed("Discount percent must be between 0 and 100.")
-			continue
+			return err
 		}
+		return recordVoucherEvent(ctx, repo, EventVoucherCreated, &voucher)
+	})
+	if err != nil {
+		run.fail(row, "Failed to save to database (Possibly duplicate voucher_code)")
+		return
+	}
 
-		expiryDate, err := time.Parse("2006-01-02", expiryDateStr)
-		if err != nil {
-			expiryDate, err = time.Parse("2006-01-02 15:04:05", expiryDateStr)
-			if err != nil {
+	run.successCount++
+}
 
-				recordFailed("expiry_date format is not valid. Use YYYY-MM-DD or YYYY-MM-DD HH:MM:SS.")
-				continue
-			}
-		}
+// updateImportedVoucher updates the voucher named by the row's ID. The
+// row's version, like If-Match on a PUT, makes the update apply only to
+// that version; it is required when REQUIRE_IF_MATCH is set.
+func (s *VoucherService) updateImportedVoucher(ctx context.Context, run *importRun, row importRow, discountPercent int32, expiryDate time.Time) {
+	voucherID, err := uuid.Parse(row.id)
+	if err != nil {
+		run.fail(row, "id is not a valid voucher id.")
+		return
+	}
 
-		obj := repository.CreateVoucherParams{
-			VoucherCode:     voucherCode,
-			DiscountPercent: int32(discountPercent),
-			ExpiryDate:      pgtype.Timestamptz{Time: expiryDate, Valid: true},
+	var expectedVersion pgtype.Int4
+	if row.version != "" {
+		version, err := strconv.ParseInt(row.version, 10, 32)
+		if err != nil || version < 1 {
+			run.fail(row, "version must be a positive whole number.")
+			return
 		}
-		_, err = s.repo.CreateVoucher(ctx, obj)
+		expectedVersion = pgtype.Int4{Int32: int32(version), Valid: true}
+	} else if s.concurrencyCfg.RequireIfMatch {
+		run.fail(row, "version is required to update a voucher.")
+		return
+	}
+
+	obj := repository.UpdateVoucherParams{
+		ID:              pgtype.UUID{Bytes: voucherID, Valid: true},
+		VoucherCode:     row.voucherCode,
+		DiscountPercent: discountPercent,
+		ExpiryDate:      pgtype.Timestamptz{Time: expiryDate, Valid: true},
+		ExpectedVersion: expectedVersion,
+	}
+	err = s.inTx(ctx, func(repo *repository.Queries) error {
+		voucher, err := repo.UpdateVoucher(ctx, obj)
 		if err != nil {
-			recordFailed("Failed to save to database (Possibly duplicate voucher_code)")
-			continue
+			return err
+		}
+		return recordVoucherEvent(ctx, repo, EventVoucherUpdated, &voucher)
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			run.fail(row, s.importUpdateMissReason(ctx, obj.ID, expectedVersion))
+			return
 		}
+		run.fail(row, "Failed to save to database (Possibly duplicate voucher_code)")
+		return
+	}
+
+	run.successCount++
+	run.updatedCount++
+}
 
-		successCount++
+// importUpdateMissReason explains why an import update found no voucher:
+// it does not exist, or it is no longer at the expected version.
+func (s *VoucherService) importUpdateMissReason(ctx context.Context, id pgtype.UUID, expectedVersion pgtype.Int4) string {
+	if expectedVersion.Valid {
+		voucher, err := s.repo.GetVoucherByID(ctx, id)
+		if err == nil {
+			return fmt.Sprintf("Voucher was modified: it is at version %d, not %d.", voucher.Version, expectedVersion.Int32)
+		}
 	}
 
+	return "No voucher exists with this id."
+}
+
+// finishImport stores the import report and builds the upload response.
+func (s *VoucherService) finishImport(ctx context.Context, fileName string, headers []string, run *importRun, dateParser *util.DateParser, source importSource) (*dto.CSVUploadResponse, error) {
+	failedRows := run.failedRows
 	if failedRows == nil {
 		failedRows = []dto.FailedRow{}
 	}
 
+	sort.SliceStable(failedRows, func(i, j int) bool {
+		return failedRows[i].RowNumber < failedRows[j].RowNumber
+	})
+
+	voucherImport, err := s.saveImportReport(ctx, fileName, headers, run.successCount, failedRows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save import report: %w", err)
+	}
+
 	return &dto.CSVUploadResponse{
-		SuccessCount: successCount,
+		ImportID:     voucherImport.ID,
+		SuccessCount: run.successCount,
+		UpdatedCount: run.updatedCount,
 		FailedCount:  len(failedRows),
 		FailedRows:   failedRows,
+		Format:       source.format,
+		Delimiter:    source.delimiter,
+		Encoding:     source.encoding,
+		Timezone:     dateParser.Location().String(),
 	}, nil
 }
 
-func (s *VoucherService) ExportCSV(ctx context.Context) ([][]string, error) {
-	vouchers, err := s.repo.GetAllVouchersForExport(ctx)
+// saveImportReport stores the outcome of an import together with the raw
+// failing rows, so an error report can be downloaded later.
+func (s *VoucherService) saveImportReport(ctx context.Context, fileName string, headers []string, successCount int, failedRows []dto.FailedRow) (*repository.VoucherImport, error) {
+	voucherImport, err := s.repo.CreateVoucherImport(ctx, repository.CreateVoucherImportParams{
+		FileName:     fileName,
+		Headers:      headers,
+		SuccessCount: int32(successCount),
+		FailedCount:  int32(len(failedRows)),
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	var records [][]string
-	records = append(records, []string{"ID", "Voucher Code", "Discount Percent", "Expiry Date", "Created At", "Updated At"})
+	for _, failedRow := range failedRows {
+		record := failedRow.Record
+		if record == nil {
+			record = []string{}
+		}
 
-	for _, voucher := range vouchers {
-		record := []string{
-			voucher.ID.String(),
-			voucher.VoucherCode,
-			strconv.Itoa(int(voucher.DiscountPercent)),
-			voucher.ExpiryDate.Time.Format("2006-01-02 15:04:05"),
-			voucher.CreatedAt.Time.Format("2006-01-02 15:04:05"),
-			voucher.UpdatedAt.Time.Format("2006-01-02 15:04:05"),
+		err = s.repo.CreateVoucherImportFailedRow(ctx, repository.CreateVoucherImportFailedRowParams{
+			ImportID:  voucherImport.ID,
+			RowNumber: int32(failedRow.RowNumber),
+			Record:    record,
+			Reason:    failedRow.Reason,
+		})
+		if err != nil {
+			return nil, err
 		}
-		records = append(records, record)
+	}
+
+	return &voucherImport, nil
+}
+
+// ImportErrorReport rebuilds the failing rows of an import as CSV records:
+// the original header plus an error_reason column, ready to be fixed and
+// uploaded again.
+func (s *VoucherService) ImportErrorReport(ctx context.Context, id string) ([][]string, error) {
+	importID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, apperror.InvalidID("invalid_import_id", "invalid import id")
+	}
+
+	uuidPg := pgtype.UUID{Bytes: importID, Valid: true}
+
+	voucherImport, err := s.repo.GetVoucherImportByID(ctx, uuidPg)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, apperror.NotFound("import_not_found", "import not found")
+		}
+		return nil, err
+	}
+
+	failedRows, err := s.repo.ListVoucherImportFailedRows(ctx, uuidPg)
+	if err != nil {
+		return nil, err
+	}
+
+	columnCount := len(voucherImport.Headers)
+
+	var records [][]string
+	records = append(records, append(append([]string{}, voucherImport.Headers...), "error_reason"))
+
+	for _, failedRow := range failedRows {
+		record := make([]string, columnCount, columnCount+1)
+		copy(record, failedRow.Record)
+		records = append(records, append(record, failedRow.Reason))
 	}
 
 	return records, nil
 }
+
+// recordValue returns the trimmed value of a column. Spreadsheet rows leave
+// out trailing empty cells, so a missing column reads as empty.
+func recordValue(record []string, index int) string {
+	if index >= len(record) {
+		return ""
+	}
+
+	return strings.TrimSpace(record[index])
+}
+
+// findDuplicateCodes maps the line number of every repeated voucher code to
+// the line where that code first appeared. Codes are compared
+// case-insensitively when the import config asks for it.
+func (s *VoucherService) findDuplicateCodes(rows []importRow) map[int]int {
+	firstSeen := make(map[string]int)
+	duplicateOf := make(map[int]int)
+
+	for _, row := range rows {
+		if row.voucherCode == "" {
+			continue
+		}
+
+		key := s.duplicateKey(row.voucherCode)
+		if firstLine, ok := firstSeen[key]; ok {
+			duplicateOf[row.lineNumber] = firstLine
+			continue
+		}
+		firstSeen[key] = row.lineNumber
+	}
+
+	return duplicateOf
+}
+
+// duplicateKey is the form of a voucher code used to spot duplicates within
+// a single import.
+func (s *VoucherService) duplicateKey(voucherCode string) string {
+	if s.importCfg.CaseInsensitiveCodes {
+		return strings.ToLower(voucherCode)
+	}
+
+	return voucherCode
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alifdwt/techtest-indico-be/internal/dto"
+	"github.com/alifdwt/techtest-indico-be/internal/i18n"
+	"github.com/alifdwt/techtest-indico-be/internal/repository"
+	"github.com/alifdwt/techtest-indico-be/internal/util"
+	"github.com/xuri/excelize/v2"
+)
+
+const xlsxExportSheet = "Vouchers"
+
+func isXLSXFile(fileName string) bool {
+	return strings.EqualFold(filepath.Ext(fileName), ".xlsx")
+}
+
+func (s *VoucherService) uploadXLSX(ctx context.Context, fileName string, file io.Reader, opts *dto.CSVUploadOptions) (*dto.CSVUploadResponse, error) {
+	// Raw values keep numbers and dates as stored instead of formatted for
+	// display, so "10%" or "31/12/24" renderings never reach the importer.
+	workbook, err := excelize.OpenReader(file, excelize.Options{RawCellValue: true})
+	if err != nil {
+		return nil, importFileError("failed to open xlsx file: %s", err.Error()).Wrap(err)
+	}
+	defer workbook.Close()
+
+	sheet := opts.Sheet
+	if sheet == "" {
+		sheets := workbook.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, importFileError("xlsx file has no sheets")
+		}
+		sheet = sheets[0]
+	} else if index, err := workbook.GetSheetIndex(sheet); err != nil || index == -1 {
+		return nil, dto.FieldRuleError("sheet_not_found", "sheet", "exists", "", i18n.MsgNotFound, "sheet '%s' not found in the xlsx file", sheet)
+	}
+
+	rows, err := workbook.Rows(sheet)
+	if err != nil {
+		return nil, importFileError("failed to read sheet '%s': %s", sheet, err.Error()).Wrap(err)
+	}
+	defer rows.Close()
+
+	props, err := workbook.GetWorkbookProps()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read xlsx workbook properties: %w", err)
+	}
+
+	return s.importRecords(ctx, fileName, &xlsxRecordReader{rows: rows}, opts, importSource{
+		format:     importFormatXLSX,
+		excelDates: true,
+		date1904:   props.Date1904 != nil && *props.Date1904,
+	})
+}
+
+// xlsxRecordReader reads sheet rows as records. Blank rows are skipped but
+// still counted, so line numbers match the row numbers shown in Excel.
+type xlsxRecordReader struct {
+	rows *excelize.Rows
+	line int
+}
+
+func (r *xlsxRecordReader) Read() ([]string, int, error) {
+	for r.rows.Next() {
+		r.line++
+
+		record, err := r.rows.Columns()
+		if err != nil {
+			return nil, r.line, err
+		}
+		if isBlankRecord(record) {
+			continue
+		}
+
+		return record, r.line, nil
+	}
+
+	if err := r.rows.Error(); err != nil {
+		return nil, r.line, err
+	}
+
+	return nil, r.line, io.EOF
+}
+
+func isBlankRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// parseExcelDate converts a spreadsheet date serial (e.g. 45657 or
+// 45657.75) into an expiry in the parser's timezone. Whole numbers are
+// dates without a time and expire at the end of the day.
+func parseExcelDate(parser *util.DateParser, value string, date1904 bool) (time.Time, error) {
+	serial, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expiry_date format is not valid. Use one of: %s, or an Excel date cell", parser.FormatNames())
+	}
+
+	wallClock, err := excelize.ExcelDateToTime(serial, date1904)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expiry_date is not a valid Excel date: %w", err)
+	}
+
+	return parser.FromWallClock(wallClock, serial == float64(int64(serial))), nil
+}
+
+// ExportXLSX streams the vouchers matching the query into a workbook with
+// typed cells: numbers for the discount and real date cells, shown in the
+// configured timezone. Date cells are read back natively on upload, so the
+// import profile only changes the headers.
+func (s *VoucherService) ExportXLSX(ctx context.Context, query *dto.VoucherExportQuery, w io.Writer) error {
+	filter, err := s.exportFilter(query)
+	if err != nil {
+		return err
+	}
+
+	columns, err := selectExportColumns(query.Columns)
+	if err != nil {
+		return err
+	}
+
+	workbook := excelize.NewFile()
+	defer workbook.Close()
+
+	if err := workbook.SetSheetName(workbook.GetSheetName(0), xlsxExportSheet); err != nil {
+		return err
+	}
+
+	headerStyle, err := workbook.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true},
+		Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"DDEBF7"}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// One style per column, zero where the column has no number format.
+	styles := make([]int, len(columns))
+	for i, column := range columns {
+		if column.format == "" {
+			continue
+		}
+		format := column.format
+		if styles[i], err = workbook.NewStyle(&excelize.Style{CustomNumFmt: &format}); err != nil {
+			return err
+		}
+	}
+
+	stream, err := workbook.NewStreamWriter(xlsxExportSheet)
+	if err != nil {
+		return err
+	}
+
+	for i, column := range columns {
+		if err := stream.SetColWidth(i+1, i+1, column.width); err != nil {
+			return err
+		}
+	}
+	if err := stream.SetPanes(&excelize.Panes{
+		Freeze:      true,
+		YSplit:      1,
+		TopLeftCell: "A2",
+		ActivePane:  "bottomLeft",
+	}); err != nil {
+		return err
+	}
+
+	headerRow := make([]interface{}, len(columns))
+	for i, column := range columns {
+		headerRow[i] = excelize.Cell{StyleID: headerStyle, Value: exportHeader(column, query.Profile)}
+	}
+	if err := stream.SetRow("A1", headerRow); err != nil {
+		return err
+	}
+
+	location := s.dateParser.Location()
+	rowNumber := 1
+	err = s.filters.StreamVouchers(ctx, filter, func(voucher repository.Voucher) error {
+		rowNumber++
+		cell, err := excelize.CoordinatesToCellName(1, rowNumber)
+		if err != nil {
+			return err
+		}
+
+		row := make([]interface{}, len(columns))
+		for i, column := range columns {
+			row[i] = excelize.Cell{StyleID: styles[i], Value: column.cell(&voucher, location)}
+		}
+
+		return stream.SetRow(cell, row)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := stream.Flush(); err != nil {
+		return err
+	}
+
+	_, err = workbook.WriteTo(w)
+	return err
+}
@@ -0,0 +1,269 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alifdwt/techtest-indico-be/internal/apperror"
+	"github.com/alifdwt/techtest-indico-be/internal/dto"
+	"github.com/alifdwt/techtest-indico-be/internal/repository"
+	"github.com/alifdwt/techtest-indico-be/internal/repository/voucherfilter"
+	"github.com/alifdwt/techtest-indico-be/internal/util"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/xuri/excelize/v2"
+)
+
+// fakeImportTx stores the vouchers and the report written by an import.
+type fakeImportTx struct {
+	pgx.Tx
+
+	created []repository.Voucher
+	reasons []string
+}
+
+func (tx *fakeImportTx) Commit(context.Context) error { return nil }
+
+func (tx *fakeImportTx) Rollback(context.Context) error { return nil }
+
+func (tx *fakeImportTx) Exec(_ context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	switch {
+	case strings.HasPrefix(sql, "-- name: CreateOutboxEvent"):
+		return pgconn.NewCommandTag("INSERT 0 1"), nil
+	case strings.HasPrefix(sql, "-- name: CreateVoucherImportFailedRow"):
+		tx.reasons = append(tx.reasons, args[3].(string))
+		return pgconn.NewCommandTag("INSERT 0 1"), nil
+	}
+
+	return pgconn.CommandTag{}, errors.New("unexpected exec: " + sql)
+}
+
+func (tx *fakeImportTx) QueryRow(_ context.Context, sql string, args ...any) pgx.Row {
+	switch {
+	case strings.HasPrefix(sql, "-- name: CreateVoucher :one"):
+		voucher := repository.Voucher{
+			ID:              pgtype.UUID{Bytes: [16]byte{byte(len(tx.created) + 1)}, Valid: true},
+			VoucherCode:     args[0].(string),
+			DiscountPercent: args[1].(int32),
+			ExpiryDate:      args[2].(pgtype.Timestamptz),
+			Version:         1,
+		}
+		tx.created = append(tx.created, voucher)
+		return &fakeRows{values: [][]any{voucherValues(voucher)}}
+	case strings.HasPrefix(sql, "-- name: CreateVoucherImport :one"):
+		return &fakeRows{values: [][]any{{
+			pgtype.UUID{Bytes: [16]byte{9}, Valid: true}, args[0], args[1], args[2], args[3], pgtype.Timestamp{},
+		}}}
+	}
+
+	return &fakeRows{err: errors.New("unexpected query: " + sql)}
+}
+
+func newXLSXTestService(t *testing.T, tx *fakeImportTx, exported []repository.Voucher) *VoucherService {
+	t.Helper()
+
+	dateParser, err := util.NewDateParser(nil, "Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &VoucherService{
+		pool:       fakeTxBeginner{tx},
+		repo:       repository.New(tx),
+		filters:    voucherfilter.New(&fakeListDB{rows: exported}),
+		dateParser: dateParser,
+	}
+}
+
+// newTestWorkbook builds an xlsx file with a sheet per entry, in order.
+func newTestWorkbook(t *testing.T, sheets []string, rows map[string][][]any) *bytes.Buffer {
+	t.Helper()
+
+	workbook := excelize.NewFile()
+	defer workbook.Close()
+
+	for i, sheet := range sheets {
+		if i == 0 {
+			if err := workbook.SetSheetName(workbook.GetSheetName(0), sheet); err != nil {
+				t.Fatal(err)
+			}
+		} else if _, err := workbook.NewSheet(sheet); err != nil {
+			t.Fatal(err)
+		}
+
+		for j, row := range rows[sheet] {
+			cell, err := excelize.CoordinatesToCellName(1, j+1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := workbook.SetSheetRow(sheet, cell, &row); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if _, err := workbook.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	return &buf
+}
+
+func TestParseExcelDate(t *testing.T) {
+	dateParser, err := util.NewDateParser(nil, "Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	jakarta := dateParser.Location()
+
+	tests := []struct {
+		name     string
+		value    string
+		date1904 bool
+		want     time.Time
+		wantErr  string
+	}{
+		{name: "whole day", value: "45657", want: time.Date(2024, 12, 31, 23, 59, 59, 0, jakarta)},
+		{name: "with time", value: "45657.75", want: time.Date(2024, 12, 31, 18, 0, 0, 0, jakarta)},
+		{name: "noon", value: "45658.5", want: time.Date(2025, 1, 1, 12, 0, 0, 0, jakarta)},
+		{name: "1904 system", value: "44195", date1904: true, want: time.Date(2024, 12, 31, 23, 59, 59, 0, jakarta)},
+		{
+			name:    "not a number",
+			value:   "next friday",
+			wantErr: "expiry_date format is not valid. Use one of: YYYY-MM-DD, YYYY-MM-DD HH:mm:ss, DD/MM/YYYY, DD/MM/YYYY HH:mm:ss, RFC3339, or an Excel date cell",
+		},
+		{name: "negative", value: "-1", wantErr: "expiry_date is not a valid Excel date"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseExcelDate(dateParser, tt.value, tt.date1904)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+					t.Fatalf("parseExcelDate(%q) = %v, %v; want error %q", tt.value, got, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseExcelDate(%q) = %v", tt.value, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseExcelDate(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUploadXLSXSheets(t *testing.T) {
+	header := []any{"voucher_code", "discount_percent", "expiry_date"}
+	file := newTestWorkbook(t, []string{"Notes", "Vouchers"}, map[string][][]any{
+		"Notes":    {{"exported for the spring campaign"}},
+		"Vouchers": {header, {"SPRING10", 10, 45657}, {}, {"SPRING20", 20, "2024-12-31 10:30:00"}},
+	})
+	jakarta := time.FixedZone("WIB", 7*60*60)
+
+	tests := []struct {
+		name       string
+		sheet      string
+		wantErr    string
+		wantCodes  []string
+		wantExpiry []time.Time
+	}{
+		{name: "missing sheet", sheet: "Summary", wantErr: "sheet_not_found"},
+		{
+			name:       "named sheet",
+			sheet:      "Vouchers",
+			wantCodes:  []string{"SPRING10", "SPRING20"},
+			wantExpiry: []time.Time{time.Date(2024, 12, 31, 23, 59, 59, 0, jakarta), time.Date(2024, 12, 31, 10, 30, 0, 0, jakarta)},
+		},
+		// The first sheet holds notes, not a voucher header.
+		{name: "first sheet by default", wantErr: "invalid_import_file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := &fakeImportTx{}
+			s := newXLSXTestService(t, tx, nil)
+
+			res, err := s.UploadCSV(context.Background(), "vouchers.xlsx", bytes.NewReader(file.Bytes()), &dto.CSVUploadOptions{Sheet: tt.sheet})
+
+			if tt.wantErr != "" {
+				var appErr *apperror.Error
+				if !errors.As(err, &appErr) || appErr.Code != tt.wantErr {
+					t.Fatalf("UploadCSV() = %v, want %s", err, tt.wantErr)
+				}
+				if len(tx.created) != 0 {
+					t.Errorf("created %d vouchers, want none", len(tx.created))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if res.Format != importFormatXLSX || res.SuccessCount != len(tt.wantCodes) || res.FailedCount != 0 {
+				t.Errorf("response = %+v, want %d xlsx rows imported", res, len(tt.wantCodes))
+			}
+			if len(tx.created) != len(tt.wantCodes) {
+				t.Fatalf("created %d vouchers, want %d", len(tx.created), len(tt.wantCodes))
+			}
+			for i, voucher := range tx.created {
+				if voucher.VoucherCode != tt.wantCodes[i] || !voucher.ExpiryDate.Time.Equal(tt.wantExpiry[i]) {
+					t.Errorf("voucher %d = %s expiring %v, want %s expiring %v", i, voucher.VoucherCode, voucher.ExpiryDate.Time, tt.wantCodes[i], tt.wantExpiry[i])
+				}
+			}
+		})
+	}
+}
+
+func TestExportXLSXImportRoundTrip(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	exported := []repository.Voucher{
+		{
+			ID:              pgtype.UUID{Bytes: [16]byte{1}, Valid: true},
+			VoucherCode:     "SAVE10",
+			DiscountPercent: 10,
+			ExpiryDate:      pgtype.Timestamptz{Time: time.Date(2030, 1, 31, 23, 59, 59, 0, jakarta), Valid: true},
+			Version:         3,
+		},
+		{
+			ID:              pgtype.UUID{Bytes: [16]byte{2}, Valid: true},
+			VoucherCode:     "HALF-OFF",
+			DiscountPercent: 50,
+			ExpiryDate:      pgtype.Timestamptz{Time: time.Date(2030, 6, 1, 8, 15, 30, 0, time.UTC), Valid: true},
+			Version:         1,
+		},
+	}
+
+	tx := &fakeImportTx{}
+	s := newXLSXTestService(t, tx, exported)
+
+	var file bytes.Buffer
+	if err := s.ExportXLSX(context.Background(), &dto.VoucherExportQuery{Format: "xlsx", Profile: exportProfileImport}, &file); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := s.UploadCSV(context.Background(), "export.xlsx", &file, &dto.CSVUploadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.SuccessCount != len(exported) || res.FailedCount != 0 {
+		t.Fatalf("response = %+v, failed reasons %v; want every exported row imported", res, tx.reasons)
+	}
+	for i, voucher := range tx.created {
+		want := exported[i]
+		if voucher.VoucherCode != want.VoucherCode || voucher.DiscountPercent != want.DiscountPercent || !voucher.ExpiryDate.Time.Equal(want.ExpiryDate.Time) {
+			t.Errorf("imported %s %d%% expiring %v, want %s %d%% expiring %v",
+				voucher.VoucherCode, voucher.DiscountPercent, voucher.ExpiryDate.Time,
+				want.VoucherCode, want.DiscountPercent, want.ExpiryDate.Time)
+		}
+	}
+}
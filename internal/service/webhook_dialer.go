@@ -0,0 +1,94 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// errWebhookAddressBlocked reports a delivery to an address webhooks may
+// not reach.
+var errWebhookAddressBlocked = errors.New("webhook target address is not allowed")
+
+// sharedAddressSpace is the carrier-grade NAT range (RFC 6598), which is
+// internal much like the private ranges.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// webhookAddressGuard keeps webhook URLs from reaching internal services
+// (SSRF): connections to loopback, private, link-local and other
+// non-public addresses are refused. It checks the address actually dialed,
+// after DNS resolution, so a public name that resolves to a private address
+// is refused too. Allowed networks are let through, e.g. for a local stub.
+type webhookAddressGuard struct {
+	allowed []netip.Prefix
+}
+
+// newWebhookAddressGuard parses the allowed networks, given as CIDRs such
+// as 10.0.0.0/8 or as single addresses.
+func newWebhookAddressGuard(allowed []string) (*webhookAddressGuard, error) {
+	guard := &webhookAddressGuard{}
+	for _, value := range allowed {
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(value)
+			if addrErr != nil {
+				return nil, fmt.Errorf("invalid allowed webhook network '%s': %w", value, err)
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		guard.allowed = append(guard.allowed, prefix.Masked())
+	}
+
+	return guard, nil
+}
+
+// check returns an error when addr may not be reached.
+func (g *webhookAddressGuard) check(addr netip.Addr) error {
+	addr = addr.Unmap()
+	for _, prefix := range g.allowed {
+		if prefix.Contains(addr) {
+			return nil
+		}
+	}
+
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() ||
+		sharedAddressSpace.Contains(addr) {
+		return fmt.Errorf("%w: %s", errWebhookAddressBlocked, addr)
+	}
+
+	return nil
+}
+
+// control is the net.Dialer hook that runs the check right before every
+// connection, on the resolved address.
+func (g *webhookAddressGuard) control(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errWebhookAddressBlocked, address)
+	}
+
+	return g.check(addrPort.Addr())
+}
+
+// transport returns the HTTP transport of webhook deliveries, which only
+// dials addresses the guard allows.
+func (g *webhookAddressGuard) transport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   g.control,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would connect to the target itself, past the guard.
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return transport
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"errors"
+	"net/netip"
+	"testing"
+)
+
+func TestWebhookAddressGuard(t *testing.T) {
+	guard, err := newWebhookAddressGuard([]string{"127.0.0.1", "10.1.0.0/16"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		addr    string
+		allowed bool
+	}{
+		{"93.184.215.14", true},
+		{"2606:2800:21f:cb07:6820:80da:af6b:8b2c", true},
+		{"127.0.0.1", true},
+		{"127.0.0.2", false},
+		{"::1", false},
+		{"10.1.2.3", true},
+		{"10.2.0.1", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"0.0.0.0", false},
+		{"100.64.0.1", false},
+		{"224.0.0.1", false},
+		{"::ffff:192.168.1.1", false},
+		{"::ffff:127.0.0.1", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			err := guard.check(netip.MustParseAddr(tt.addr))
+			if tt.allowed && err != nil {
+				t.Errorf("check(%s) = %v, want allowed", tt.addr, err)
+			}
+			if !tt.allowed && !errors.Is(err, errWebhookAddressBlocked) {
+				t.Errorf("check(%s) = %v, want %v", tt.addr, err, errWebhookAddressBlocked)
+			}
+		})
+	}
+}
+
+func TestNewWebhookAddressGuardRejectsInvalidNetworks(t *testing.T) {
+	if _, err := newWebhookAddressGuard([]string{"localhost"}); err == nil {
+		t.Error("newWebhookAddressGuard(localhost) succeeded, want an error")
+	}
+}
@@ -0,0 +1,232 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/alifdwt/techtest-indico-be/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Headers sent with every webhook delivery. The signature is the hex
+// HMAC-SHA256, keyed with the subscription secret, of the timestamp, a dot
+// and the body.
+const (
+	WebhookEventIDHeader   = "X-Webhook-Id"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+const (
+	// webhookBatchSize is the number of deliveries claimed, and sent in
+	// parallel, at a time.
+	webhookBatchSize = 20
+	// webhookResponseLimit is how much of a response body is kept in the
+	// delivery log.
+	webhookResponseLimit = 1024
+	// webhookMaxRetryDelay caps the backoff between attempts.
+	webhookMaxRetryDelay = 24 * time.Hour
+)
+
+// Start runs the dispatcher in the background: every poll interval it
+// sends the deliveries that are due.
+func (s *WebhookService) Start() {
+	s.running.Add(1)
+	go func() {
+		defer s.running.Done()
+
+		ticker := time.NewTicker(s.cfg.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			s.dispatchDue(s.runCtx)
+
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops the dispatcher and waits for the deliveries being sent. When
+// ctx expires first they are cancelled; as they were never marked as done,
+// they are sent again once their lease runs out.
+func (s *WebhookService) Stop(ctx context.Context) error {
+	close(s.stop)
+
+	done := make(chan struct{})
+	go func() {
+		s.running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.cancelRuns()
+		return ctx.Err()
+	}
+}
+
+// dispatchDue sends the deliveries that are due, a batch at a time, until
+// none is left or the dispatcher stops.
+func (s *WebhookService) dispatchDue(ctx context.Context) {
+	// Long enough for a batch to be sent before other instances may claim
+	// its deliveries again.
+	lease := s.cfg.Timeout + time.Minute
+
+	for {
+		deliveries, err := s.repo.ClaimDueWebhookDeliveries(ctx, repository.ClaimDueWebhookDeliveriesParams{
+			Lease:      pgtype.Interval{Microseconds: lease.Microseconds(), Valid: true},
+			MaxResults: webhookBatchSize,
+		})
+		if err != nil {
+			log.Printf("webhooks: failed to claim deliveries: %v", err)
+			return
+		}
+
+		subscriptions := make(map[pgtype.UUID]*repository.WebhookSubscription)
+		var sending sync.WaitGroup
+		for _, delivery := range deliveries {
+			subscription, ok := subscriptions[delivery.SubscriptionID]
+			if !ok {
+				loaded, err := s.repo.GetWebhookSubscriptionByID(ctx, delivery.SubscriptionID)
+				if err != nil {
+					// Usually deleted meanwhile, together with its
+					// deliveries; otherwise the delivery is claimed again
+					// when its lease runs out.
+					continue
+				}
+				subscription = &loaded
+				subscriptions[delivery.SubscriptionID] = subscription
+			}
+
+			sending.Add(1)
+			go func() {
+				defer sending.Done()
+				s.deliver(ctx, subscription, &delivery)
+			}()
+		}
+		sending.Wait()
+
+		if len(deliveries) < webhookBatchSize {
+			return
+		}
+
+		select {
+		case <-s.stop:
+			return
+		default:
+		}
+	}
+}
+
+// deliver makes one attempt at a delivery, logs it, and marks the delivery
+// as succeeded, due for a retry, or dead when it was the last attempt.
+func (s *WebhookService) deliver(ctx context.Context, subscription *repository.WebhookSubscription, delivery *repository.WebhookDelivery) {
+	attempt := delivery.Attempts + 1
+
+	started := time.Now()
+	statusCode, responseBody, err := s.send(ctx, subscription, delivery)
+	duration := time.Since(started)
+
+	errMessage := ""
+	if err != nil {
+		errMessage = err.Error()
+	}
+
+	err = s.repo.CreateWebhookDeliveryAttempt(ctx, repository.CreateWebhookDeliveryAttemptParams{
+		DeliveryID:   delivery.ID,
+		Attempt:      attempt,
+		StatusCode:   int32(statusCode),
+		Error:        errMessage,
+		ResponseBody: responseBody,
+		DurationMs:   int32(duration.Milliseconds()),
+	})
+	if err != nil {
+		log.Printf("webhooks: failed to log attempt of delivery %s: %v", delivery.ID.String(), err)
+	}
+
+	params := repository.FinishWebhookDeliveryAttemptParams{
+		ID:            delivery.ID,
+		Status:        webhookSucceeded,
+		Attempts:      attempt,
+		NextAttemptAt: delivery.NextAttemptAt,
+		LastError:     errMessage,
+	}
+	if errMessage != "" {
+		if int(attempt) >= s.cfg.MaxAttempts {
+			params.Status = webhookDead
+		} else {
+			params.Status = webhookPending
+			nextAttemptAt := time.Now().Add(retryDelay(s.cfg.RetryBaseDelay, int(attempt), webhookMaxRetryDelay))
+			params.NextAttemptAt = pgtype.Timestamptz{Time: nextAttemptAt, Valid: true}
+		}
+	}
+
+	if err := s.repo.FinishWebhookDeliveryAttempt(ctx, params); err != nil {
+		log.Printf("webhooks: failed to record attempt of delivery %s: %v", delivery.ID.String(), err)
+	}
+}
+
+// send posts the payload of a delivery to the subscription URL. Any answer
+// other than 2xx is an error; the status code and the start of the body
+// are returned either way, for the delivery log.
+func (s *WebhookService) send(ctx context.Context, subscription *repository.WebhookSubscription, delivery *repository.WebhookDelivery) (int, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, "", err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "techtest-indico-be-webhooks/1.0")
+	req.Header.Set(WebhookEventIDHeader, uuid.UUID(delivery.EventID.Bytes).String())
+	req.Header.Set(WebhookEventHeader, delivery.EventType)
+	req.Header.Set(WebhookDeliveryHeader, uuid.UUID(delivery.ID.Bytes).String())
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookSignatureHeader, signWebhookPayload(subscription.Secret, timestamp, delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseLimit))
+	// Drain the rest so the connection can be reused.
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, string(body), fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, string(body), nil
+}
+
+// signWebhookPayload returns the X-Webhook-Signature of a payload sent at
+// timestamp. Signing the timestamp too lets receivers reject old requests
+// replayed with a valid signature.
+func signWebhookPayload(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(payload)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
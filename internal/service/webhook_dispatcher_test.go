@@ -0,0 +1,50 @@
+package service
+
+import "testing"
+
+func TestSignWebhookPayload(t *testing.T) {
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp int64
+		payload   string
+		want      string
+	}{
+		{
+			name:      "payload",
+			secret:    "whsec_test",
+			timestamp: 1700000000,
+			payload:   `{"id":"1"}`,
+			want:      "sha256=11bf4466ea17c3df3fd743af0b435368e16b7a05eb8eced85e8c4670767bdec5",
+		},
+		{
+			name:      "other timestamp",
+			secret:    "whsec_test",
+			timestamp: 1700000001,
+			payload:   `{"id":"1"}`,
+			want:      "sha256=b1feba12f212f2ce5192c1233a9427597af5f3d45ee6bb9cb6c30b434095284d",
+		},
+		{
+			name:      "other secret",
+			secret:    "other",
+			timestamp: 1700000000,
+			payload:   `{"id":"1"}`,
+			want:      "sha256=0c9dcd041b074d1b31727e0c1f821d11366e9db9f94c18bf202eb66cd0bd4d40",
+		},
+		{
+			name:      "empty payload",
+			secret:    "whsec_test",
+			timestamp: 0,
+			payload:   "",
+			want:      "sha256=a2fa7a43c6a1cf2e784eaf3327d65c65b3d2b790320ebed9aa5661bc42a8cccd",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := signWebhookPayload(tt.secret, tt.timestamp, []byte(tt.payload)); got != tt.want {
+				t.Errorf("signWebhookPayload() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
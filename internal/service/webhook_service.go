@@ -0,0 +1,396 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/alifdwt/techtest-indico-be/internal/apperror"
+	"github.com/alifdwt/techtest-indico-be/internal/config"
+	"github.com/alifdwt/techtest-indico-be/internal/dto"
+	"github.com/alifdwt/techtest-indico-be/internal/repository"
+	"github.com/alifdwt/techtest-indico-be/internal/util"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Webhook delivery statuses.
+const (
+	webhookPending   = "pending"
+	webhookSucceeded = "succeeded"
+	webhookDead      = "dead"
+)
+
+// WebhookService manages webhook subscriptions and delivers voucher events
+// to them. Events are stored as one delivery per subscription and sent by
+// a background dispatcher, which retries failed deliveries with
+// exponential backoff until they succeed or run out of attempts.
+type WebhookService struct {
+	pool   *pgxpool.Pool
+	repo   *repository.Queries
+	cfg    config.WebhookConfig
+	client *http.Client
+
+	// stop ends the dispatcher loop; runCtx is cancelled when shutdown
+	// gives up waiting for deliveries in flight.
+	stop       chan struct{}
+	runCtx     context.Context
+	cancelRuns context.CancelFunc
+	running    sync.WaitGroup
+}
+
+func NewWebhookService(pool *pgxpool.Pool, repo *repository.Queries, cfg config.WebhookConfig) (*WebhookService, error) {
+	if cfg.MaxAttempts < 1 {
+		cfg.MaxAttempts = 1
+	}
+
+	guard, err := newWebhookAddressGuard(cfg.AllowedNetworks)
+	if err != nil {
+		return nil, err
+	}
+
+	runCtx, cancelRuns := context.WithCancel(context.Background())
+
+	return &WebhookService{
+		pool: pool,
+		repo: repo,
+		cfg:  cfg,
+		client: &http.Client{
+			Timeout:   cfg.Timeout,
+			Transport: guard.transport(),
+			// A redirect counts as a failed delivery; following it would
+			// turn the POST into a GET.
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		stop:       make(chan struct{}),
+		runCtx:     runCtx,
+		cancelRuns: cancelRuns,
+	}, nil
+}
+
+// PublishVoucherEvent queues the event for every enabled subscription to
+// its type. It makes the webhook service the "webhook" sink of the outbox
+// relay. The deliveries are queued together, and an event published again
+// is not queued twice.
+func (s *WebhookService) PublishVoucherEvent(ctx context.Context, event *dto.VoucherEvent) error {
+	subscriptions, err := s.repo.ListWebhookSubscriptionsForEvent(ctx, event.Type)
+	if err != nil || len(subscriptions) == 0 {
+		return err
+	}
+
+	eventID, err := uuid.Parse(event.ID)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	repo := s.repo.WithTx(tx)
+
+	for _, subscription := range subscriptions {
+		err := repo.CreateWebhookDelivery(ctx, repository.CreateWebhookDeliveryParams{
+			SubscriptionID: subscription.ID,
+			EventID:        pgtype.UUID{Bytes: eventID, Valid: true},
+			VoucherID:      event.Data.ID,
+			EventType:      event.Type,
+			Payload:        payload,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (s *WebhookService) CreateSubscription(ctx context.Context, req *dto.WebhookSubscriptionRequest) (*dto.WebhookSubscriptionResponse, error) {
+	secret := req.Secret
+	if secret == "" {
+		var err error
+		if secret, err = generateWebhookSecret(); err != nil {
+			return nil, err
+		}
+	}
+
+	subscription, err := s.repo.CreateWebhookSubscription(ctx, repository.CreateWebhookSubscriptionParams{
+		Name:    strings.TrimSpace(req.Name),
+		Url:     req.URL,
+		Secret:  secret,
+		Events:  webhookEvents(req.Events),
+		Enabled: req.Enabled == nil || *req.Enabled,
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, webhookNameTaken(req.Name)
+		}
+		return nil, err
+	}
+
+	res := toWebhookSubscriptionResponse(&subscription)
+	res.Secret = subscription.Secret
+	return res, nil
+}
+
+func (s *WebhookService) ListSubscriptions(ctx context.Context, query *dto.PaginationQuery) ([]*dto.WebhookSubscriptionResponse, util.Meta, error) {
+	subscriptions, err := s.repo.ListWebhookSubscriptions(ctx, repository.ListWebhookSubscriptionsParams{
+		Limit:  int32(query.Limit),
+		Offset: int32((query.Page - 1) * query.Limit),
+	})
+	if err != nil {
+		return nil, util.Meta{}, err
+	}
+
+	total, err := s.repo.CountWebhookSubscriptions(ctx)
+	if err != nil {
+		return nil, util.Meta{}, err
+	}
+
+	responses := []*dto.WebhookSubscriptionResponse{}
+	for _, subscription := range subscriptions {
+		responses = append(responses, toWebhookSubscriptionResponse(&subscription))
+	}
+
+	return responses, util.NewMeta(total, query.Page, query.Limit), nil
+}
+
+func (s *WebhookService) GetSubscriptionByID(ctx context.Context, id string) (*dto.WebhookSubscriptionResponse, error) {
+	subscription, err := s.getSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toWebhookSubscriptionResponse(subscription), nil
+}
+
+// UpdateSubscription replaces a subscription. Without a secret in the
+// request the current secret is kept.
+func (s *WebhookService) UpdateSubscription(ctx context.Context, id string, req *dto.WebhookSubscriptionRequest) (*dto.WebhookSubscriptionResponse, error) {
+	subscriptionID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, apperror.InvalidID("invalid_webhook_id", "invalid webhook id")
+	}
+
+	subscription, err := s.repo.UpdateWebhookSubscription(ctx, repository.UpdateWebhookSubscriptionParams{
+		ID:      pgtype.UUID{Bytes: subscriptionID, Valid: true},
+		Name:    strings.TrimSpace(req.Name),
+		Url:     req.URL,
+		Secret:  pgtype.Text{String: req.Secret, Valid: req.Secret != ""},
+		Events:  webhookEvents(req.Events),
+		Enabled: req.Enabled == nil || *req.Enabled,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, webhookNotFound()
+		}
+		if isUniqueViolation(err) {
+			return nil, webhookNameTaken(req.Name)
+		}
+		return nil, err
+	}
+
+	res := toWebhookSubscriptionResponse(&subscription)
+	if req.Secret != "" {
+		res.Secret = subscription.Secret
+	}
+	return res, nil
+}
+
+// DeleteSubscription deletes a subscription together with its deliveries.
+func (s *WebhookService) DeleteSubscription(ctx context.Context, id string) error {
+	subscription, err := s.getSubscription(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.DeleteWebhookSubscription(ctx, subscription.ID)
+}
+
+func (s *WebhookService) ListDeliveries(ctx context.Context, id string, query *dto.WebhookDeliveryQuery) ([]*dto.WebhookDeliveryResponse, util.Meta, error) {
+	subscription, err := s.getSubscription(ctx, id)
+	if err != nil {
+		return nil, util.Meta{}, err
+	}
+
+	status := pgtype.Text{String: query.Status, Valid: query.Status != ""}
+	deliveries, err := s.repo.ListWebhookDeliveries(ctx, repository.ListWebhookDeliveriesParams{
+		SubscriptionID: subscription.ID,
+		Status:         status,
+		Limit:          int32(query.Limit),
+		Offset:         int32((query.Page - 1) * query.Limit),
+	})
+	if err != nil {
+		return nil, util.Meta{}, err
+	}
+
+	total, err := s.repo.CountWebhookDeliveries(ctx, repository.CountWebhookDeliveriesParams{
+		SubscriptionID: subscription.ID,
+		Status:         status,
+	})
+	if err != nil {
+		return nil, util.Meta{}, err
+	}
+
+	responses := []*dto.WebhookDeliveryResponse{}
+	for _, delivery := range deliveries {
+		responses = append(responses, toWebhookDeliveryResponse(&delivery))
+	}
+
+	return responses, util.NewMeta(total, query.Page, query.Limit), nil
+}
+
+// GetDelivery returns a delivery with the log of its attempts.
+func (s *WebhookService) GetDelivery(ctx context.Context, id, deliveryID string) (*dto.WebhookDeliveryResponse, error) {
+	delivery, err := s.getDelivery(ctx, id, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	attempts, err := s.repo.ListWebhookDeliveryAttempts(ctx, delivery.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := toWebhookDeliveryResponse(delivery)
+	res.Log = []dto.WebhookDeliveryAttemptResponse{}
+	for _, attempt := range attempts {
+		res.Log = append(res.Log, dto.WebhookDeliveryAttemptResponse{
+			Attempt:      int(attempt.Attempt),
+			StatusCode:   int(attempt.StatusCode),
+			Error:        attempt.Error,
+			ResponseBody: attempt.ResponseBody,
+			DurationMS:   int(attempt.DurationMs),
+			AttemptedAt:  attempt.AttemptedAt.Time,
+		})
+	}
+
+	return res, nil
+}
+
+// RedeliverDelivery sends the event of a delivery again, whatever became
+// of it, as a new delivery with a fresh set of attempts. Receivers can
+// recognise the repeat by its unchanged event ID.
+func (s *WebhookService) RedeliverDelivery(ctx context.Context, id, deliveryID string) (*dto.WebhookDeliveryResponse, error) {
+	delivery, err := s.getDelivery(ctx, id, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	redelivery, err := s.repo.CreateWebhookRedelivery(ctx, delivery.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toWebhookDeliveryResponse(&redelivery), nil
+}
+
+func (s *WebhookService) getSubscription(ctx context.Context, id string) (*repository.WebhookSubscription, error) {
+	subscriptionID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, apperror.InvalidID("invalid_webhook_id", "invalid webhook id")
+	}
+
+	subscription, err := s.repo.GetWebhookSubscriptionByID(ctx, pgtype.UUID{Bytes: subscriptionID, Valid: true})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, webhookNotFound()
+		}
+		return nil, err
+	}
+
+	return &subscription, nil
+}
+
+func (s *WebhookService) getDelivery(ctx context.Context, id, deliveryID string) (*repository.WebhookDelivery, error) {
+	subscription, err := s.getSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedID, err := uuid.Parse(deliveryID)
+	if err != nil {
+		return nil, apperror.InvalidID("invalid_webhook_delivery_id", "invalid webhook delivery id")
+	}
+
+	delivery, err := s.repo.GetWebhookDelivery(ctx, repository.GetWebhookDeliveryParams{
+		ID:             pgtype.UUID{Bytes: parsedID, Valid: true},
+		SubscriptionID: subscription.ID,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, apperror.NotFound("webhook_delivery_not_found", "webhook delivery not found")
+		}
+		return nil, err
+	}
+
+	return &delivery, nil
+}
+
+// webhookEvents sorts the subscribed event types and drops repeats.
+func webhookEvents(events []string) []string {
+	events = slices.Clone(events)
+	slices.Sort(events)
+	return slices.Compact(events)
+}
+
+func generateWebhookSecret() (string, error) {
+	key := make([]byte, 24)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+
+	return "whsec_" + hex.EncodeToString(key), nil
+}
+
+func toWebhookSubscriptionResponse(subscription *repository.WebhookSubscription) *dto.WebhookSubscriptionResponse {
+	return &dto.WebhookSubscriptionResponse{
+		ID:        subscription.ID,
+		Name:      subscription.Name,
+		URL:       subscription.Url,
+		Events:    subscription.Events,
+		Enabled:   subscription.Enabled,
+		CreatedAt: subscription.CreatedAt.Time,
+		UpdatedAt: subscription.UpdatedAt.Time,
+	}
+}
+
+func toWebhookDeliveryResponse(delivery *repository.WebhookDelivery) *dto.WebhookDeliveryResponse {
+	res := &dto.WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		SubscriptionID: delivery.SubscriptionID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Status:         delivery.Status,
+		Attempts:       int(delivery.Attempts),
+		LastError:      delivery.LastError,
+		Payload:        delivery.Payload,
+		CreatedAt:      delivery.CreatedAt.Time,
+	}
+	if delivery.Status == webhookPending {
+		nextAttemptAt := delivery.NextAttemptAt.Time
+		res.NextAttemptAt = &nextAttemptAt
+	}
+	if delivery.CompletedAt.Valid {
+		completedAt := delivery.CompletedAt.Time
+		res.CompletedAt = &completedAt
+	}
+
+	return res
+}
@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// LocalStorage writes objects as files below a base directory.
+type LocalStorage struct {
+	dir string
+}
+
+func NewLocalStorage(dir string) *LocalStorage {
+	return &LocalStorage{
+		dir: dir,
+	}
+}
+
+// Put writes to a temporary file next to the target and renames it once
+// complete, so a half written export is never visible under its final name.
+func (l *LocalStorage) Put(ctx context.Context, key string, contentType string, r io.Reader) (string, error) {
+	if !filepath.IsLocal(key) {
+		return "", fmt.Errorf("invalid object key '%s'", key)
+	}
+
+	path := filepath.Join(l.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
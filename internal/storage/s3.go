@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/alifdwt/techtest-indico-be/internal/config"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// s3PartSize bounds the memory used while uploading an export of unknown
+// size; the client buffers one part at a time.
+const s3PartSize = 16 << 20
+
+// S3Storage uploads objects to an S3-compatible bucket, such as AWS S3 or
+// MinIO.
+type S3Storage struct {
+	client *minio.Client
+	bucket string
+}
+
+func NewS3Storage(cfg config.S3Config) (*S3Storage, error) {
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure: cfg.UseSSL,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid s3 storage config: %w", err)
+	}
+
+	return &S3Storage{
+		client: client,
+		bucket: cfg.Bucket,
+	}, nil
+}
+
+func (s *S3Storage) Put(ctx context.Context, key string, contentType string, r io.Reader) (string, error) {
+	_, err := s.client.PutObject(ctx, s.bucket, key, r, -1, minio.PutObjectOptions{
+		ContentType: contentType,
+		PartSize:    s3PartSize,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("s3://%s/%s", s.bucket, key), nil
+}
@@ -0,0 +1,39 @@
+package storage
+
+import (
+	"context"
+	"io"
+
+	"github.com/alifdwt/techtest-indico-be/internal/config"
+)
+
+const (
+	BackendLocal = "local"
+	BackendS3    = "s3"
+)
+
+// Storage is a place finished export files are written to.
+type Storage interface {
+	// Put stores everything read from r under key and returns where the
+	// object ended up, e.g. a file path or an s3:// URL.
+	Put(ctx context.Context, key string, contentType string, r io.Reader) (string, error)
+}
+
+// NewBackends builds every storage backend available with the given config,
+// keyed by backend name. The local backend is always available; the s3
+// backend only when an endpoint and bucket are configured.
+func NewBackends(cfg config.ExportConfig) (map[string]Storage, error) {
+	backends := map[string]Storage{
+		BackendLocal: NewLocalStorage(cfg.LocalDir),
+	}
+
+	if cfg.S3.Endpoint != "" && cfg.S3.Bucket != "" {
+		s3, err := NewS3Storage(cfg.S3)
+		if err != nil {
+			return nil, err
+		}
+		backends[BackendS3] = s3
+	}
+
+	return backends, nil
+}
@@ -0,0 +1,184 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// dateFormats maps the human friendly names accepted in configuration to Go
+// time layouts. Layouts without a time part are date-only.
+var dateFormats = map[string]string{
+	"YYYY-MM-DD":          "2006-01-02",
+	"YYYY-MM-DD HH:mm":    "2006-01-02 15:04",
+	"YYYY-MM-DD HH:mm:ss": "2006-01-02 15:04:05",
+	"DD/MM/YYYY":          "02/01/2006",
+	"DD/MM/YYYY HH:mm":    "02/01/2006 15:04",
+	"DD/MM/YYYY HH:mm:ss": "02/01/2006 15:04:05",
+	"DD-MM-YYYY":          "02-01-2006",
+	"RFC3339":             time.RFC3339,
+}
+
+var DefaultDateFormats = []string{
+	"YYYY-MM-DD",
+	"YYYY-MM-DD HH:mm:ss",
+	"DD/MM/YYYY",
+	"DD/MM/YYYY HH:mm:ss",
+	"RFC3339",
+}
+
+type dateLayout struct {
+	name     string
+	layout   string
+	dateOnly bool
+}
+
+// DateParser parses user supplied dates using a fixed list of accepted
+// formats. Values without an explicit offset are read in the parser's
+// location, and date-only expiries mean the end of that day.
+type DateParser struct {
+	layouts  []dateLayout
+	location *time.Location
+}
+
+func NewDateParser(formats []string, timezone string) (*DateParser, error) {
+	if len(formats) == 0 {
+		formats = DefaultDateFormats
+	}
+
+	location, err := LoadTimezone(timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := &DateParser{location: location}
+	for _, format := range formats {
+		format = strings.TrimSpace(format)
+		layout, ok := dateFormats[format]
+		if !ok {
+			// Allow raw Go layouts for formats that have no friendly name.
+			if !strings.Contains(format, "2006") {
+				return nil, fmt.Errorf("unknown date format '%s'", format)
+			}
+			layout = format
+		}
+
+		parser.layouts = append(parser.layouts, dateLayout{
+			name:     format,
+			layout:   layout,
+			dateOnly: !hasTimeOfDay(layout),
+		})
+	}
+
+	return parser, nil
+}
+
+func hasTimeOfDay(layout string) bool {
+	return strings.Contains(layout, "15") || strings.Contains(layout, "03") || strings.Contains(layout, ":04")
+}
+
+func LoadTimezone(timezone string) (*time.Location, error) {
+	if timezone == "" {
+		timezone = "Asia/Jakarta"
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone '%s'", timezone)
+	}
+
+	return location, nil
+}
+
+// WithTimezone returns a copy of the parser that reads dates in another
+// timezone, e.g. for a single import.
+func (p *DateParser) WithTimezone(timezone string) (*DateParser, error) {
+	location, err := LoadTimezone(timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DateParser{layouts: p.layouts, location: location}, nil
+}
+
+func (p *DateParser) Location() *time.Location {
+	return p.location
+}
+
+// ParseExpiry parses an expiry date. A date without a time, like
+// "2024-12-31", expires at 23:59:59 of that day in the parser's timezone.
+func (p *DateParser) ParseExpiry(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+
+	for _, layout := range p.layouts {
+		parsed, err := time.ParseInLocation(layout.layout, value, p.location)
+		if err != nil {
+			continue
+		}
+
+		if layout.dateOnly {
+			return p.FromWallClock(parsed, true), nil
+		}
+		return parsed, nil
+	}
+
+	return time.Time{}, fmt.Errorf("expiry_date format is not valid. Use one of: %s", p.FormatNames())
+}
+
+// ParseRangeBound parses one end of a date range. A date without a time
+// covers the whole day: it means the start of the day as a lower bound and
+// its last instant as an upper bound.
+func (p *DateParser) ParseRangeBound(value string, upper bool) (time.Time, error) {
+	value = strings.TrimSpace(value)
+
+	for _, layout := range p.layouts {
+		parsed, err := time.ParseInLocation(layout.layout, value, p.location)
+		if err != nil {
+			continue
+		}
+
+		if layout.dateOnly && upper {
+			return parsed.AddDate(0, 0, 1).Add(-time.Microsecond), nil
+		}
+		return parsed, nil
+	}
+
+	return time.Time{}, fmt.Errorf("date format is not valid. Use one of: %s", p.FormatNames())
+}
+
+// FromWallClock reads the calendar date and clock time of t, ignoring its
+// location, as a time in the parser's timezone. It is used for values that
+// carry no timezone at all, such as spreadsheet date cells.
+func (p *DateParser) FromWallClock(t time.Time, dateOnly bool) time.Time {
+	year, month, day := t.Date()
+	if dateOnly {
+		return time.Date(year, month, day, 23, 59, 59, 0, p.location)
+	}
+
+	hour, minute, second := t.Clock()
+	return time.Date(year, month, day, hour, minute, second, 0, p.location)
+}
+
+// Format renders t in the parser's timezone with the first accepted format
+// that has a time of day, so the value parses back to the same instant. When
+// only date-only formats are accepted, the first one is used.
+func (p *DateParser) Format(t time.Time) string {
+	layout := p.layouts[0]
+	for _, candidate := range p.layouts {
+		if !candidate.dateOnly {
+			layout = candidate
+			break
+		}
+	}
+
+	return t.In(p.location).Format(layout.layout)
+}
+
+func (p *DateParser) FormatNames() string {
+	names := make([]string, 0, len(p.layouts))
+	for _, layout := range p.layouts {
+		names = append(names, layout.name)
+	}
+
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,244 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func mustDateParser(t *testing.T, formats []string, timezone string) *DateParser {
+	t.Helper()
+
+	parser, err := NewDateParser(formats, timezone)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return parser
+}
+
+func mustLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return location
+}
+
+func TestNewDateParser(t *testing.T) {
+	tests := []struct {
+		name     string
+		formats  []string
+		timezone string
+		wantErr  string
+	}{
+		{name: "defaults"},
+		{name: "friendly names", formats: []string{"DD-MM-YYYY", " YYYY-MM-DD HH:mm "}, timezone: "UTC"},
+		{name: "go layout", formats: []string{"Jan 2, 2006"}},
+		{name: "unknown format", formats: []string{"MM/DD/YYYY"}, wantErr: "unknown date format 'MM/DD/YYYY'"},
+		{name: "unknown timezone", timezone: "Mars/Olympus", wantErr: "unknown timezone 'Mars/Olympus'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewDateParser(tt.formats, tt.timezone)
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("NewDateParser() = %v, want no error", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("NewDateParser() = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseExpiry(t *testing.T) {
+	jakarta := mustLocation(t, "Asia/Jakarta")
+	parser := mustDateParser(t, nil, "")
+
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"2024-12-31", time.Date(2024, 12, 31, 23, 59, 59, 0, jakarta)},
+		{"  2024-12-31 ", time.Date(2024, 12, 31, 23, 59, 59, 0, jakarta)},
+		{"2024-12-31 10:30:00", time.Date(2024, 12, 31, 10, 30, 0, 0, jakarta)},
+		{"31/12/2024", time.Date(2024, 12, 31, 23, 59, 59, 0, jakarta)},
+		{"31/12/2024 00:00:00", time.Date(2024, 12, 31, 0, 0, 0, 0, jakarta)},
+		{"2024-12-31T10:30:00Z", time.Date(2024, 12, 31, 10, 30, 0, 0, time.UTC)},
+		{"2024-12-31T10:30:00+09:00", time.Date(2024, 12, 31, 1, 30, 0, 0, time.UTC)},
+		{"2024-02-29", time.Date(2024, 2, 29, 23, 59, 59, 0, jakarta)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parser.ParseExpiry(tt.value)
+			if err != nil {
+				t.Fatalf("ParseExpiry(%q) = %v", tt.value, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseExpiry(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseExpiryRejects(t *testing.T) {
+	parser := mustDateParser(t, nil, "")
+	const message = "expiry_date format is not valid. Use one of: YYYY-MM-DD, YYYY-MM-DD HH:mm:ss, DD/MM/YYYY, DD/MM/YYYY HH:mm:ss, RFC3339"
+
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"month first", "12/31/2024"},
+		{"unpadded day and month", "1/2/2024"},
+		{"two digit year", "31/12/24"},
+		{"slashes year first", "2024/12/31"},
+		{"format not configured", "31-12-2024"},
+		{"time without seconds", "2024-12-31 10:30"},
+		{"rfc3339 without offset", "2024-12-31T10:30:00"},
+		{"no such day", "2023-02-29"},
+		{"no such month", "2024-13-01"},
+		{"words", "next friday"},
+		{"empty", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parser.ParseExpiry(tt.value)
+			if err == nil {
+				t.Fatalf("ParseExpiry(%q) = %v, want an error", tt.value, got)
+			}
+			if err.Error() != message {
+				t.Errorf("ParseExpiry(%q) error = %q, want %q", tt.value, err, message)
+			}
+		})
+	}
+}
+
+func TestParseExpiryConfiguredFormats(t *testing.T) {
+	parser := mustDateParser(t, []string{"DD-MM-YYYY", "YYYY-MM-DD HH:mm", "Jan 2, 2006"}, "UTC")
+
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{value: "31-12-2024", want: time.Date(2024, 12, 31, 23, 59, 59, 0, time.UTC)},
+		{value: "2024-12-31 10:30", want: time.Date(2024, 12, 31, 10, 30, 0, 0, time.UTC)},
+		{value: "Dec 31, 2024", want: time.Date(2024, 12, 31, 23, 59, 59, 0, time.UTC)},
+		{value: "2024-12-31", wantErr: true},
+		{value: "2024-12-31T10:30:00Z", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parser.ParseExpiry(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseExpiry(%q) = %v, want an error", tt.value, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseExpiry(%q) = %v", tt.value, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseExpiry(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDateParserWithTimezone(t *testing.T) {
+	parser := mustDateParser(t, nil, "Asia/Jakarta")
+	newYork := mustLocation(t, "America/New_York")
+
+	tests := []struct {
+		timezone string
+		value    string
+		want     time.Time
+	}{
+		{"UTC", "2024-12-31", time.Date(2024, 12, 31, 23, 59, 59, 0, time.UTC)},
+		{"America/New_York", "2024-07-01 08:00:00", time.Date(2024, 7, 1, 8, 0, 0, 0, newYork)},
+		{"America/New_York", "31/12/2024", time.Date(2024, 12, 31, 23, 59, 59, 0, newYork)},
+		// An explicit offset wins over the timezone.
+		{"America/New_York", "2024-12-31T10:30:00+07:00", time.Date(2024, 12, 31, 3, 30, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.timezone+" "+tt.value, func(t *testing.T) {
+			override, err := parser.WithTimezone(tt.timezone)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := override.ParseExpiry(tt.value)
+			if err != nil {
+				t.Fatalf("ParseExpiry(%q) = %v", tt.value, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseExpiry(%q) in %s = %v, want %v", tt.value, tt.timezone, got, tt.want)
+			}
+		})
+	}
+
+	if parser.Location().String() != "Asia/Jakarta" {
+		t.Errorf("original parser location = %s, want Asia/Jakarta", parser.Location())
+	}
+	if _, err := parser.WithTimezone("Mars/Olympus"); err == nil {
+		t.Error("WithTimezone(Mars/Olympus) = nil, want an error")
+	}
+}
+
+func TestParseRangeBound(t *testing.T) {
+	parser := mustDateParser(t, nil, "UTC")
+
+	tests := []struct {
+		value string
+		upper bool
+		want  time.Time
+	}{
+		{"2024-12-31", false, time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{"2024-12-31", true, time.Date(2024, 12, 31, 23, 59, 59, 999999000, time.UTC)},
+		{"2024-12-31 10:30:00", false, time.Date(2024, 12, 31, 10, 30, 0, 0, time.UTC)},
+		{"2024-12-31 10:30:00", true, time.Date(2024, 12, 31, 10, 30, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parser.ParseRangeBound(tt.value, tt.upper)
+			if err != nil {
+				t.Fatalf("ParseRangeBound(%q, %v) = %v", tt.value, tt.upper, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseRangeBound(%q, %v) = %v, want %v", tt.value, tt.upper, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDateParserFormatRoundTrip(t *testing.T) {
+	parser := mustDateParser(t, nil, "Asia/Jakarta")
+	instant := time.Date(2024, 12, 31, 16, 59, 59, 0, time.UTC)
+
+	formatted := parser.Format(instant)
+	if formatted != "2024-12-31 23:59:59" {
+		t.Errorf("Format() = %q, want %q", formatted, "2024-12-31 23:59:59")
+	}
+
+	parsed, err := parser.ParseExpiry(formatted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !parsed.Equal(instant) {
+		t.Errorf("ParseExpiry(Format()) = %v, want %v", parsed, instant)
+	}
+}
@@ -0,0 +1,37 @@
+package util
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ETag returns the entity tag of a resource version, e.g. "3".
+func ETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// MatchETag reports whether the entity tag list of an If-Match or
+// If-None-Match header contains etag; "*" matches any tag. If-Match uses
+// the strong comparison, under which weak tags such as W/"3" never match,
+// and If-None-Match the weak one, which ignores the W/ prefix.
+func MatchETag(header, etag string, weak bool) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return true
+		}
+
+		if strings.HasPrefix(tag, "W/") {
+			if !weak {
+				continue
+			}
+			tag = strings.TrimPrefix(tag, "W/")
+		}
+
+		if tag == etag {
+			return true
+		}
+	}
+
+	return false
+}
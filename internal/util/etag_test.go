@@ -0,0 +1,34 @@
+package util
+
+import "testing"
+
+func TestMatchETag(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		etag   string
+		weak   bool
+		want   bool
+	}{
+		{"same tag", `"3"`, `"3"`, false, true},
+		{"other tag", `"4"`, `"3"`, false, false},
+		{"any tag", `*`, `"3"`, false, true},
+		{"list", `"1", "3"`, `"3"`, false, true},
+		{"list without spaces", `"1","3"`, `"3"`, false, true},
+		{"list without tag", `"1", "2"`, `"3"`, false, false},
+		{"weak tag, strong comparison", `W/"3"`, `"3"`, false, false},
+		{"weak tag, weak comparison", `W/"3"`, `"3"`, true, true},
+		{"strong tag, weak comparison", `"3"`, `"3"`, true, true},
+		{"weak tag in list", `W/"1", W/"3"`, `"3"`, true, true},
+		{"unquoted tag", `3`, `"3"`, false, false},
+		{"empty header", ``, `"3"`, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchETag(tt.header, tt.etag, tt.weak); got != tt.want {
+				t.Errorf("MatchETag(%q, %q, %v) = %v, want %v", tt.header, tt.etag, tt.weak, got, tt.want)
+			}
+		})
+	}
+}
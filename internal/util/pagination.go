@@ -0,0 +1,56 @@
+package util
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// paginationLinks renders an RFC 8288 Link header value pointing at the
+// first, previous, next and last pages. The links repeat the request's
+// query string so filters and sorting carry over. Cursor pages link to
+// their neighbours by cursor and have no last page.
+func paginationLinks(requestURL *url.URL, meta Meta) string {
+	if requestURL == nil {
+		return ""
+	}
+
+	link := func(rel string, set func(url.Values)) string {
+		query := requestURL.Query()
+		query.Del("page")
+		query.Del("cursor")
+		set(query)
+
+		target := url.URL{Path: requestURL.Path, RawQuery: query.Encode()}
+		return fmt.Sprintf(`<%s>; rel="%s"`, target.String(), rel)
+	}
+	page := func(number int) func(url.Values) {
+		return func(query url.Values) { query.Set("page", strconv.Itoa(number)) }
+	}
+	cursor := func(value string) func(url.Values) {
+		return func(query url.Values) { query.Set("cursor", value) }
+	}
+
+	links := []string{link("first", page(1))}
+	if meta.Page == 0 {
+		if meta.HasPrev && meta.PrevCursor != "" {
+			links = append(links, link("prev", cursor(meta.PrevCursor)))
+		}
+		if meta.HasNext && meta.NextCursor != "" {
+			links = append(links, link("next", cursor(meta.NextCursor)))
+		}
+	} else {
+		if meta.HasPrev {
+			links = append(links, link("prev", page(meta.Page-1)))
+		}
+		if meta.HasNext {
+			links = append(links, link("next", page(meta.Page+1)))
+		}
+		if meta.TotalPages > 0 {
+			links = append(links, link("last", page(meta.TotalPages)))
+		}
+	}
+
+	return strings.Join(links, ", ")
+}
@@ -0,0 +1,28 @@
+package util
+
+import (
+	"github.com/alifdwt/techtest-indico-be/internal/apperror"
+	"github.com/gin-gonic/gin"
+)
+
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details body. Code is a stable identifier
+// clients can branch on; Errors lists invalid fields for validation errors.
+type Problem struct {
+	Type      string                `json:"type"`
+	Title     string                `json:"title"`
+	Status    int                   `json:"status"`
+	Detail    string                `json:"detail,omitempty"`
+	Instance  string                `json:"instance,omitempty"`
+	Code      string                `json:"code"`
+	RequestID string                `json:"request_id,omitempty"`
+	Errors    []apperror.FieldError `json:"errors,omitempty"`
+}
+
+// ProblemResponse writes the problem as application/problem+json; gin keeps
+// a content type that is already set.
+func ProblemResponse(ctx *gin.Context, problem Problem) {
+	ctx.Header("Content-Type", ProblemContentType)
+	ctx.JSON(problem.Status, problem)
+}
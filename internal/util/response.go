@@ -8,10 +8,36 @@ type Response struct {
 	Data    any    `json:"data,omitempty"`
 }
 
+// Meta describes the page returned by a list endpoint. Page is omitted for
+// pages fetched by cursor, which have no page number.
 type Meta struct {
-	Total int `json:"total"`
-	Page  int `json:"page"`
-	Limit int `json:"limit"`
+	Total      int  `json:"total"`
+	Page       int  `json:"page,omitempty"`
+	Limit      int  `json:"limit"`
+	TotalPages int  `json:"total_pages"`
+	HasNext    bool `json:"has_next"`
+	HasPrev    bool `json:"has_prev"`
+	// NextCursor, PrevCursor are set by endpoints that support keyset
+	// pagination.
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+// NewMeta builds the metadata of a numbered page.
+func NewMeta(total int64, page, limit int) Meta {
+	totalPages := 0
+	if limit > 0 {
+		totalPages = int((total + int64(limit) - 1) / int64(limit))
+	}
+
+	return Meta{
+		Total:      int(total),
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages,
+		HasNext:    page < totalPages,
+		HasPrev:    page > 1,
+	}
 }
 
 type PaginatedResponse struct {
@@ -29,22 +55,17 @@ func SuccessResponse(ctx *gin.Context, statusCode int, message string, data inte
 	})
 }
 
-func ErrorResponse(ctx *gin.Context, statusCode int, message string) {
-	ctx.JSON(statusCode, Response{
-		Success: false,
-		Message: message,
-	})
-}
+// PaginatedSuccessResponse writes a page of a list together with its
+// metadata, and links to the neighbouring pages in the Link header.
+func PaginatedSuccessResponse(ctx *gin.Context, statusCode int, message string, data interface{}, meta Meta) {
+	if link := paginationLinks(ctx.Request.URL, meta); link != "" {
+		ctx.Writer.Header().Add("Link", link)
+	}
 
-func PaginatedSuccessResponse(ctx *gin.Context, statusCode int, message string, data interface{}, total, page, limit int) {
 	ctx.JSON(statusCode, PaginatedResponse{
 		Success: true,
 		Message: message,
 		Data:    data,
-		Meta: Meta{
-			Total: total,
-			Page:  page,
-			Limit: limit,
-		},
+		Meta:    meta,
 	})
 }